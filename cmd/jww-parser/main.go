@@ -5,6 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/f4ah6o/jww-parser/dxf"
 	"github.com/f4ah6o/jww-parser/jww"
@@ -14,6 +16,21 @@ func main() {
 	outputDxf := flag.Bool("dxf", false, "Output DXF format")
 	outputFile := flag.String("o", "", "Output file (default: stdout)")
 	verbose := flag.Bool("v", false, "Verbose output")
+	explodeDims := flag.Bool("explode-dimensions", false, "Write dimensions as separate lines and texts instead of DIMENSION entities")
+	explodeHatching := flag.Bool("explode-hatching", false, "Write hatching as the lines drawn instead of pattern HATCH entities")
+	attributeLayers := flag.Bool("attribute-layers", false, "Move hatching, dimension, fitting and figure entities to sub-layers named after their attribute")
+	mode := flag.String("mode", "standard", "Parse mode: standard, lenient (skip damaged data with warnings) or strict (reject suspicious data)")
+	solidFill := flag.String("solid-fill", "hatch", "Write solids as hatch (one HATCH each), merged (adjacent solids in one HATCH) or solid (SOLID entities)")
+	stream := flag.Bool("stream", false, "Convert to DXF while reading the file, without holding the whole drawing in memory (for very large files)")
+	fontMap := make(map[string]string)
+	flag.Func("font", "Map a JWW font to a DXF font file, as NAME=FILE (repeatable)", func(s string) error {
+		name, file, ok := strings.Cut(s, "=")
+		if !ok || name == "" || file == "" {
+			return fmt.Errorf("expected NAME=FILE, got %q", s)
+		}
+		fontMap[name] = file
+		return nil
+	})
 	flag.Parse()
 
 	if flag.NArg() < 1 {
@@ -33,11 +50,68 @@ func main() {
 	defer f.Close()
 
 	// Parse JWW file
-	doc, err := jww.Parse(f)
+	var parseOpts jww.ParseOptions
+	switch *mode {
+	case "standard":
+	case "lenient":
+		parseOpts.Mode = jww.Lenient
+	case "strict":
+		parseOpts.Mode = jww.Strict
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown -mode %q\n", *mode)
+		os.Exit(2)
+	}
+
+	// Auto-enable DXF output if -o or -stream flag is specified
+	if *outputFile != "" || *stream {
+		*outputDxf = true
+	}
+
+	// DXF conversion options
+	opts := []dxf.ConvertOption{dxf.WithFontMap(fontMap)}
+	if *explodeDims {
+		opts = append(opts, dxf.WithExplodedDimensions())
+	}
+	if *explodeHatching {
+		opts = append(opts, dxf.WithExplodedHatching())
+	}
+	if *attributeLayers {
+		opts = append(opts, dxf.WithAttributeLayers())
+	}
+	switch *solidFill {
+	case "hatch":
+	case "merged":
+		opts = append(opts, dxf.WithSolidFill(dxf.SolidFillMerged))
+	case "solid":
+		opts = append(opts, dxf.WithSolidFill(dxf.SolidFillSolid))
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown -solid-fill mode %q\n", *solidFill)
+		os.Exit(2)
+	}
+
+	if *stream {
+		if parseOpts.Mode != jww.Standard {
+			fmt.Fprintln(os.Stderr, "Error: -stream requires -mode standard")
+			os.Exit(2)
+		}
+		if err := streamDXF(f, *outputFile, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *verbose && *outputFile != "" {
+			fmt.Fprintf(os.Stderr, "DXF written to: %s\n", *outputFile)
+		}
+		return
+	}
+
+	doc, err := jww.ParseWithOptions(f, parseOpts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing JWW: %v\n", err)
 		os.Exit(1)
 	}
+	for _, w := range doc.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
 
 	if *verbose {
 		fmt.Fprintf(os.Stderr, "JWW File: %s\n", inputFile)
@@ -48,14 +122,9 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  Blocks: %d\n", len(doc.BlockDefs))
 	}
 
-	// Auto-enable DXF output if -o flag is specified
-	if *outputFile != "" {
-		*outputDxf = true
-	}
-
 	if *outputDxf {
 		// Convert to DXF
-		dxfDoc := dxf.ConvertDocument(doc)
+		dxfDoc := dxf.ConvertDocument(doc, opts...)
 		dxfStr := dxf.ToString(dxfDoc)
 
 		// Output
@@ -64,6 +133,11 @@ func main() {
 				fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
 				os.Exit(1)
 			}
+			// Bundled images are referenced relative to the DXF file
+			if err := dxf.WriteImageFiles(dxfDoc, filepath.Dir(*outputFile)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing images: %v\n", err)
+				os.Exit(1)
+			}
 			if *verbose {
 				fmt.Fprintf(os.Stderr, "DXF written to: %s\n", *outputFile)
 			}
@@ -80,3 +154,45 @@ func main() {
 		fmt.Printf("  Blocks: %d\n", len(doc.BlockDefs))
 	}
 }
+
+// streamDXF converts the JWW file f to DXF with dxf.ConvertStream, writing
+// to outputFile, or to stdout when it is empty. The bundled images are
+// written next to the output file.
+func streamDXF(f *os.File, outputFile string, opts []dxf.ConvertOption) error {
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	dec := jww.NewDecoder(f, st.Size())
+
+	out := os.Stdout
+	if outputFile != "" {
+		if out, err = os.Create(outputFile); err != nil {
+			return fmt.Errorf("writing output: %w", err)
+		}
+		defer out.Close()
+	}
+	if err := dxf.ConvertStream(out, dec, opts...); err != nil {
+		return fmt.Errorf("converting JWW: %w", err)
+	}
+	for _, w := range dec.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	if outputFile == "" {
+		return nil
+	}
+
+	// Bundled images are referenced relative to the DXF file
+	images, _ := dec.Images()
+	for _, img := range images {
+		data, err := img.Decompress(0)
+		if err != nil {
+			return fmt.Errorf("writing image %s: %w", img.Name, err)
+		}
+		path := filepath.Join(filepath.Dir(outputFile), img.FileName())
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("writing image %s: %w", img.Name, err)
+		}
+	}
+	return out.Close()
+}
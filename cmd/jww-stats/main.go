@@ -30,6 +30,8 @@ type FileStats struct {
 	Texts     int
 	Solids    int
 	Blocks    int
+	Dims      int
+	Images    int
 	BlockDefs int
 	Unknown   []string
 	Error     string
@@ -114,6 +116,8 @@ func main() {
 			fmt.Sprintf("%d", s.Texts),
 			fmt.Sprintf("%d", s.Solids),
 			fmt.Sprintf("%d", s.Blocks),
+			fmt.Sprintf("%d", s.Dims),
+			fmt.Sprintf("%d", s.Images),
 			fmt.Sprintf("%d", s.BlockDefs),
 			errStr,
 		})
@@ -121,7 +125,7 @@ func main() {
 
 	fmt.Println("## Test Data Matrix")
 	fmt.Println()
-	printTable([]string{"File", "Version", "Line", "Arc", "Point", "Text", "Solid", "Block", "BlockDef", "Error"}, testDataRows)
+	printTable([]string{"File", "Version", "Line", "Arc", "Point", "Text", "Solid", "Block", "Dim", "Image", "BlockDef", "Error"}, testDataRows)
 
 	// Build DXF Conversion Results rows
 	var dxfRows [][]string
@@ -294,10 +298,14 @@ func parseFile(path string) FileStats {
 			stats.Points++
 		case "TEXT":
 			stats.Texts++
-		case "SOLID":
+		case "SOLID", "CIRCLE_SOLID":
 			stats.Solids++
 		case "BLOCK":
 			stats.Blocks++
+		case "DIMENSION":
+			stats.Dims++
+		case "IMAGE":
+			stats.Images++
 		default:
 			stats.Unknown = append(stats.Unknown, e.Type())
 		}
@@ -0,0 +1,90 @@
+package dxf
+
+import "github.com/f4ah6o/jww-parser/jww"
+
+// AttributeLayerSuffix returns the sub-layer suffix used by
+// WithAttributeLayers for entities with the given JWW attributes, or "" when
+// they stay on their layer. When several attributes are set, the first of
+// this list applies:
+//   - "HATCH": hatching (ハッチ)
+//   - "DIM": dimension parts and dimension value texts (寸法, 寸法値)
+//   - "FITTING": fittings (建具)
+//   - "FIGURE": figures (図形)
+//   - "NORTH": true north texts (真北)
+//   - "SHADOW": shadow texts (日影)
+//   - "25D": 2.5D texts
+func AttributeLayerSuffix(a jww.Attributes) string {
+	switch {
+	case a.Hatch:
+		return "HATCH"
+	case a.DimensionPart:
+		return "DIM"
+	}
+	switch a.TextRole {
+	case jww.TextRoleDimensionValue, jww.TextRoleRadiusValue, jww.TextRoleDiameterValue,
+		jww.TextRoleAngleValue, jww.TextRoleCumulativeValue:
+		return "DIM"
+	}
+	switch {
+	case a.Fitting:
+		return "FITTING"
+	case a.Figure:
+		return "FIGURE"
+	case a.TextRole == jww.TextRoleTrueNorth:
+		return "NORTH"
+	case a.TextRole == jww.TextRoleShadow:
+		return "SHADOW"
+	case a.Is25D:
+		return "25D"
+	}
+	return ""
+}
+
+// attributeLayers collects the sub-layers created by WithAttributeLayers.
+type attributeLayers struct {
+	names   []string          // Sub-layer names in order of first use
+	parents map[string]string // Sub-layer name -> layer name
+}
+
+// assign moves entities converted from a JWW entity with the given
+// attributes to their sub-layer.
+func (al *attributeLayers) assign(entities []Entity, a jww.Attributes) {
+	suffix := AttributeLayerSuffix(a)
+	if suffix == "" {
+		return
+	}
+	for _, e := range entities {
+		layer := entityLayer(e)
+		if layer == nil {
+			continue
+		}
+		name := *layer + "_" + suffix
+		if _, ok := al.parents[name]; !ok {
+			al.parents[name] = *layer
+			al.names = append(al.names, name)
+		}
+		*layer = name
+	}
+}
+
+// addLayers appends the sub-layers to layers, copying the properties of
+// their layer.
+func (al *attributeLayers) addLayers(layers []Layer) []Layer {
+	index := make(map[string]int, len(layers))
+	for i, l := range layers {
+		index[l.Name] = i
+	}
+	for _, name := range al.names {
+		if _, ok := index[name]; ok {
+			continue
+		}
+		layer := Layer{Name: name, Color: 7, LineType: "CONTINUOUS"}
+		if i, ok := index[al.parents[name]]; ok {
+			layer = layers[i]
+			layer.Name = name
+		}
+		index[name] = len(layers)
+		layers = append(layers, layer)
+	}
+	return layers
+}
@@ -0,0 +1,59 @@
+package dxf
+
+import (
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+func TestAttributeLayerSuffix(t *testing.T) {
+	tests := []struct {
+		attrs jww.Attributes
+		want  string
+	}{
+		{jww.Attributes{}, ""},
+		{jww.Attributes{Vertical: true}, ""},
+		{jww.Attributes{Hatch: true, Figure: true}, "HATCH"},
+		{jww.Attributes{TextRole: jww.TextRoleDiameterValue}, "DIM"},
+		{jww.Attributes{Fitting: true, EnvelopeExcluded: true}, "FITTING"},
+		{jww.Attributes{Figure: true}, "FIGURE"},
+		{jww.Attributes{TextRole: jww.TextRoleShadow}, "SHADOW"},
+		{jww.Attributes{Is25D: true}, "25D"},
+	}
+	for _, tt := range tests {
+		if got := AttributeLayerSuffix(tt.attrs); got != tt.want {
+			t.Errorf("AttributeLayerSuffix(%+v) = %q, want %q", tt.attrs, got, tt.want)
+		}
+	}
+}
+
+func TestConvertDocument_AttributeLayers(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[0].Layers[1].Protect = 1
+	doc.Entities = []jww.Entity{
+		&jww.Line{EndX: 1},
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 1, Flag: 0x1000}, EndX: 1},
+		&jww.Arc{EntityBase: jww.EntityBase{Layer: 1, Flag: 0x0080}, Radius: 1, IsFullCircle: true},
+	}
+
+	result := ConvertDocument(doc, WithAttributeLayers())
+
+	want := []string{"0-0", "0-1_FITTING", "0-1_FITTING"}
+	for i, e := range result.Entities {
+		if got := *entityLayer(e); got != want[i] {
+			t.Errorf("entity %d: layer %q, want %q", i, got, want[i])
+		}
+	}
+	if n := len(result.Layers); n != 257 {
+		t.Fatalf("expected one sub-layer after the 256 layers, got %d layers", n)
+	}
+	if l := result.Layers[256]; l.Name != "0-1_FITTING" || !l.Locked || l.Color != result.Layers[1].Color {
+		t.Errorf("sub-layer: got %+v, want a copy of layer 0-1", l)
+	}
+
+	// Without the option the entities stay on their layers
+	result = ConvertDocument(doc)
+	if got := *entityLayer(result.Entities[1]); got != "0-1" {
+		t.Errorf("layer without option: got %q, want 0-1", got)
+	}
+}
@@ -211,6 +211,20 @@ func WithTextStyle(style string) TextOption {
 	}
 }
 
+// WithTextWidthFactor sets the relative character width for a Text entity.
+func WithTextWidthFactor(factor float64) TextOption {
+	return func(t *Text) {
+		t.WidthFactor = factor
+	}
+}
+
+// WithTextObliqueAngle sets the character slant (in degrees) for a Text entity.
+func WithTextObliqueAngle(angle float64) TextOption {
+	return func(t *Text) {
+		t.ObliqueAngle = angle
+	}
+}
+
 // NewText creates a new Text entity with the given position and content.
 // Optional TextOption functions can customize the text properties.
 //
@@ -238,6 +252,95 @@ func NewText(x, y float64, content string, opts ...TextOption) *Text {
 	return text
 }
 
+// MTextOption configures MText entity properties.
+type MTextOption func(*MText)
+
+// WithMTextLayer sets the layer for an MText entity.
+func WithMTextLayer(layer string) MTextOption {
+	return func(m *MText) {
+		m.Layer = layer
+	}
+}
+
+// WithMTextColor sets the color for an MText entity.
+func WithMTextColor(color int) MTextOption {
+	return func(m *MText) {
+		m.Color = color
+	}
+}
+
+// WithMTextHeight sets the character height for an MText entity.
+func WithMTextHeight(height float64) MTextOption {
+	return func(m *MText) {
+		m.Height = height
+	}
+}
+
+// WithMTextWidth sets the reference column width for an MText entity.
+// Lines longer than the width are wrapped by the reading application.
+func WithMTextWidth(width float64) MTextOption {
+	return func(m *MText) {
+		m.Width = width
+	}
+}
+
+// WithMTextRotation sets the rotation angle (in degrees) for an MText entity.
+func WithMTextRotation(rotation float64) MTextOption {
+	return func(m *MText) {
+		m.Rotation = rotation
+	}
+}
+
+// WithMTextStyle sets the text style for an MText entity.
+func WithMTextStyle(style string) MTextOption {
+	return func(m *MText) {
+		m.Style = style
+	}
+}
+
+// WithMTextAttachment sets the attachment point (e.g. dxf.MTextMiddleCenter)
+// for an MText entity.
+func WithMTextAttachment(attachment int) MTextOption {
+	return func(m *MText) {
+		m.AttachmentPoint = attachment
+	}
+}
+
+// WithMTextLineSpacing sets the line spacing factor for an MText entity.
+func WithMTextLineSpacing(factor float64) MTextOption {
+	return func(m *MText) {
+		m.LineSpacing = factor
+	}
+}
+
+// NewMText creates a new MText entity with the given position and content.
+// The content may contain MTEXT formatting codes; plain newlines are
+// converted to paragraph breaks when written. Optional MTextOption functions
+// can customize the text properties.
+//
+// Example:
+//
+//	mtext := dxf.NewMText(10, 10, "Line 1\PLine 2",
+//		dxf.WithMTextHeight(5.0),
+//		dxf.WithMTextWidth(100),
+//		dxf.WithMTextAttachment(dxf.MTextMiddleCenter))
+func NewMText(x, y float64, content string, opts ...MTextOption) *MText {
+	mtext := &MText{
+		Layer:           "0",
+		Color:           0, // BYLAYER
+		X:               x,
+		Y:               y,
+		Height:          2.5,
+		Content:         content,
+		Style:           "STANDARD",
+		AttachmentPoint: MTextTopLeft,
+	}
+	for _, opt := range opts {
+		opt(mtext)
+	}
+	return mtext
+}
+
 // SolidOption configures Solid entity properties.
 type SolidOption func(*Solid)
 
@@ -285,6 +388,69 @@ func NewSolid(p1x, p1y, p2x, p2y, p3x, p3y, p4x, p4y float64, opts ...SolidOptio
 	return solid
 }
 
+// HatchOption configures Hatch entity properties.
+type HatchOption func(*Hatch)
+
+// WithHatchLayer sets the layer for a Hatch entity.
+func WithHatchLayer(layer string) HatchOption {
+	return func(h *Hatch) {
+		h.Layer = layer
+	}
+}
+
+// WithHatchColor sets the color for a Hatch entity.
+func WithHatchColor(color int) HatchOption {
+	return func(h *Hatch) {
+		h.Color = color
+	}
+}
+
+// WithHatchTrueColor sets a 24-bit RGB color (0xRRGGBB) for a Hatch entity.
+func WithHatchTrueColor(rgb int) HatchOption {
+	return func(h *Hatch) {
+		h.TrueColor = &rgb
+	}
+}
+
+// WithHatchPattern sets the pattern name, angle (in degrees) and scale for a
+// Hatch entity. Use WithHatchPatternLines to supply the pattern definition.
+func WithHatchPattern(name string, angle, scale float64) HatchOption {
+	return func(h *Hatch) {
+		h.Pattern = name
+		h.PatternAngle = angle
+		h.PatternScale = scale
+	}
+}
+
+// WithHatchPatternLines sets the pattern definition lines for a Hatch entity.
+func WithHatchPatternLines(lines ...HatchPatternLine) HatchOption {
+	return func(h *Hatch) {
+		h.PatternLines = lines
+	}
+}
+
+// NewHatch creates a new solid filled Hatch entity with the given boundaries.
+// Optional HatchOption functions can customize the hatch properties.
+//
+// Example:
+//
+//	hatch := dxf.NewHatch([]dxf.HatchBoundary{
+//		dxf.PolygonBoundary(0, 0, 100, 0, 100, 100, 0, 100),
+//		dxf.CircleBoundary(50, 50, 25), // Hole
+//	}, dxf.WithHatchColor(3))
+func NewHatch(boundaries []HatchBoundary, opts ...HatchOption) *Hatch {
+	hatch := &Hatch{
+		Layer:      "0",
+		Color:      0, // BYLAYER
+		Pattern:    "SOLID",
+		Boundaries: boundaries,
+	}
+	for _, opt := range opts {
+		opt(hatch)
+	}
+	return hatch
+}
+
 // InsertOption configures Insert entity properties.
 type InsertOption func(*Insert)
 
@@ -104,6 +104,28 @@ func TestNewTextWithOptions(t *testing.T) {
 	}
 }
 
+func TestNewMTextWithOptions(t *testing.T) {
+	mtext := NewMText(10, 20, "Hello",
+		WithMTextLayer("Notes"),
+		WithMTextHeight(5.0),
+		WithMTextWidth(80),
+		WithMTextAttachment(MTextMiddleCenter),
+		WithMTextLineSpacing(1.5))
+
+	if mtext.X != 10 || mtext.Y != 20 || mtext.Content != "Hello" {
+		t.Errorf("NewMText position or content mismatch: %+v", mtext)
+	}
+	if mtext.Layer != "Notes" || mtext.Height != 5 || mtext.Width != 80 {
+		t.Errorf("NewMText options not applied: %+v", mtext)
+	}
+	if mtext.AttachmentPoint != MTextMiddleCenter || mtext.LineSpacing != 1.5 {
+		t.Errorf("NewMText attachment or spacing mismatch: %+v", mtext)
+	}
+	if mtext.Style != "STANDARD" {
+		t.Errorf("Expected default style STANDARD, got %q", mtext.Style)
+	}
+}
+
 func TestNewSolid(t *testing.T) {
 	solid := NewSolid(0, 0, 100, 0, 50, 100, 50, 100)
 	if solid.X1 != 0 || solid.Y1 != 0 {
@@ -0,0 +1,87 @@
+package dxf
+
+import "math"
+
+// aciPalette holds the default RGB values (0xRRGGBB) of the AutoCAD Color
+// Index colors 1-255. Index 0 (BYBLOCK) is unused.
+var aciPalette = buildACIPalette()
+
+// buildACIPalette generates the standard ACI palette:
+//   - 1-9: the basic colors
+//   - 10-249: 24 hues in 15° steps, each with five brightness levels in a
+//     saturated and a half-saturated variant
+//   - 250-255: shades of gray
+func buildACIPalette() [256]int {
+	var p [256]int
+
+	basic := []int{0xFF0000, 0xFFFF00, 0x00FF00, 0x00FFFF, 0x0000FF, 0xFF00FF, 0xFFFFFF, 0x808080, 0xC0C0C0}
+	copy(p[1:], basic)
+
+	values := []float64{255, 204, 153, 127, 76}
+	for i := 10; i < 250; i++ {
+		hue := float64((i-10)/10) * 15
+		v := values[(i%10)/2]
+		s := 1.0
+		if i%2 == 1 {
+			s = 0.5
+		}
+		p[i] = hsvToRGB(hue, s, v)
+	}
+
+	grays := []int{51, 80, 105, 130, 190, 255}
+	for k, g := range grays {
+		p[250+k] = g<<16 | g<<8 | g
+	}
+
+	return p
+}
+
+// hsvToRGB converts a hue in degrees, saturation (0-1) and value (0-255) to
+// 0xRRGGBB, truncating channel values as the ACI palette does.
+func hsvToRGB(hue, s, v float64) int {
+	lo := v * (1 - s)
+	sector := int(hue / 60)
+	frac := (hue - float64(sector)*60) / 60
+	rising := lo + (v-lo)*frac
+	falling := v - (v-lo)*frac
+
+	var r, g, b float64
+	switch sector % 6 {
+	case 0:
+		r, g, b = v, rising, lo
+	case 1:
+		r, g, b = falling, v, lo
+	case 2:
+		r, g, b = lo, v, rising
+	case 3:
+		r, g, b = lo, falling, v
+	case 4:
+		r, g, b = rising, lo, v
+	default:
+		r, g, b = v, lo, falling
+	}
+	return int(r)<<16 | int(g)<<8 | int(b)
+}
+
+// NearestACI returns the AutoCAD Color Index (1-255) closest to the given
+// 24-bit RGB color (0xRRGGBB), measured by Euclidean distance in RGB space.
+// It is used as the fallback color for entities carrying a true color.
+//
+// Example:
+//
+//	aci := dxf.NearestACI(0xFF0000) // Returns 1 (red)
+func NearestACI(rgb int) int {
+	r, g, b := float64(rgb>>16&0xFF), float64(rgb>>8&0xFF), float64(rgb&0xFF)
+
+	best, bestDist := 7, math.Inf(1)
+	for i := 1; i < 256; i++ {
+		c := aciPalette[i]
+		dr := r - float64(c>>16&0xFF)
+		dg := g - float64(c>>8&0xFF)
+		db := b - float64(c&0xFF)
+		if d := dr*dr + dg*dg + db*db; d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,47 @@
+package dxf
+
+import "testing"
+
+func TestACIPalette(t *testing.T) {
+	tests := []struct {
+		aci int
+		rgb int
+	}{
+		{1, 0xFF0000},
+		{7, 0xFFFFFF},
+		{10, 0xFF0000},
+		{11, 0xFF7F7F},
+		{13, 0xCC6666},
+		{19, 0x4C2626},
+		{20, 0xFF3F00},
+		{21, 0xFF9F7F},
+		{250, 0x333333},
+		{255, 0xFFFFFF},
+	}
+
+	for _, tt := range tests {
+		if got := aciPalette[tt.aci]; got != tt.rgb {
+			t.Errorf("ACI %d: got %06X, want %06X", tt.aci, got, tt.rgb)
+		}
+	}
+}
+
+func TestNearestACI(t *testing.T) {
+	tests := []struct {
+		rgb  int
+		want int
+	}{
+		{0xFF0000, 1},
+		{0x00FF00, 3},
+		{0x0000FF, 5},
+		{0x808080, 8},
+		{0x333333, 250},
+		{0xFE7F80, 11},
+	}
+
+	for _, tt := range tests {
+		if got := NearestACI(tt.rgb); got != tt.want {
+			t.Errorf("NearestACI(%06X) = %d, want %d", tt.rgb, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package dxf
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/f4ah6o/jww-parser/jww"
 )
@@ -11,44 +12,69 @@ import (
 //
 // This function transforms JWW entities into their DXF equivalents:
 //   - JWW layers are converted to DXF layers with appropriate mapping
+//   - JWW line type patterns are converted to LTYPE definitions scaled per layer group
 //   - JWW entities (Line, Arc, Point, Text, Solid, Block) are converted to DXF entities
+//   - JWW vertical texts are converted to one upright TEXT per character
+//   - JWW dimension settings are converted to a DIMSTYLE
+//   - JWW dimensions are converted to DIMENSION entities whose anonymous blocks
+//     hold the dimension line, auxiliary lines and value text
+//   - JWW circle solids are converted to SOLID entities approximating the filled region
+//   - JWW image references are converted to IMAGE entities with matching image definitions
 //   - JWW block definitions are converted to DXF blocks
 //
 // The conversion handles:
 //   - Layer group and layer hierarchy mapping
 //   - Color index mapping
+//   - Line widths (per entity or per color number) to lineweights
 //   - Coordinate system preservation
 //   - Arc and ellipse geometry conversion
 //   - Text encoding (Shift-JIS to Unicode)
+//   - Fonts, text presets and italic/bold flags to text styles
+//
+// Options such as WithFontMap, WithExplodedDimensions and WithAttributeLayers
+// adjust the conversion.
 //
 // Returns a DXF Document ready to be written to a file.
-func ConvertDocument(doc *jww.Document) *Document {
+func ConvertDocument(doc *jww.Document, opts ...ConvertOption) *Document {
+	cfg := newConvertConfig(opts)
+	cfg.total = len(doc.Entities)
+	for _, bd := range doc.BlockDefs {
+		cfg.total += len(bd.Entities)
+	}
+	cfg.report()
+
 	dxfDoc := &Document{
-		Layers:   convertLayers(doc),
-		Entities: convertEntities(doc),
-		Blocks:   convertBlocks(doc),
+		Layers:     convertLayers(doc, cfg),
+		LineTypes:  convertLineTypes(doc),
+		TextStyles: convertTextStyles(doc, cfg),
+		DimStyles:  convertDimStyles(doc),
+		Entities:   convertEntities(doc, cfg),
+		Blocks:     convertBlocks(doc, cfg),
+		ImageDefs:  convertImageDefs(doc),
+	}
+	if cfg.attributeLayers != nil {
+		dxfDoc.Layers = cfg.attributeLayers.addLayers(dxfDoc.Layers)
 	}
+	assignLayerLineWeights(dxfDoc)
+	cfg.report()
 	return dxfDoc
 }
 
 // convertLayers creates DXF layers from JWW layer groups.
 // JWW has 16 layer groups with 16 layers each (256 total layers).
-// Each JWW layer is converted to a single DXF layer with a name like "0-0" or "F-A".
+// Each JWW layer is converted to a single DXF layer named after the JWW layer
+// (e.g. "壁"), or "0-0"/"F-A" style names for unnamed layers; see layerNames.
 // Layer properties (frozen, locked) are preserved in the conversion.
-func convertLayers(doc *jww.Document) []Layer {
+func convertLayers(doc *jww.Document, cfg *convertConfig) []Layer {
 	var layers []Layer
 
 	for gLay := 0; gLay < 16; gLay++ {
 		lg := &doc.LayerGroups[gLay]
 		for lay := 0; lay < 16; lay++ {
 			l := &lg.Layers[lay]
-			name := l.Name
-			if name == "" {
-				name = fmt.Sprintf("%X-%X", gLay, lay)
-			}
 
 			layers = append(layers, Layer{
-				Name:     name,
+				Name:     cfg.layerName(doc, uint16(gLay), uint16(lay)),
 				Color:    (gLay*16+lay)%255 + 1, // Simple ACI color mapping
 				LineType: "CONTINUOUS",
 				Frozen:   l.State == 0,
@@ -64,19 +90,240 @@ func convertLayers(doc *jww.Document) []Layer {
 // This function iterates through all entities in the JWW document and
 // converts each one based on its type. Unsupported or invalid entities
 // are skipped.
-func convertEntities(doc *jww.Document) []Entity {
+func convertEntities(doc *jww.Document, cfg *convertConfig) []Entity {
+	return convertEntityList(doc.Entities, doc, cfg)
+}
+
+// convertEntityList converts a list of JWW entities to DXF entities.
+// Recognized hatching becomes pattern HATCH entities unless exploded, and
+// solids are merged when SolidFillMerged is selected; all other entities are
+// converted by convertListEntity. With WithAttributeLayers, the converted
+// entities move to the sub-layers for their attribute flags.
+func convertEntityList(list []jww.Entity, doc *jww.Document, cfg *convertConfig) []Entity {
 	var entities []Entity
+	var solids []*Hatch
+
+	var hatching map[int]*Hatch
+	if !cfg.explodeHatching {
+		hatching = convertHatching(list, doc, cfg)
+	}
 
-	for _, e := range doc.Entities {
-		dxfEntity := convertEntity(e, doc)
-		if dxfEntity != nil {
-			entities = append(entities, dxfEntity)
+	for i, e := range list {
+		cfg.count()
+		var converted []Entity
+		if hatch, ok := hatching[i]; ok {
+			if hatch == nil {
+				continue
+			}
+			converted = []Entity{hatch}
+		} else {
+			converted = convertListEntity(e, doc, cfg)
 		}
+
+		if cfg.attributeLayers != nil {
+			cfg.attributeLayers.assign(converted, jww.DecodeAttributes(e))
+		}
+		if _, ok := e.(*jww.Solid); ok {
+			for _, c := range converted {
+				if hatch, ok := c.(*Hatch); ok {
+					solids = append(solids, hatch)
+				}
+			}
+		}
+		entities = append(entities, converted...)
+	}
+
+	if cfg.solidFill == SolidFillMerged && len(solids) > 1 {
+		entities = mergeSolidHatches(entities, solids)
 	}
 
 	return entities
 }
 
+// convertListEntity converts a JWW entity to DXF entities. Dimensions become
+// DIMENSION entities, or their component entities when exploded; solids
+// become HATCH entities unless SolidFillSolid is selected; other composite
+// JWW entities expand to several DXF entities and all remaining entities are
+// converted one-to-one by convertEntity.
+func convertListEntity(e jww.Entity, doc *jww.Document, cfg *convertConfig) []Entity {
+	switch v := e.(type) {
+	case *jww.Solid:
+		if cfg.solidFill != SolidFillSolid {
+			if hatch := convertSolidHatch(v, doc, cfg); hatch != nil {
+				return []Entity{hatch}
+			}
+		}
+	case *jww.Dimension:
+		if !cfg.explodeDimensions {
+			if dim := convertDimensionEntity(v, doc, cfg); dim != nil {
+				return []Entity{dim}
+			}
+		}
+		return convertDimension(v, doc, cfg)
+	case *jww.CircleSolid:
+		if cfg.solidFill != SolidFillSolid {
+			if hatch := convertCircleSolidHatch(v, doc, cfg); hatch != nil {
+				return []Entity{hatch}
+			}
+		}
+		return convertCircleSolid(v, doc, cfg)
+	case *jww.Text:
+		if v.IsVertical() {
+			return convertVerticalText(v, doc, cfg)
+		}
+	}
+	if dxfEntity := convertEntity(e, doc, cfg); dxfEntity != nil {
+		return []Entity{dxfEntity}
+	}
+	return nil
+}
+
+// convertDimension converts a JWW dimension to its component DXF entities:
+// the dimension line, the auxiliary (extension) lines when present, and the
+// dimension value text.
+func convertDimension(dim *jww.Dimension, doc *jww.Document, cfg *convertConfig) []Entity {
+	var entities []Entity
+
+	if e := convertEntity(&dim.Line, doc, cfg); e != nil {
+		entities = append(entities, e)
+	}
+	for i := range dim.AuxLines {
+		aux := &dim.AuxLines[i]
+		if aux.StartX == aux.EndX && aux.StartY == aux.EndY {
+			continue // Not present or degenerate
+		}
+		if e := convertEntity(aux, doc, cfg); e != nil {
+			entities = append(entities, e)
+		}
+	}
+	if dim.Text.Content != "" {
+		if e := convertEntity(&dim.Text, doc, cfg); e != nil {
+			entities = append(entities, e)
+		}
+	}
+
+	return entities
+}
+
+// circleSolidSegments is the number of segments used to approximate a full
+// turn of a circle solid boundary.
+const circleSolidSegments = 64
+
+// convertCircleSolid converts a JWW circle solid (円ソリッド) to filled DXF
+// SOLID entities approximating the curved region. Circumference solids fill
+// the band given by circumferenceSolidBand; when their width is unknown they
+// are converted to their outline curve, with the lineweight of their pen.
+func convertCircleSolid(cs *jww.CircleSolid, doc *jww.Document, cfg *convertConfig) []Entity {
+	base := cs.Base()
+	layerName := cfg.layerName(doc, base.LayerGroup, base.Layer)
+	color, trueColor, colorName := entityColor(doc, base.PenColor)
+	if base.PenColor == 10 {
+		color, trueColor = rgbColor(cs.Color)
+	}
+
+	start, sweep := cs.StartAngle, cs.ArcAngle
+	if cs.IsFullCircle() {
+		start, sweep = 0, 2*math.Pi
+	}
+	n := int(math.Ceil(math.Abs(sweep) / (2 * math.Pi) * circleSolidSegments))
+	if n < 1 {
+		n = 1
+	}
+
+	// pointAt returns the point at parameter t on an ellipse with the solid's
+	// center and tilt and the given semi-axes.
+	sin, cos := math.Sincos(cs.TiltAngle)
+	pointAt := func(a, b, t float64) (float64, float64) {
+		lx, ly := a*math.Cos(t), b*math.Sin(t)
+		return cs.CenterX + lx*cos - ly*sin, cs.CenterY + lx*sin + ly*cos
+	}
+	outer := func(i int) (float64, float64) {
+		return pointAt(cs.Radius, cs.Radius*cs.Flatness, start+sweep*float64(i)/float64(n))
+	}
+	// ring returns the quadrilaterals between two ellipses
+	ring := func(outerA, outerB, innerA, innerB float64) []Entity {
+		entities := make([]Entity, 0, n)
+		for i := 0; i < n; i++ {
+			t1 := start + sweep*float64(i)/float64(n)
+			t2 := start + sweep*float64(i+1)/float64(n)
+			ox1, oy1 := pointAt(outerA, outerB, t1)
+			ox2, oy2 := pointAt(outerA, outerB, t2)
+			ix1, iy1 := pointAt(innerA, innerB, t1)
+			ix2, iy2 := pointAt(innerA, innerB, t2)
+			// DXF SOLID vertex order is 1-2-4-3 around the outline
+			entities = append(entities, &Solid{
+				Layer: layerName, Color: color, TrueColor: trueColor, ColorName: colorName,
+				X1: ox1, Y1: oy1, X2: ox2, Y2: oy2,
+				X3: ix1, Y3: iy1, X4: ix2, Y4: iy2,
+			})
+		}
+		return entities
+	}
+	triangle := func(x1, y1, x2, y2, x3, y3 float64) Entity {
+		return &Solid{Layer: layerName, Color: color, TrueColor: trueColor, ColorName: colorName, X1: x1, Y1: y1, X2: x2, Y2: y2, X3: x3, Y3: y3, X4: x3, Y4: y3}
+	}
+	fan := func(cx, cy float64) []Entity {
+		entities := make([]Entity, 0, n)
+		for i := 0; i < n; i++ {
+			x1, y1 := outer(i)
+			x2, y2 := outer(i + 1)
+			entities = append(entities, triangle(cx, cy, x1, y1, x2, y2))
+		}
+		return entities
+	}
+
+	switch cs.Kind() {
+	case jww.CircleSolidSector, jww.CircleSolidDisc:
+		return fan(cs.CenterX, cs.CenterY)
+
+	case jww.CircleSolidSegment:
+		// Fan from the arc start; the region is convex
+		return fan(outer(0))
+
+	case jww.CircleSolidOuterArc:
+		// Fan from the intersection of the end tangents, which lies on the
+		// bisector at R/cos(sweep/2) in the unscaled circle
+		half := sweep / 2
+		if math.Abs(math.Cos(half)) < 1e-9 {
+			return nil
+		}
+		d := cs.Radius / math.Cos(half)
+		cx, cy := pointAt(d, d*cs.Flatness, start+half)
+		return fan(cx, cy)
+
+	case jww.CircleSolidRing, jww.CircleSolidRingOffset:
+		innerA := cs.InnerRadius()
+		innerB := innerA * cs.Flatness
+		if cs.Kind() == jww.CircleSolidRingOffset {
+			// Same difference between outer and inner on both axes
+			innerB = cs.Radius*cs.Flatness - (cs.Radius - innerA)
+		}
+		return ring(cs.Radius, cs.Radius*cs.Flatness, innerA, innerB)
+
+	case jww.CircleSolidCircumference:
+		if outerA, outerB, innerA, innerB, ok := circumferenceSolidBand(cs, doc); ok {
+			return ring(outerA, outerB, innerA, innerB)
+		}
+		arc := &jww.Arc{
+			EntityBase:   *base,
+			CenterX:      cs.CenterX,
+			CenterY:      cs.CenterY,
+			Radius:       cs.Radius,
+			StartAngle:   cs.StartAngle,
+			ArcAngle:     cs.ArcAngle,
+			TiltAngle:    cs.TiltAngle,
+			Flatness:     cs.Flatness,
+			IsFullCircle: cs.IsFullCircle(),
+		}
+		arc.PenStyle = 1 // Solid line
+		if e := convertEntity(arc, doc, cfg); e != nil {
+			return []Entity{e}
+		}
+	}
+
+	return nil
+}
+
 // convertEntity converts a single JWW entity to its DXF equivalent.
 //
 // Supported conversions:
@@ -86,36 +333,46 @@ func convertEntities(doc *jww.Document) []Entity {
 //   - jww.Text -> dxf.Text (with Unicode escape conversion)
 //   - jww.Solid -> dxf.Solid
 //   - jww.Block -> dxf.Insert
+//   - jww.ImageRef -> dxf.Image
+//
+// Dimensions (jww.Dimension) and circle solids (jww.CircleSolid) are handled
+// by convertDimensionEntity, convertDimension and convertCircleSolid instead.
 //
 // Returns nil for unsupported entity types or entities that should be skipped.
-func convertEntity(e jww.Entity, doc *jww.Document) Entity {
+func convertEntity(e jww.Entity, doc *jww.Document, cfg *convertConfig) Entity {
 	base := e.Base()
-	layerName := getLayerName(doc, base.LayerGroup, base.Layer)
-	color := mapColor(base.PenColor)
-	lineType := mapLineType(base.PenStyle)
+	layerName := cfg.layerName(doc, base.LayerGroup, base.Layer)
+	color, trueColor, colorName := entityColor(doc, base.PenColor)
+	lineType := lineTypeName(doc, base.PenStyle, base.LayerGroup)
 
 	switch v := e.(type) {
 	case *jww.Line:
 		return &Line{
-			Layer:    layerName,
-			Color:    color,
-			LineType: lineType,
-			X1:       v.StartX,
-			Y1:       v.StartY,
-			X2:       v.EndX,
-			Y2:       v.EndY,
+			Layer:      layerName,
+			Color:      color,
+			TrueColor:  trueColor,
+			ColorName:  colorName,
+			LineType:   lineType,
+			LineWeight: entityLineWeight(doc, base),
+			X1:         v.StartX,
+			Y1:         v.StartY,
+			X2:         v.EndX,
+			Y2:         v.EndY,
 		}
 
 	case *jww.Arc:
 		if v.IsFullCircle && v.Flatness == 1.0 {
 			// Full circle
 			return &Circle{
-				Layer:    layerName,
-				Color:    color,
-				LineType: lineType,
-				CenterX:  v.CenterX,
-				CenterY:  v.CenterY,
-				Radius:   v.Radius,
+				Layer:      layerName,
+				Color:      color,
+				TrueColor:  trueColor,
+				ColorName:  colorName,
+				LineType:   lineType,
+				LineWeight: entityLineWeight(doc, base),
+				CenterX:    v.CenterX,
+				CenterY:    v.CenterY,
+				Radius:     v.Radius,
 			}
 		} else if v.Flatness != 1.0 {
 			// Ellipse or elliptical arc
@@ -146,7 +403,10 @@ func convertEntity(e jww.Entity, doc *jww.Document) Entity {
 			return &Ellipse{
 				Layer:      layerName,
 				Color:      color,
+				TrueColor:  trueColor,
+				ColorName:  colorName,
 				LineType:   lineType,
+				LineWeight: entityLineWeight(doc, base),
 				CenterX:    v.CenterX,
 				CenterY:    v.CenterY,
 				MajorAxisX: majorAxisX,
@@ -163,7 +423,10 @@ func convertEntity(e jww.Entity, doc *jww.Document) Entity {
 			return &Arc{
 				Layer:      layerName,
 				Color:      color,
+				TrueColor:  trueColor,
+				ColorName:  colorName,
 				LineType:   lineType,
+				LineWeight: entityLineWeight(doc, base),
 				CenterX:    v.CenterX,
 				CenterY:    v.CenterY,
 				Radius:     v.Radius,
@@ -177,51 +440,63 @@ func convertEntity(e jww.Entity, doc *jww.Document) Entity {
 			return nil // Skip temporary points
 		}
 		return &Point{
-			Layer:    layerName,
-			Color:    color,
-			LineType: lineType,
-			X:        v.X,
-			Y:        v.Y,
+			Layer:     layerName,
+			Color:     color,
+			TrueColor: trueColor,
+			ColorName: colorName,
+			LineType:  lineType,
+			X:         v.X,
+			Y:         v.Y,
 		}
 
 	case *jww.Text:
-		// Use default height if SizeY is not set or too small
-		height := v.SizeY
-		if height <= 0 {
-			height = 2.5 // Default text height (same as NewText builder)
-		}
+		style, height := jwwTextStyle(v, doc)
 		return &Text{
-			Layer:    layerName,
-			Color:    color,
-			LineType: lineType,
-			X:        v.StartX,
-			Y:        v.StartY,
-			Height:   height,
-			Rotation: v.Angle,
-			Content:  v.Content,
-			Style:    "STANDARD",
+			Layer:        layerName,
+			Color:        color,
+			TrueColor:    trueColor,
+			ColorName:    colorName,
+			LineType:     lineType,
+			X:            v.StartX,
+			Y:            v.StartY,
+			Height:       height,
+			Rotation:     v.Angle,
+			Content:      v.Content,
+			Style:        style.name(),
+			WidthFactor:  textWidthFactor(v, height, style),
+			ObliqueAngle: style.obliqueAngle(),
 		}
 
 	case *jww.Solid:
+		if v.PenColor == 10 {
+			color, trueColor = rgbColor(v.Color)
+		}
 		return &Solid{
-			Layer:    layerName,
-			Color:    color,
-			LineType: lineType,
-			X1:       v.Point1X,
-			Y1:       v.Point1Y,
-			X2:       v.Point2X,
-			Y2:       v.Point2Y,
-			X3:       v.Point3X,
-			Y3:       v.Point3Y,
-			X4:       v.Point4X,
-			Y4:       v.Point4Y,
+			Layer:     layerName,
+			Color:     color,
+			TrueColor: trueColor,
+			ColorName: colorName,
+			LineType:  lineType,
+			X1:        v.Point1X,
+			Y1:        v.Point1Y,
+			X2:        v.Point2X,
+			Y2:        v.Point2Y,
+			X3:        v.Point3X,
+			Y3:        v.Point3Y,
+			X4:        v.Point4X,
+			Y4:        v.Point4Y,
 		}
 
+	case *jww.ImageRef:
+		return convertImage(v, doc, cfg)
+
 	case *jww.Block:
-		blockName := getBlockName(doc, v.DefNumber)
+		blockName := cfg.blockName(doc, v.DefNumber)
 		return &Insert{
 			Layer:     layerName,
 			Color:     color,
+			TrueColor: trueColor,
+			ColorName: colorName,
 			LineType:  lineType,
 			BlockName: blockName,
 			X:         v.RefX,
@@ -236,56 +511,131 @@ func convertEntity(e jww.Entity, doc *jww.Document) Entity {
 }
 
 // convertBlocks converts JWW block definitions to DXF blocks.
-// Each JWW block definition is converted to a DXF block with all its
-// entities converted to DXF equivalents.
-func convertBlocks(doc *jww.Document) []Block {
+// Each JWW block definition is converted to a DXF block named by
+// blockNames with all its entities converted to DXF equivalents. Compound
+// figures (partial figures, drawing groups and parts) are described by their
+// kind. Their entities are stored in the drawing's math coordinates whatever
+// the kind, so partial figures in survey coordinates need no transformation;
+// the description records the coordinate system they were drawn in.
+func convertBlocks(doc *jww.Document, cfg *convertConfig) []Block {
 	var blocks []Block
 
 	for _, bd := range doc.BlockDefs {
 		block := Block{
-			Name:  bd.Name,
+			Name:  cfg.blockName(doc, bd.Number),
 			BaseX: 0,
 			BaseY: 0,
 		}
-
-		for _, e := range bd.Entities {
-			dxfEntity := convertEntity(e, doc)
-			if dxfEntity != nil {
-				block.Entities = append(block.Entities, dxfEntity)
-			}
+		if bd.Kind != jww.BlockKindBlock {
+			block.Description = "Jw_cad " + bd.Kind.String()
 		}
 
+		block.Entities = convertEntityList(bd.Entities, doc, cfg)
+
 		blocks = append(blocks, block)
 	}
 
 	return blocks
 }
 
-// getLayerName returns the DXF layer name for a given JWW layer group and layer.
-// If the layer has a custom name, it is used. Otherwise, a default name
-// in the format "G-L" (e.g., "0-0", "F-A") is generated using hexadecimal notation.
-func getLayerName(doc *jww.Document, layerGroup, layer uint16) string {
-	if int(layerGroup) < 16 && int(layer) < 16 {
-		lg := &doc.LayerGroups[layerGroup]
-		l := &lg.Layers[layer]
-		if l.Name != "" {
-			return l.Name
+// layerNames holds the DXF layer name of each JWW layer, by layer group and
+// layer. If the layer has a custom name (e.g. "壁"), it is used with
+// characters that are invalid in DXF symbol names replaced by "_". When the
+// same name, compared case-insensitively like DXF layer names, is used by
+// more than one JWW layer, the name is prefixed with the "G-L" position
+// (e.g. "0-1_壁") so every JWW layer keeps its own DXF layer. Unnamed layers
+// get a default name in the format "G-L" (e.g., "0-0", "F-A") in hexadecimal
+// notation.
+type layerNames [16][16]string
+
+// newLayerNames returns the DXF layer names of the layers of doc.
+func newLayerNames(doc *jww.Document) *layerNames {
+	names := new(layerNames)
+	uses := make(map[string]int)
+	for gLay := range names {
+		for lay := range names[gLay] {
+			names[gLay][lay] = baseLayerName(doc, gLay, lay)
+			uses[strings.ToUpper(names[gLay][lay])]++
 		}
 	}
-	return fmt.Sprintf("%X-%X", layerGroup, layer)
+	for gLay := range names {
+		for lay := range names[gLay] {
+			name := names[gLay][lay]
+			if name != fmt.Sprintf("%X-%X", gLay, lay) && uses[strings.ToUpper(name)] > 1 {
+				names[gLay][lay] = fmt.Sprintf("%X-%X_%s", gLay, lay, name)
+			}
+		}
+	}
+	return names
+}
+
+// name returns the DXF layer name for a given JWW layer group and layer.
+func (n *layerNames) name(layerGroup, layer uint16) string {
+	if int(layerGroup) >= 16 || int(layer) >= 16 {
+		return fmt.Sprintf("%X-%X", layerGroup, layer)
+	}
+	return n[layerGroup][layer]
+}
+
+// baseLayerName returns the sanitized stored name of a JWW layer,
+// or the default "G-L" name when the layer is unnamed.
+func baseLayerName(doc *jww.Document, gLay, lay int) string {
+	name := strings.TrimSpace(doc.LayerGroups[gLay].Layers[lay].Name)
+	if name == "" {
+		return fmt.Sprintf("%X-%X", gLay, lay)
+	}
+	return sanitizeSymbolName(name)
+}
+
+// sanitizeSymbolName replaces characters that are not allowed in DXF symbol
+// table names (layers, blocks, styles) with "_".
+func sanitizeSymbolName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '<', '>', '/', '\\', '"', ':', ';', '?', '*', '|', '=', '`':
+			return '_'
+		}
+		if r < 0x20 {
+			return '_'
+		}
+		return r
+	}, name)
 }
 
-// getBlockName returns the block name for a given JWW block definition number.
-// If the block has a custom name, it is used. Otherwise, a default name
-// like "BLOCK_1" is generated.
-func getBlockName(doc *jww.Document, defNumber uint32) string {
+// blockNames maps JWW block definition numbers to DXF block names. If a
+// block has a custom name, it is used with characters that are invalid in DXF
+// symbol names replaced by "_"; when another block definition has the same
+// name, compared case-insensitively, the number is appended (e.g. "窓_2").
+// Otherwise, a default name like "BLOCK_1" is used.
+type blockNames map[uint32]string
+
+// newBlockNames returns the DXF block names of the block definitions of doc.
+func newBlockNames(doc *jww.Document) blockNames {
+	names := make(blockNames, len(doc.BlockDefs))
+	uses := make(map[string]int)
 	for _, bd := range doc.BlockDefs {
-		if bd.Number == defNumber {
-			if bd.Name != "" {
-				return bd.Name
-			}
-			break
+		if _, ok := names[bd.Number]; ok {
+			continue
 		}
+		name := sanitizeSymbolName(strings.TrimSpace(bd.Name))
+		names[bd.Number] = name
+		uses[strings.ToUpper(name)]++
+	}
+	for number, name := range names {
+		switch {
+		case name == "":
+			names[number] = fmt.Sprintf("BLOCK_%d", number)
+		case uses[strings.ToUpper(name)] > 1:
+			names[number] = fmt.Sprintf("%s_%d", name, number)
+		}
+	}
+	return names
+}
+
+// name returns the DXF block name for a given JWW block definition number.
+func (n blockNames) name(defNumber uint32) string {
+	if name, ok := n[defNumber]; ok {
+		return name
 	}
 	return fmt.Sprintf("BLOCK_%d", defNumber)
 }
@@ -303,7 +653,8 @@ func getBlockName(doc *jww.Document, defNumber uint32) string {
 //   - 7: 黒/白 (foreground) -> 7 (white/black)
 //   - 8: 赤 (red) -> 1 (red)
 //   - 9: グレー (gray) -> 8 (dark gray)
-//   - 100+: extended SXF colors -> mapped to DXF colors 10+
+//   - 10 (arbitrary RGB) and 100+ (SXF extended colors): see entityColor;
+//     7 when no color data is available
 //
 // DXF ACI color reference:
 //   - 0: BYLAYER (inherits layer color)
@@ -333,15 +684,158 @@ func mapColor(jwwColor uint16) int {
 	case 9:
 		return 8 // JWW グレー (gray) -> DXF gray
 	default:
-		// Extended colors (SXF): 100+ -> map to DXF 10+
-		if jwwColor >= 100 {
-			return int(jwwColor - 100 + 10)
+		// Extended (SXF) and arbitrary RGB colors are resolved by
+		// entityColor; without color data use the foreground color.
+		return 7
+	}
+}
+
+// entityColor returns the ACI color, optional true color and color name for a
+// JWW pen color. SXF extended colors (100 and above, Ver.4.20+) are looked up
+// in the header color table: the printer RGB becomes the true color, its
+// nearest ACI the fallback, and the user-defined name the color name. Other
+// colors map to a fixed ACI value via mapColor.
+func entityColor(doc *jww.Document, penColor uint16) (int, *int, string) {
+	if penColor >= 100 {
+		if idx := int(penColor) - 100; idx < len(doc.Header.SXFColors) {
+			// The printer color is the drawing color the SXF standard
+			// defines; the screen color is a display setting that users
+			// adjust to their background, so it is not used.
+			sxf := &doc.Header.SXFColors[idx]
+			aci, rgb := rgbColor(sxf.PrinterColor)
+			return aci, rgb, sxf.Name
+		}
+	}
+	return mapColor(penColor), nil, ""
+}
+
+// rgbColor converts a Windows COLORREF (0x00BBGGRR) as stored in JWW files to
+// the nearest ACI and a true color (0xRRGGBB).
+func rgbColor(colorRef uint32) (int, *int) {
+	rgb := int(colorRef&0xFF)<<16 | int(colorRef&0xFF00) | int(colorRef>>16&0xFF)
+	return NearestACI(rgb), &rgb
+}
+
+// defaultLineWidthDPI is the printer resolution assumed for line widths in
+// printer dots when the file does not record one (before Ver.6.00).
+const defaultLineWidthDPI = 600
+
+// entityLineWeight returns the DXF lineweight of a JWW entity, or 0 when its
+// width is unknown; see entityLineWidth.
+func entityLineWeight(doc *jww.Document, base *jww.EntityBase) int {
+	if width := entityLineWidth(doc, base); width > 0 {
+		return NearestLineWeight(width)
+	}
+	return 0
+}
+
+// entityLineWidth returns the printed line width of a JWW entity in 1/100 mm,
+// or 0 when it is unknown. In 1/100 mm mode an entity's own PenWidth is used
+// when set; otherwise the printer width of its color number applies, given
+// either in 1/100 mm or in printer dots depending on the mode.
+func entityLineWidth(doc *jww.Document, base *jww.EntityBase) float64 {
+	hd := &doc.Header
+	if hd.LineWidthIn100thMM() && base.PenWidth > 0 {
+		return float64(base.PenWidth)
+	}
+
+	var width uint32
+	switch pc := int(base.PenColor); {
+	case pc >= 1 && pc < len(hd.Pens):
+		width = hd.Pens[pc].PrinterWidth
+	case pc >= 100 && pc-100 < len(hd.SXFColors):
+		width = hd.SXFColors[pc-100].PrinterWidth
+	}
+	if width == 0 {
+		return 0
+	}
+
+	if hd.LineWidthIn100thMM() {
+		return float64(width)
+	}
+	return float64(width) * printerDotSize(hd) * 100
+}
+
+// printerDotSize returns the size in millimetres of a printer dot, the unit
+// of the printer widths of the pen tables outside 1/100 mm mode and of the
+// line type pitches, at the resolution recorded in the header.
+func printerDotSize(hd *jww.Header) float64 {
+	dpi := hd.LineWidthDPI()
+	if dpi == 0 {
+		dpi = defaultLineWidthDPI
+	}
+	return 25.4 / float64(dpi)
+}
+
+// circumferenceSolidBand returns the outer and inner semi-axes of the band
+// filled by a circumference solid (円周ソリッド), a solid line along the
+// circumference. The file stores no width for it, so the band is
+// approximated by the printed line width of its pen (see entityLineWidth),
+// converted from paper to drawing units with the layer group scale and kept
+// equal on both axes like an offset ring solid. It reports false when the
+// width is unknown or not smaller than the circle.
+func circumferenceSolidBand(cs *jww.CircleSolid, doc *jww.Document) (outerA, outerB, innerA, innerB float64, ok bool) {
+	half := entityLineWidth(doc, &cs.EntityBase) / 100 * layerGroupScale(doc, cs.LayerGroup) / 2
+	a, b := cs.Radius, cs.Radius*cs.Flatness
+	if half <= 0 || half >= a || half >= b {
+		return 0, 0, 0, 0, false
+	}
+	return a + half, b + half, a - half, b - half, true
+}
+
+// assignLayerLineWeights sets the lineweight of every layer to the weight
+// used by most of its entities, so that plotting by layer matches the
+// converted entities. Ties go to the thinner weight.
+func assignLayerLineWeights(doc *Document) {
+	counts := make(lineWeightCounts)
+	counts.add(doc.Entities)
+	counts.assign(doc.Layers)
+}
+
+// lineWeightCounts counts the entities drawn with each lineweight per layer.
+type lineWeightCounts map[string]map[int]int
+
+// add counts the lines, circles, arcs and ellipses among entities.
+func (counts lineWeightCounts) add(entities []Entity) {
+	for _, e := range entities {
+		var layer string
+		var lw int
+		switch v := e.(type) {
+		case *Line:
+			layer, lw = v.Layer, v.LineWeight
+		case *Circle:
+			layer, lw = v.Layer, v.LineWeight
+		case *Arc:
+			layer, lw = v.Layer, v.LineWeight
+		case *Ellipse:
+			layer, lw = v.Layer, v.LineWeight
+		}
+		if lw <= 0 {
+			continue
+		}
+		if counts[layer] == nil {
+			counts[layer] = make(map[int]int)
+		}
+		counts[layer][lw]++
+	}
+}
+
+// assign sets the lineweight of each layer to its most used one.
+func (counts lineWeightCounts) assign(layers []Layer) {
+	for i := range layers {
+		best, bestCount := 0, 0
+		for lw, n := range counts[layers[i].Name] {
+			if n > bestCount || (n == bestCount && lw < best) {
+				best, bestCount = lw, n
+			}
 		}
-		return int(jwwColor)
+		layers[i].LineWeight = best
 	}
 }
 
-// mapLineType maps JWW pen style numbers to DXF linetype names.
+// mapLineType maps JWW pen style numbers to standard DXF linetype names.
+// It is the fallback used by lineTypeName when the file header has no
+// pattern for the line type.
 //
 // JWW uses numeric line types for common patterns:
 //   - 1: continuous (実線)
@@ -2,6 +2,8 @@ package dxf
 
 import (
 	"math"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/f4ah6o/jww-parser/jww"
@@ -324,7 +326,7 @@ func TestConvertSolid(t *testing.T) {
 	doc := createTestDocument()
 	doc.Entities = []jww.Entity{solid}
 
-	result := ConvertDocument(doc)
+	result := ConvertDocument(doc, WithSolidFill(SolidFillSolid))
 
 	if len(result.Entities) != 1 {
 		t.Fatalf("expected 1 entity, got %d", len(result.Entities))
@@ -340,6 +342,147 @@ func TestConvertSolid(t *testing.T) {
 	}
 }
 
+func TestConvertCircleSolid(t *testing.T) {
+	tests := []struct {
+		name     string
+		solid    jww.CircleSolid
+		wantArea float64
+	}{
+		{
+			name:     "disc",
+			solid:    jww.CircleSolid{EntityBase: jww.EntityBase{PenStyle: 101}, Radius: 10, Flatness: 1, Param: 100},
+			wantArea: math.Pi * 100,
+		},
+		{
+			name:     "sector",
+			solid:    jww.CircleSolid{EntityBase: jww.EntityBase{PenStyle: 101}, Radius: 10, Flatness: 1, ArcAngle: math.Pi / 2},
+			wantArea: math.Pi * 100 / 4,
+		},
+		{
+			name:     "segment",
+			solid:    jww.CircleSolid{EntityBase: jww.EntityBase{PenStyle: 101}, Radius: 10, Flatness: 1, ArcAngle: math.Pi, Param: 5},
+			wantArea: math.Pi * 100 / 2,
+		},
+		{
+			name:     "outer arc",
+			solid:    jww.CircleSolid{EntityBase: jww.EntityBase{PenStyle: 101}, Radius: 10, Flatness: 1, ArcAngle: math.Pi / 2, Param: -1},
+			wantArea: 100 - math.Pi*100/4,
+		},
+		{
+			name:     "ring",
+			solid:    jww.CircleSolid{EntityBase: jww.EntityBase{PenStyle: 105}, Radius: 10, Flatness: 1, ArcAngle: 2 * math.Pi, Param: 5},
+			wantArea: math.Pi * (100 - 25),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := createTestDocument()
+			doc.Entities = []jww.Entity{&tt.solid}
+
+			result := ConvertDocument(doc, WithSolidFill(SolidFillSolid))
+
+			var area float64
+			for _, e := range result.Entities {
+				s, ok := e.(*Solid)
+				if !ok {
+					t.Fatalf("expected *Solid, got %T", e)
+				}
+				area += dxfSolidArea(s)
+			}
+			if math.Abs(area-tt.wantArea)/tt.wantArea > 0.01 {
+				t.Errorf("area: got %v, want %v", area, tt.wantArea)
+			}
+
+			result = ConvertDocument(doc)
+
+			if len(result.Entities) != 1 {
+				t.Fatalf("expected 1 hatch, got %d entities", len(result.Entities))
+			}
+			h, ok := result.Entities[0].(*Hatch)
+			if !ok {
+				t.Fatalf("expected *Hatch, got %T", result.Entities[0])
+			}
+			if area := h.Area(); math.Abs(area-tt.wantArea)/tt.wantArea > 0.01 {
+				t.Errorf("hatch area: got %v, want %v", area, tt.wantArea)
+			}
+		})
+	}
+}
+
+// dxfSolidArea returns the area of a SOLID using the DXF vertex order, in
+// which the outline runs 1-2-4-3.
+func dxfSolidArea(s *Solid) float64 {
+	xs := []float64{s.X1, s.X2, s.X4, s.X3}
+	ys := []float64{s.Y1, s.Y2, s.Y4, s.Y3}
+	var sum float64
+	for i := range xs {
+		j := (i + 1) % len(xs)
+		sum += xs[i]*ys[j] - xs[j]*ys[i]
+	}
+	return math.Abs(sum) / 2
+}
+
+func TestConvertCircleSolid_Circumference(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.CircleSolid{
+		EntityBase: jww.EntityBase{PenStyle: 111},
+		CenterX:    5, Radius: 10, Flatness: 1, Param: 100,
+	}}
+
+	result := ConvertDocument(doc)
+
+	if len(result.Entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(result.Entities))
+	}
+	if c, ok := result.Entities[0].(*Circle); !ok || c.Radius != 10 {
+		t.Errorf("expected circle with radius 10, got %#v", result.Entities[0])
+	}
+}
+
+func TestConvertCircleSolid_CircumferenceWidth(t *testing.T) {
+	// 0.5 mm at 1:10 is a band 5 units wide
+	doc := createTestDocument()
+	doc.Header.MaxDrawWidth = -101
+	doc.LayerGroups[0].Scale = 10
+	cs := &jww.CircleSolid{
+		EntityBase: jww.EntityBase{PenStyle: 111, PenWidth: 50},
+		Radius:     100, Flatness: 1, ArcAngle: math.Pi / 2,
+	}
+
+	hatch := convertCircleSolidHatch(cs, doc, newConvertConfig(nil))
+	if hatch == nil || len(hatch.Boundaries) != 1 {
+		t.Fatalf("expected a hatch with one boundary, got %+v", hatch)
+	}
+	edges := hatch.Boundaries[0].Edges
+	if len(edges) != 4 || edges[0].Radius != 102.5 || edges[2].Radius != 97.5 {
+		t.Errorf("expected arcs of radius 102.5 and 97.5, got %+v", edges)
+	}
+	want := math.Pi / 4 * (102.5*102.5 - 97.5*97.5)
+	if area := hatch.Area(); math.Abs(area-want) > 0.01*want {
+		t.Errorf("area: got %v, want %v", area, want)
+	}
+
+	solids := convertCircleSolid(cs, doc, newConvertConfig(nil))
+	if len(solids) != circleSolidSegments/4 {
+		t.Fatalf("expected %d solids, got %d", circleSolidSegments/4, len(solids))
+	}
+	if s := solids[0].(*Solid); s.X1 != 102.5 || s.X3 != 97.5 {
+		t.Errorf("first solid: got %+v", s)
+	}
+
+	// Without a known width the outline remains
+	cs.PenWidth = 0
+	if hatch := convertCircleSolidHatch(cs, doc, newConvertConfig(nil)); hatch != nil {
+		t.Errorf("expected no hatch, got %+v", hatch)
+	}
+	if e := convertCircleSolid(cs, doc, newConvertConfig(nil)); len(e) != 1 {
+		t.Errorf("expected the outline arc, got %d entities", len(e))
+	} else if _, ok := e[0].(*Arc); !ok {
+		t.Errorf("expected *Arc, got %T", e[0])
+	}
+}
+
 func TestConvertBlock(t *testing.T) {
 	block := &jww.Block{
 		EntityBase: jww.EntityBase{
@@ -387,6 +530,47 @@ func TestConvertBlock(t *testing.T) {
 	}
 }
 
+func TestConvertDimension_Exploded(t *testing.T) {
+	dim := &jww.Dimension{
+		Line: jww.Line{
+			EntityBase: jww.EntityBase{PenColor: 1},
+			StartX:     0, StartY: 100, EndX: 3640, EndY: 100,
+		},
+		Text: jww.Text{
+			EntityBase: jww.EntityBase{PenColor: 1},
+			StartX:     1800, StartY: 110, EndX: 1830, EndY: 110,
+			SizeX: 2.5, SizeY: 2.5,
+			Content: "3,640",
+		},
+		AuxLines: [2]jww.Line{
+			{StartX: 0, StartY: 0, EndX: 0, EndY: 110},
+			{}, // degenerate: not emitted
+		},
+	}
+
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{dim}
+
+	result := ConvertDocument(doc, WithExplodedDimensions())
+
+	if len(result.Entities) != 3 {
+		t.Fatalf("expected 3 entities, got %d", len(result.Entities))
+	}
+	if l, ok := result.Entities[0].(*Line); !ok || l.X2 != 3640 {
+		t.Errorf("dimension line: got %#v", result.Entities[0])
+	}
+	if l, ok := result.Entities[1].(*Line); !ok || l.Y2 != 110 {
+		t.Errorf("aux line: got %#v", result.Entities[1])
+	}
+	txt, ok := result.Entities[2].(*Text)
+	if !ok {
+		t.Fatalf("expected *Text, got %T", result.Entities[2])
+	}
+	if txt.Content != "3,640" {
+		t.Errorf("text: got %q, want %q", txt.Content, "3,640")
+	}
+}
+
 func TestMapColor(t *testing.T) {
 	tests := []struct {
 		jwwColor uint16
@@ -403,8 +587,8 @@ func TestMapColor(t *testing.T) {
 		{7, 7, "JWW黒/白->DXF white"},
 		{8, 1, "JWW赤->DXF red"},
 		{9, 8, "JWWグレー->DXF gray"},
-		{100, 10, "Extended color"},
-		{150, 60, "Extended color"},
+		{100, 7, "Extended color without table"},
+		{150, 7, "Extended color without table"},
 	}
 
 	for _, tt := range tests {
@@ -417,6 +601,85 @@ func TestMapColor(t *testing.T) {
 	}
 }
 
+func TestEntityColor_SXF(t *testing.T) {
+	doc := createTestDocument()
+	doc.Header.SXFColors = make([]jww.SXFColor, 257)
+	doc.Header.SXFColors[5].PrinterColor = 0x00336699 // COLORREF: R=0x99 G=0x66 B=0x33
+	doc.Header.SXFColors[5].Name = "土色"
+
+	aci, trueColor, name := entityColor(doc, 105)
+	if trueColor == nil || *trueColor != 0x996633 {
+		t.Fatalf("true color: got %v, want 0x996633", trueColor)
+	}
+	if aci != NearestACI(0x996633) {
+		t.Errorf("ACI fallback: got %d, want %d", aci, NearestACI(0x996633))
+	}
+	if name != "土色" {
+		t.Errorf("color name: got %q, want 土色", name)
+	}
+
+	if aci, trueColor, name := entityColor(doc, 3); aci != 3 || trueColor != nil || name != "" {
+		t.Errorf("standard color: got %d/%v/%q, want 3/nil/\"\"", aci, trueColor, name)
+	}
+}
+
+func TestConvertText_SXFColor(t *testing.T) {
+	doc := createTestDocument()
+	doc.Header.SXFColors = make([]jww.SXFColor, 257)
+	doc.Header.SXFColors[5].PrinterColor = 0x00336699
+	doc.Header.SXFColors[5].Name = "土色"
+	doc.Entities = []jww.Entity{&jww.Text{
+		EntityBase: jww.EntityBase{PenColor: 105},
+		SizeX:      2.5, SizeY: 2.5,
+		Content: "A",
+	}}
+
+	result := ConvertDocument(doc)
+
+	text := result.Entities[0].(*Text)
+	codes := text.GroupCodes()
+	for k, gc := range codes {
+		if gc.Code == 62 {
+			if k+2 >= len(codes) || codes[k+1].Code != 420 || codes[k+1].Value != 0x996633 {
+				t.Fatalf("expected group 420 after 62, got %v", codes[k+1:])
+			}
+			if codes[k+2].Code != 430 || codes[k+2].Value != EscapeUnicode("土色") {
+				t.Errorf("expected group 430 after 420, got %v", codes[k+2])
+			}
+			return
+		}
+	}
+	t.Error("group 62 not written")
+}
+
+func TestConvertSolid_RGBColor(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.Solid{
+		EntityBase: jww.EntityBase{PenColor: 10},
+		Point2X:    1, Point3X: 1, Point3Y: 1,
+		Color: 0x000000FF, // COLORREF red
+	}}
+
+	result := ConvertDocument(doc, WithSolidFill(SolidFillSolid))
+
+	solid := result.Entities[0].(*Solid)
+	if solid.TrueColor == nil || *solid.TrueColor != 0xFF0000 {
+		t.Errorf("true color: got %v, want 0xFF0000", solid.TrueColor)
+	}
+	if solid.Color != 1 {
+		t.Errorf("ACI fallback: got %d, want 1", solid.Color)
+	}
+
+	codes := solid.GroupCodes()
+	for k, gc := range codes {
+		if gc.Code == 62 {
+			if k+1 >= len(codes) || codes[k+1].Code != 420 || codes[k+1].Value != 0xFF0000 {
+				t.Errorf("expected group 420 after 62, got %v", codes[k+1])
+			}
+		}
+	}
+}
+
 func TestConvertLayers(t *testing.T) {
 	doc := createTestDocument()
 
@@ -428,6 +691,45 @@ func TestConvertLayers(t *testing.T) {
 	}
 }
 
+func TestLayerNames(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[0].Layers[1].Name = "壁"
+	doc.LayerGroups[0].Layers[2].Name = "寸法"
+	doc.LayerGroups[1].Layers[2].Name = "寸法"
+	doc.LayerGroups[2].Layers[3].Name = "A/B"
+	doc.LayerGroups[2].Layers[4].Name = "2-4"
+	doc.LayerGroups[3].Layers[0].Name = "Wall"
+	doc.LayerGroups[3].Layers[1].Name = "WALL"
+
+	tests := []struct {
+		group, layer uint16
+		expected     string
+	}{
+		{0, 0, "0-0"},
+		{0, 1, "壁"},
+		{0, 2, "0-2_寸法"},
+		{1, 2, "1-2_寸法"},
+		{2, 3, "A_B"},
+		{2, 4, "2-4"},
+		{3, 0, "3-0_Wall"},
+		{3, 1, "3-1_WALL"},
+		{15, 10, "F-A"},
+		{16, 0, "10-0"},
+	}
+
+	names := newLayerNames(doc)
+	for _, tt := range tests {
+		if got := names.name(tt.group, tt.layer); got != tt.expected {
+			t.Errorf("layer name (%d, %d) = %q, want %q", tt.group, tt.layer, got, tt.expected)
+		}
+	}
+
+	result := ConvertDocument(doc)
+	if !result.HasLayer("壁") {
+		t.Error("expected DXF layer named 壁")
+	}
+}
+
 func TestConvertBlocks(t *testing.T) {
 	line := &jww.Line{
 		EntityBase: jww.EntityBase{PenColor: 1},
@@ -460,6 +762,60 @@ func TestConvertBlocks(t *testing.T) {
 	}
 }
 
+func TestConvertBlocks_NamesAndKinds(t *testing.T) {
+	doc := createTestDocument()
+	doc.BlockDefs = []jww.BlockDef{
+		{Number: 1, Name: " 窓 "},
+		{Number: 2, Name: "DOOR", Kind: jww.BlockKindPartialSurvey},
+		{Number: 3, Name: "door"},
+		{Number: 4, Name: "A/B"},
+		{Number: 5},
+	}
+	doc.Entities = []jww.Entity{&jww.Block{DefNumber: 1}, &jww.Block{DefNumber: 6}}
+
+	result := ConvertDocument(doc)
+
+	want := []struct {
+		name        string
+		description string
+	}{
+		{"窓", ""},
+		{"DOOR_2", "Jw_cad partial figure (survey coordinates)"},
+		{"door_3", ""},
+		{"A_B", ""},
+		{"BLOCK_5", ""},
+	}
+	if len(result.Blocks) != len(want) {
+		t.Fatalf("expected %d blocks, got %d", len(want), len(result.Blocks))
+	}
+	for i, w := range want {
+		b := result.Blocks[i]
+		if b.Name != w.name || b.Description != w.description {
+			t.Errorf("block %d: got %q (%q), want %q (%q)", i, b.Name, b.Description, w.name, w.description)
+		}
+	}
+
+	out := ToString(result)
+	if !strings.Contains(out, "  3\nDOOR_2\n  4\nJw_cad partial figure (survey coordinates)\n") {
+		t.Error("expected the block description in group code 4")
+	}
+
+	// Inserts use the same names; block names are escaped like layer names
+	if name := result.Entities[0].(*Insert).BlockName; name != "窓" {
+		t.Errorf("insert block name: got %q, want 窓", name)
+	}
+	if name := result.Entities[1].(*Insert).BlockName; name != "BLOCK_6" {
+		t.Errorf("insert of an undefined block: got %q, want BLOCK_6", name)
+	}
+	escaped := EscapeUnicode("窓")
+	if !strings.Contains(out, "BLOCK\n  8\n0\n  2\n"+escaped+"\n") || !strings.Contains(out, "  3\n"+escaped+"\n") {
+		t.Error("expected the escaped block name in groups 2 and 3 of BLOCK")
+	}
+	if !strings.Contains(out, "INSERT\n") || strings.Contains(out, "窓") {
+		t.Error("expected no unescaped block name in the output")
+	}
+}
+
 // createTestDocument creates a minimal JWW document for testing.
 func createTestDocument() *jww.Document {
 	doc := &jww.Document{
@@ -481,3 +837,105 @@ func createTestDocument() *jww.Document {
 
 	return doc
 }
+
+func TestEntityLineWeight(t *testing.T) {
+	doc := createTestDocument()
+	doc.Header.Pens[2].PrinterWidth = 6 // Dots at 600 dpi: 0.254 mm
+	doc.Header.SXFColors = make([]jww.SXFColor, 257)
+	doc.Header.SXFColors[3].PrinterWidth = 12
+
+	if got := entityLineWeight(doc, &jww.EntityBase{PenColor: 2}); got != 25 {
+		t.Errorf("color 2 at default dpi: got %d, want 25", got)
+	}
+	if got := entityLineWeight(doc, &jww.EntityBase{PenColor: 103}); got != 50 {
+		t.Errorf("SXF color 103 at default dpi: got %d, want 50", got)
+	}
+	if got := entityLineWeight(doc, &jww.EntityBase{PenColor: 5}); got != 0 {
+		t.Errorf("color without width: got %d, want 0", got)
+	}
+
+	doc.Header.PrintSkipDisplayOnly = 10 // 300 dpi
+	if got := entityLineWeight(doc, &jww.EntityBase{PenColor: 2}); got != 50 {
+		t.Errorf("color 2 at 300 dpi: got %d, want 50", got)
+	}
+
+	// 1/100 mm mode: the entity width wins, the color width is in 1/100 mm
+	doc.Header.MaxDrawWidth = -101
+	if got := entityLineWeight(doc, &jww.EntityBase{PenColor: 2, PenWidth: 70}); got != 70 {
+		t.Errorf("entity width: got %d, want 70", got)
+	}
+	if got := entityLineWeight(doc, &jww.EntityBase{PenColor: 2}); got != 5 {
+		t.Errorf("color 2 in 1/100 mm: got %d, want 5", got)
+	}
+}
+
+func TestConvertDocument_LineWeights(t *testing.T) {
+	doc := createTestDocument()
+	doc.Header.MaxDrawWidth = -101
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{PenWidth: 35}, EndX: 1},
+		&jww.Line{EntityBase: jww.EntityBase{PenWidth: 35}, EndX: 2},
+		&jww.Arc{EntityBase: jww.EntityBase{PenWidth: 50}, Radius: 1, Flatness: 1, IsFullCircle: true},
+	}
+
+	result := ConvertDocument(doc)
+
+	if got := result.Entities[2].(*Circle).LineWeight; got != 50 {
+		t.Errorf("circle lineweight: got %d, want 50", got)
+	}
+	layer := result.GetLayer(newLayerNames(doc).name(0, 0))
+	if layer == nil || layer.LineWeight != 35 {
+		t.Fatalf("layer lineweight: got %+v, want 35", layer)
+	}
+	if other := result.GetLayer(newLayerNames(doc).name(0, 1)); other.LineWeight != 0 {
+		t.Errorf("empty layer lineweight: got %d, want 0", other.LineWeight)
+	}
+
+	out := ToString(result)
+	if !strings.Contains(out, "370\n35\n") || !strings.Contains(out, "370\n-3\n") {
+		t.Error("expected lineweights in the layer table and entities")
+	}
+}
+
+func TestConvertDocument_Progress(t *testing.T) {
+	doc := createTestDocument()
+	for i := 0; i < progressInterval+1; i++ {
+		doc.Entities = append(doc.Entities, &jww.Line{EndX: float64(i)})
+	}
+	doc.BlockDefs = []jww.BlockDef{{Number: 1, Name: "B", Entities: []jww.Entity{&jww.Point{}}}}
+
+	var reports []jww.Progress
+	dxfDoc := ConvertDocument(doc, WithProgress(func(p jww.Progress) { reports = append(reports, p) }))
+
+	total := progressInterval + 2
+	want := []jww.Progress{
+		{Stage: jww.StageConverting, TotalEntities: total},
+		{Stage: jww.StageConverting, Entities: progressInterval, TotalEntities: total},
+		{Stage: jww.StageConverting, Entities: total, TotalEntities: total},
+	}
+	if !slices.Equal(reports, want) {
+		t.Errorf("ConvertDocument reports: got %+v, want %+v", reports, want)
+	}
+
+	var sb strings.Builder
+	reports = nil
+	w := NewWriter(&sb)
+	w.SetProgress(func(p jww.Progress) { reports = append(reports, p) })
+	if err := w.WriteDocument(dxfDoc); err != nil {
+		t.Fatalf("WriteDocument failed: %v", err)
+	}
+
+	n := len(dxfDoc.Entities)
+	if len(reports) != 3 {
+		t.Fatalf("expected 3 WriteDocument reports, got %+v", reports)
+	}
+	if p := reports[0]; p != (jww.Progress{Stage: jww.StageWriting, TotalEntities: n}) {
+		t.Errorf("first report: got %+v", p)
+	}
+	if p := reports[1]; p.Entities != progressInterval || p.Bytes == 0 {
+		t.Errorf("second report: got %+v", p)
+	}
+	if p := reports[2]; p.Entities != n || p.Bytes != int64(sb.Len()) {
+		t.Errorf("last report: got %+v, want %d entities and %d bytes", p, n, sb.Len())
+	}
+}
@@ -0,0 +1,308 @@
+package dxf
+
+import (
+	"math"
+	"strings"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// jwwDimStyleName is the name of the dimension style created from the JWW
+// dimension settings (寸法設定).
+const jwwDimStyleName = "JWW"
+
+// convertDimStyles creates the dimension style described by the JWW
+// dimension settings. Drawings saved without them use the STANDARD style and
+// get no style of their own.
+//
+// Sizes are paper millimetres scaled by the write layer group scale. Rounding
+// modes other than half up, thousands separators and full-width digits have
+// no DIMSTYLE equivalent; the converted dimensions keep the JWW value text.
+func convertDimStyles(doc *jww.Document) []DimStyle {
+	ds, ok := doc.Header.DimensionStyle()
+	if !ok {
+		return nil
+	}
+
+	style := DimStyle{
+		Name:                  jwwDimStyleName,
+		Scale:                 layerGroupScale(doc, uint16(doc.WriteLayerGroup)),
+		ArrowSize:             ds.ArrowLength,
+		ExtLineExtension:      math.Max(ds.ExtLineOvershoot, 0),
+		TextGap:               math.Abs(ds.TextOffset),
+		TextAbove:             true,
+		Decimals:              ds.Decimals,
+		SuppressTrailingZeros: true,
+		AngleDecimals:         ds.AngleDecimals,
+		LineColor:             dimColor(ds.LineColor),
+		ExtLineColor:          dimColor(ds.ExtLineColor),
+	}
+
+	switch ds.EndMark {
+	case jww.DimEndDot:
+		style.ArrowBlock = "_DOT"
+	case jww.DimEndArrow:
+		style.ArrowBlock = "_OPEN"
+		if ds.ArrowAngle == 15 {
+			style.ArrowBlock = "_OPEN30"
+		}
+	case jww.DimEndReverseArrow:
+		// Reverse arrows only survive in the dimension blocks
+		style.ArrowBlock = "_OPEN"
+	}
+
+	if n := ds.TextType; n >= 1 && n <= 10 {
+		style.TextHeight = doc.Header.TextStyles[n-1].Height
+	}
+	if ds.Meters {
+		style.LinearFactor = 0.001
+	}
+	if ds.ShowUnit {
+		style.Suffix = "mm"
+		if ds.Meters {
+			style.Suffix = "m"
+		}
+	}
+	if ds.AngleUnit == jww.DimAngleDMS {
+		style.AngleUnit = 1
+	}
+
+	return []DimStyle{style}
+}
+
+// dimColor maps a JWW dimension setting color (1-9) to an ACI color, or 0
+// (BYBLOCK) when it is not set.
+func dimColor(c int) int {
+	if c <= 0 || c > 9 {
+		return 0
+	}
+	return mapColor(uint16(c))
+}
+
+// dimStyleName returns the dimension style used by converted dimensions.
+func dimStyleName(doc *jww.Document) string {
+	if _, ok := doc.Header.DimensionStyle(); ok {
+		return jwwDimStyleName
+	}
+	return StandardDimStyle.Name
+}
+
+// convertDimensionEntity converts a JWW dimension (寸法図形) to a DXF
+// DIMENSION whose anonymous block holds the entities of convertDimension.
+//
+// JWW dimensions only store a straight dimension line, so the kind is taken
+// from the role flags of the value text (see dimensionType): an angle value
+// makes an angular dimension (measured between the auxiliary lines), a
+// diameter or radius value the matching dimension; all others are linear.
+// The value text is kept as the text override. It returns nil when the
+// dimension cannot be represented, e.g. an angular dimension without
+// auxiliary lines.
+func convertDimensionEntity(dim *jww.Dimension, doc *jww.Document, cfg *convertConfig) *Dimension {
+	ln := &dim.Line
+	if ln.StartX == ln.EndX && ln.StartY == ln.EndY {
+		return nil
+	}
+	geometry := convertDimension(dim, doc, cfg)
+	if len(geometry) == 0 {
+		return nil
+	}
+
+	color, trueColor, colorName := entityColor(doc, ln.PenColor)
+	d := &Dimension{
+		Layer:        cfg.layerName(doc, dim.LayerGroup, dim.Layer),
+		Color:        color,
+		TrueColor:    trueColor,
+		ColorName:    colorName,
+		Geometry:     geometry,
+		Style:        dimStyleName(doc),
+		Text:         strings.TrimSpace(dim.Text.Content),
+		TextRotation: dim.Text.Angle,
+	}
+	d.TextX, d.TextY = dimensionTextMidpoint(&dim.Text, doc)
+
+	switch dimensionType(&dim.Text) {
+	case DimAngular:
+		if !setAngularDimension(d, dim) {
+			return nil
+		}
+
+	case DimRadius:
+		d.DimType = DimRadius
+		cx, cy, px, py := ln.StartX, ln.StartY, ln.EndX, ln.EndY
+		// The arrow sits on the circle, not at the center
+		if nearArrowPoint(dim, cx, cy) && !nearArrowPoint(dim, px, py) {
+			cx, cy, px, py = px, py, cx, cy
+		}
+		d.DefX, d.DefY, d.X3, d.Y3 = cx, cy, px, py
+		d.Measurement = math.Hypot(px-cx, py-cy)
+
+	case DimDiameter:
+		d.DimType = DimDiameter
+		d.DefX, d.DefY, d.X3, d.Y3 = ln.EndX, ln.EndY, ln.StartX, ln.StartY
+		d.Measurement = math.Hypot(ln.EndX-ln.StartX, ln.EndY-ln.StartY)
+
+	default:
+		x1, y1, x2, y2 := measuredPoints(dim)
+		dx, dy := ln.EndX-ln.StartX, ln.EndY-ln.StartY
+		length := math.Hypot(dx, dy)
+		ux, uy := dx/length, dy/length
+		mx, my := x2-x1, y2-y1
+
+		d.DefX, d.DefY = ln.EndX, ln.EndY
+		d.X1, d.Y1, d.X2, d.Y2 = x1, y1, x2, y2
+		d.Measurement = math.Abs(mx*ux + my*uy)
+		if math.Abs(mx*uy-my*ux) <= 1e-9*math.Max(math.Hypot(mx, my), 1) {
+			d.DimType = DimAligned
+		} else {
+			d.DimType = DimLinear
+			d.Rotation = radToDeg(math.Atan2(dy, dx))
+		}
+	}
+
+	return d
+}
+
+// dimensionType returns the kind of a JWW dimension from the role flags of
+// its value text (jww.Text.TextRole). Value texts without role flags, as
+// written by older versions, are recognized from their content instead: a
+// degree sign makes an angular, a leading "φ" a diameter and a leading or
+// trailing "R" a radius dimension.
+func dimensionType(text *jww.Text) int {
+	switch text.TextRole() {
+	case jww.TextRoleAngleValue:
+		return DimAngular
+	case jww.TextRoleDiameterValue:
+		return DimDiameter
+	case jww.TextRoleRadiusValue:
+		return DimRadius
+	case jww.TextRoleDimensionValue, jww.TextRoleCumulativeValue:
+		return DimLinear
+	}
+
+	content := strings.TrimSpace(text.Content)
+	switch {
+	case strings.ContainsAny(content, "°゜ﾟ"):
+		return DimAngular
+	case strings.HasPrefix(content, "φ") || strings.HasPrefix(content, "Φ") || strings.HasPrefix(content, "∅"):
+		return DimDiameter
+	case strings.HasPrefix(content, "R") || strings.HasSuffix(content, "R") ||
+		strings.HasPrefix(content, "Ｒ") || strings.HasSuffix(content, "Ｒ"):
+		return DimRadius
+	}
+	return DimLinear
+}
+
+// measuredPoints returns the points measured by a linear dimension: its
+// reference points (基準点), the outer ends of its auxiliary lines, or the
+// ends of the dimension line when neither is stored.
+func measuredPoints(dim *jww.Dimension) (x1, y1, x2, y2 float64) {
+	r1, r2 := &dim.RefPoints[0], &dim.RefPoints[1]
+	if r1.X != r2.X || r1.Y != r2.Y {
+		return r1.X, r1.Y, r2.X, r2.Y
+	}
+
+	ln := &dim.Line
+	a1, a2 := &dim.AuxLines[0], &dim.AuxLines[1]
+	if !isDegenerateLine(a1) && !isDegenerateLine(a2) {
+		x1, y1 = farEnd(a1, ln)
+		x2, y2 = farEnd(a2, ln)
+		return x1, y1, x2, y2
+	}
+
+	return ln.StartX, ln.StartY, ln.EndX, ln.EndY
+}
+
+// setAngularDimension fills in an angular dimension measured between the
+// auxiliary lines of dim, which meet at the vertex. It reports false when
+// the auxiliary lines are missing or parallel.
+func setAngularDimension(d *Dimension, dim *jww.Dimension) bool {
+	a1, a2 := &dim.AuxLines[0], &dim.AuxLines[1]
+	if isDegenerateLine(a1) || isDegenerateLine(a2) {
+		return false
+	}
+	vx, vy, ok := lineIntersection(a1, a2)
+	if !ok {
+		return false
+	}
+
+	x1, y1 := farFrom(a1, vx, vy)
+	x2, y2 := farFrom(a2, vx, vy)
+
+	// The arc passes through the dimension line ends; place the arc point on
+	// the bisector of the line at the radius of its start point
+	ln := &dim.Line
+	radius := math.Hypot(ln.StartX-vx, ln.StartY-vy)
+	mx, my := (ln.StartX+ln.EndX)/2-vx, (ln.StartY+ln.EndY)/2-vy
+	if m := math.Hypot(mx, my); m > 0 {
+		mx, my = mx/m*radius, my/m*radius
+	}
+
+	d.DimType = DimAngular
+	d.X1, d.Y1, d.X2, d.Y2 = vx, vy, x1, y1
+	d.X3, d.Y3, d.DefX, d.DefY = vx, vy, x2, y2
+	d.X4, d.Y4 = vx+mx, vy+my
+
+	angle := math.Abs(math.Atan2(y2-vy, x2-vx) - math.Atan2(y1-vy, x1-vx))
+	if angle > math.Pi {
+		angle = 2*math.Pi - angle
+	}
+	d.Measurement = radToDeg(angle)
+	return true
+}
+
+// dimensionTextMidpoint returns the middle of the value text box, which
+// starts at the text's start point and runs to its end point.
+func dimensionTextMidpoint(t *jww.Text, doc *jww.Document) (float64, float64) {
+	_, height := jwwTextStyle(t, doc)
+	sin, cos := math.Sincos(t.Angle * math.Pi / 180)
+	return (t.StartX+t.EndX)/2 - height/2*sin, (t.StartY+t.EndY)/2 + height/2*cos
+}
+
+// nearArrowPoint reports whether (x, y) is one of the dimension's arrow points.
+func nearArrowPoint(dim *jww.Dimension, x, y float64) bool {
+	for i := range dim.ArrowPoints {
+		p := &dim.ArrowPoints[i]
+		if math.Hypot(p.X-x, p.Y-y) < 1e-6 {
+			return true
+		}
+	}
+	return false
+}
+
+// isDegenerateLine reports whether a line has no length (or is not stored).
+func isDegenerateLine(l *jww.Line) bool {
+	return l.StartX == l.EndX && l.StartY == l.EndY
+}
+
+// farEnd returns the end of aux farther from the infinite line through ln.
+func farEnd(aux, ln *jww.Line) (float64, float64) {
+	dx, dy := ln.EndX-ln.StartX, ln.EndY-ln.StartY
+	dist := func(x, y float64) float64 {
+		return math.Abs((x-ln.StartX)*dy - (y-ln.StartY)*dx)
+	}
+	if dist(aux.StartX, aux.StartY) >= dist(aux.EndX, aux.EndY) {
+		return aux.StartX, aux.StartY
+	}
+	return aux.EndX, aux.EndY
+}
+
+// farFrom returns the end of l farther from (x, y).
+func farFrom(l *jww.Line, x, y float64) (float64, float64) {
+	if math.Hypot(l.StartX-x, l.StartY-y) >= math.Hypot(l.EndX-x, l.EndY-y) {
+		return l.StartX, l.StartY
+	}
+	return l.EndX, l.EndY
+}
+
+// lineIntersection returns the intersection of the infinite lines through a
+// and b, reporting false for parallel lines.
+func lineIntersection(a, b *jww.Line) (float64, float64, bool) {
+	adx, ady := a.EndX-a.StartX, a.EndY-a.StartY
+	bdx, bdy := b.EndX-b.StartX, b.EndY-b.StartY
+	den := adx*bdy - ady*bdx
+	if math.Abs(den) < 1e-12*math.Hypot(adx, ady)*math.Hypot(bdx, bdy) {
+		return 0, 0, false
+	}
+	t := ((b.StartX-a.StartX)*bdy - (b.StartY-a.StartY)*bdx) / den
+	return a.StartX + t*adx, a.StartY + t*ady, true
+}
@@ -0,0 +1,235 @@
+package dxf
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+func TestConvertDimStyles(t *testing.T) {
+	doc := createTestDocument()
+	if styles := convertDimStyles(doc); styles != nil {
+		t.Errorf("expected no styles without settings, got %+v", styles)
+	}
+	if got := dimStyleName(doc); got != "STANDARD" {
+		t.Errorf("style name: got %q, want STANDARD", got)
+	}
+
+	doc.LayerGroups[0].Scale = 50
+	doc.Header.TextStyles[2].Height = 3.5
+	doc.Header.DimensionSettings = [5]uint32{
+		3112128, // text type 3, arrows, metres, 2 decimals, colors 8/2/1
+		201015,  // overshoot 2.0, offset -1.5
+		150030,  // arrow angle 15.0, length 3.0
+		100000,  // show unit
+		1100,    // 1 angle decimal, degrees/minutes/seconds
+	}
+
+	styles := convertDimStyles(doc)
+	if len(styles) != 1 {
+		t.Fatalf("expected 1 style, got %d", len(styles))
+	}
+	want := DimStyle{
+		Name:                  "JWW",
+		Scale:                 50,
+		ArrowSize:             3,
+		ArrowBlock:            "_OPEN30",
+		ExtLineExtension:      2,
+		TextHeight:            3.5,
+		TextGap:               1.5,
+		TextAbove:             true,
+		LinearFactor:          0.001,
+		Suffix:                "m",
+		Decimals:              2,
+		SuppressTrailingZeros: true,
+		AngleUnit:             1,
+		AngleDecimals:         1,
+		LineColor:             1, // JWW red
+		ExtLineColor:          7, // JWW white
+	}
+	if styles[0] != want {
+		t.Errorf("got %+v\nwant %+v", styles[0], want)
+	}
+}
+
+func TestConvertDimensionEntity_Linear(t *testing.T) {
+	doc := createTestDocument()
+	dim := &jww.Dimension{
+		Line: jww.Line{StartX: 0, StartY: 100, EndX: 3640, EndY: 100},
+		Text: jww.Text{StartX: 1800, StartY: 101, EndX: 1840, EndY: 101, SizeY: 2, Content: "3,640"},
+		AuxLines: [2]jww.Line{
+			{StartX: 0, StartY: 0, EndX: 0, EndY: 102},
+			{StartX: 3640, StartY: -50, EndX: 3640, EndY: 102},
+		},
+	}
+
+	d := convertDimensionEntity(dim, doc, newConvertConfig(nil))
+	if d == nil {
+		t.Fatal("expected a dimension")
+	}
+	if d.DimType != DimLinear || d.Rotation != 0 {
+		t.Errorf("type: got %d rotation %v, want linear at 0°", d.DimType, d.Rotation)
+	}
+	if d.X1 != 0 || d.Y1 != 0 || d.X2 != 3640 || d.Y2 != -50 {
+		t.Errorf("measured points: got (%v,%v) (%v,%v)", d.X1, d.Y1, d.X2, d.Y2)
+	}
+	if d.Measurement != 3640 || d.Text != "3,640" || d.Style != "STANDARD" {
+		t.Errorf("measurement %v text %q style %q", d.Measurement, d.Text, d.Style)
+	}
+	if d.TextX != 1820 || d.TextY != 102 {
+		t.Errorf("text midpoint: got (%v, %v), want (1820, 102)", d.TextX, d.TextY)
+	}
+	if len(d.Geometry) != 4 {
+		t.Errorf("expected line, 2 aux lines and text in the block, got %d entities", len(d.Geometry))
+	}
+
+	// Without auxiliary lines the dimension line ends are measured
+	dim.AuxLines = [2]jww.Line{}
+	d = convertDimensionEntity(dim, doc, newConvertConfig(nil))
+	if d.DimType != DimAligned || d.X2 != 3640 || d.Y2 != 100 {
+		t.Errorf("aligned: got type %d point (%v,%v)", d.DimType, d.X2, d.Y2)
+	}
+}
+
+func TestConvertDimensionEntity_RadiusDiameter(t *testing.T) {
+	doc := createTestDocument()
+	dim := &jww.Dimension{
+		Line:        jww.Line{StartX: 10, StartY: 0, EndX: 0, EndY: 0},
+		Text:        jww.Text{Content: "R10"},
+		ArrowPoints: [2]jww.Point{{X: 10}, {X: 10}},
+	}
+
+	d := convertDimensionEntity(dim, doc, newConvertConfig(nil))
+	if d.DimType != DimRadius || d.DefX != 0 || d.X3 != 10 || d.Measurement != 10 {
+		t.Errorf("radius: got type %d center %v point %v measurement %v", d.DimType, d.DefX, d.X3, d.Measurement)
+	}
+
+	dim.Text.Content = "φ10"
+	d = convertDimensionEntity(dim, doc, newConvertConfig(nil))
+	if d.DimType != DimDiameter || d.Measurement != 10 {
+		t.Errorf("diameter: got type %d measurement %v", d.DimType, d.Measurement)
+	}
+}
+
+func TestDimensionType_RoleFlags(t *testing.T) {
+	tests := []struct {
+		content string
+		flag    uint16
+		want    int
+	}{
+		// The role flags take precedence over the value text
+		{"10", 0x0100, DimRadius},
+		{"10", 0x0200, DimDiameter},
+		{"90", 0x0400, DimAngular},
+		{"R10", 0x0010, DimLinear},
+		// Value texts without role flags
+		{"R10", 0, DimRadius},
+		{" φ10", 0, DimDiameter},
+		{"90°", 0, DimAngular},
+		{"3,640", 0, DimLinear},
+	}
+	for _, tt := range tests {
+		text := &jww.Text{EntityBase: jww.EntityBase{Flag: tt.flag}, Content: tt.content}
+		if got := dimensionType(text); got != tt.want {
+			t.Errorf("dimensionType(%q, flag 0x%04x) = %d, want %d", tt.content, tt.flag, got, tt.want)
+		}
+	}
+
+	// A radius dimension whose value has no "R"
+	dim := &jww.Dimension{
+		Line:        jww.Line{StartX: 10, StartY: 0, EndX: 0, EndY: 0},
+		Text:        jww.Text{EntityBase: jww.EntityBase{Flag: 0x0100}, Content: "10"},
+		ArrowPoints: [2]jww.Point{{X: 10}, {X: 10}},
+	}
+	if d := convertDimensionEntity(dim, createTestDocument(), newConvertConfig(nil)); d.DimType != DimRadius || d.Measurement != 10 {
+		t.Errorf("radius: got type %d measurement %v", d.DimType, d.Measurement)
+	}
+}
+
+func TestConvertDimensionEntity_Angular(t *testing.T) {
+	doc := createTestDocument()
+	dim := &jww.Dimension{
+		Line: jww.Line{StartX: 10, StartY: 0, EndX: 0, EndY: 10},
+		Text: jww.Text{Content: "90°"},
+		AuxLines: [2]jww.Line{
+			{StartX: 2, StartY: 0, EndX: 12, EndY: 0},
+			{StartX: 0, StartY: 2, EndX: 0, EndY: 12},
+		},
+	}
+
+	d := convertDimensionEntity(dim, doc, newConvertConfig(nil))
+	if d == nil || d.DimType != DimAngular {
+		t.Fatalf("expected an angular dimension, got %+v", d)
+	}
+	if d.X1 != 0 || d.Y1 != 0 || d.X2 != 12 || d.DefY != 12 {
+		t.Errorf("lines: got (%v,%v)-(%v,%v) and (%v,%v)", d.X1, d.Y1, d.X2, d.Y2, d.DefX, d.DefY)
+	}
+	if math.Abs(d.Measurement-90) > 1e-9 {
+		t.Errorf("measurement: got %v, want 90", d.Measurement)
+	}
+	if r := math.Hypot(d.X4, d.Y4); math.Abs(r-10) > 1e-9 || math.Abs(d.X4-d.Y4) > 1e-9 {
+		t.Errorf("arc point: got (%v, %v)", d.X4, d.Y4)
+	}
+
+	// Angular dimensions need their auxiliary lines
+	dim.AuxLines = [2]jww.Line{}
+	if d := convertDimensionEntity(dim, doc, newConvertConfig(nil)); d != nil {
+		t.Errorf("expected nil without auxiliary lines, got %+v", d)
+	}
+	result := ConvertDocument(&jww.Document{LayerGroups: doc.LayerGroups, Entities: []jww.Entity{dim}})
+	if len(result.Entities) != 2 {
+		t.Errorf("expected the exploded line and text as fallback, got %d entities", len(result.Entities))
+	}
+}
+
+func TestConvertDocument_DimensionBlocks(t *testing.T) {
+	doc := createTestDocument()
+	doc.Header.DimensionSettings = [5]uint32{1000000, 0, 0, 0, 0}
+	dim := &jww.Dimension{
+		Line: jww.Line{StartX: 0, EndX: 100},
+		Text: jww.Text{StartX: 45, EndX: 55, Content: "100"},
+	}
+	doc.Entities = []jww.Entity{dim}
+	doc.BlockDefs = []jww.BlockDef{{Name: "B", Entities: []jww.Entity{dim}}}
+
+	result := ConvertDocument(doc)
+	if len(result.DimStyles) != 1 {
+		t.Fatalf("expected the JWW dimension style, got %d styles", len(result.DimStyles))
+	}
+	if _, ok := result.Entities[0].(*Dimension); !ok {
+		t.Fatalf("expected *Dimension, got %T", result.Entities[0])
+	}
+
+	out := ToString(result)
+	for _, want := range []string{
+		"  2\nDIMSTYLE\n",
+		"105\n",
+		"  2\nJWW\n",
+		"  0\nBLOCK\n  8\n0\n  2\n*D1\n 70\n1\n",
+		"  0\nBLOCK\n  8\n0\n  2\n*D2\n 70\n1\n",
+		"  2\n*D2\n",
+		" 70\n33\n", // Aligned, block referenced by this dimension only
+		"  3\nJWW\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	// The dimension in block B gets *D1, the one in the entities *D2
+	if i, j := strings.Index(out, "  2\n*D1\n 10"), strings.Index(out, "ENTITIES"); i < 0 || i > j {
+		t.Error("expected *D1 to be referenced from the BLOCKS section")
+	}
+}
+
+func TestWriteDimensionBlocks_NameCollision(t *testing.T) {
+	doc := NewDocument()
+	doc.Blocks = []Block{{Name: "*D1"}}
+	doc.Entities = []Entity{&Dimension{Geometry: []Entity{NewLine(0, 0, 1, 0)}}}
+
+	out := ToString(doc)
+	if !strings.Contains(out, "  2\n*D2\n") {
+		t.Error("expected the generated block to skip the existing *D1")
+	}
+}
@@ -110,6 +110,31 @@ func (d *Document) AddText(x, y float64, content string, opts ...TextOption) *Do
 	return d
 }
 
+// AddMText creates and adds an MText entity to the document, returning the document for chaining.
+//
+// Example:
+//
+//	doc := dxf.NewDocument().
+//		AddMText(10, 10, "Line 1\PLine 2",
+//			dxf.WithMTextHeight(5.0),
+//			dxf.WithMTextWidth(100))
+func (d *Document) AddMText(x, y float64, content string, opts ...MTextOption) *Document {
+	d.Entities = append(d.Entities, NewMText(x, y, content, opts...))
+	return d
+}
+
+// AddHatch creates and adds a Hatch entity to the document, returning the document for chaining.
+//
+// Example:
+//
+//	doc := dxf.NewDocument().
+//		AddHatch([]dxf.HatchBoundary{dxf.CircleBoundary(50, 50, 25)},
+//			dxf.WithHatchColor(5))
+func (d *Document) AddHatch(boundaries []HatchBoundary, opts ...HatchOption) *Document {
+	d.Entities = append(d.Entities, NewHatch(boundaries, opts...))
+	return d
+}
+
 // AddSolid creates and adds a Solid entity to the document, returning the document for chaining.
 //
 // Example:
@@ -0,0 +1,173 @@
+package dxf
+
+import "math"
+
+// hatchArcSegments is the number of segments used to flatten a full turn of
+// a curved boundary edge when measuring hatches.
+const hatchArcSegments = 64
+
+// PolygonBoundary returns a polyline hatch boundary through the given
+// coordinates, given as x, y pairs. A trailing odd coordinate is ignored.
+//
+// Example:
+//
+//	square := dxf.PolygonBoundary(0, 0, 10, 0, 10, 10, 0, 10)
+func PolygonBoundary(xy ...float64) HatchBoundary {
+	vertices := make([]HatchVertex, 0, len(xy)/2)
+	for i := 0; i+1 < len(xy); i += 2 {
+		vertices = append(vertices, HatchVertex{X: xy[i], Y: xy[i+1]})
+	}
+	return HatchBoundary{Vertices: vertices}
+}
+
+// CircleBoundary returns an edge hatch boundary along a full circle.
+//
+// Example:
+//
+//	disc := dxf.CircleBoundary(50, 50, 25)
+func CircleBoundary(cx, cy, radius float64) HatchBoundary {
+	return HatchBoundary{Edges: []HatchEdge{ArcEdge(cx, cy, radius, 0, 360)}}
+}
+
+// LineEdge returns a straight hatch boundary edge.
+func LineEdge(x1, y1, x2, y2 float64) HatchEdge {
+	return HatchEdge{Type: HatchEdgeLine, X1: x1, Y1: y1, X2: x2, Y2: y2}
+}
+
+// ArcEdge returns a counterclockwise circular arc hatch boundary edge.
+// Angles are in degrees.
+func ArcEdge(cx, cy, radius, startAngle, endAngle float64) HatchEdge {
+	return HatchEdge{
+		Type:       HatchEdgeArc,
+		CenterX:    cx,
+		CenterY:    cy,
+		Radius:     radius,
+		StartAngle: startAngle,
+		EndAngle:   endAngle,
+	}
+}
+
+// EllipseEdge returns a counterclockwise elliptical arc hatch boundary edge.
+// The major axis endpoint is relative to the center and the start and end
+// parameters are in degrees.
+func EllipseEdge(cx, cy, majorAxisX, majorAxisY, minorRatio, startParam, endParam float64) HatchEdge {
+	return HatchEdge{
+		Type:       HatchEdgeEllipse,
+		CenterX:    cx,
+		CenterY:    cy,
+		MajorAxisX: majorAxisX,
+		MajorAxisY: majorAxisY,
+		MinorRatio: minorRatio,
+		StartAngle: startParam,
+		EndAngle:   endParam,
+	}
+}
+
+// UserPatternLines returns the definition of a user-defined pattern of
+// continuous parallel lines at the given angle (in degrees) and spacing.
+//
+// Example:
+//
+//	hatch := dxf.NewHatch([]dxf.HatchBoundary{square},
+//		dxf.WithHatchPattern("_USER", 45, 2),
+//		dxf.WithHatchPatternLines(dxf.UserPatternLines(45, 2)...))
+func UserPatternLines(angle, spacing float64) []HatchPatternLine {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	return []HatchPatternLine{{
+		Angle:   angle,
+		OffsetX: -sin * spacing,
+		OffsetY: cos * spacing,
+	}}
+}
+
+// outline returns the boundary as a closed polygon (without repeating the
+// first point), flattening arcs, ellipses and bulges.
+func (b *HatchBoundary) outline() [][2]float64 {
+	var pts [][2]float64
+
+	if len(b.Vertices) > 0 {
+		for i, v := range b.Vertices {
+			pts = append(pts, [2]float64{v.X, v.Y})
+			if v.Bulge == 0 {
+				continue
+			}
+			next := b.Vertices[(i+1)%len(b.Vertices)]
+			pts = append(pts, bulgePoints(v, next)...)
+		}
+		return pts
+	}
+
+	for _, e := range b.Edges {
+		switch e.Type {
+		case HatchEdgeArc, HatchEdgeEllipse:
+			pts = append(pts, e.curvePoints()...)
+		default:
+			pts = append(pts, [2]float64{e.X1, e.Y1})
+		}
+	}
+	return pts
+}
+
+// bulgePoints returns the points strictly between two polyline vertices
+// joined by a bulged (arc) segment.
+func bulgePoints(v, next HatchVertex) [][2]float64 {
+	// Included angle and radius from the bulge
+	theta := 4 * math.Atan(v.Bulge)
+	chord := math.Hypot(next.X-v.X, next.Y-v.Y)
+	if chord == 0 {
+		return nil
+	}
+	radius := chord / (2 * math.Sin(math.Abs(theta)/2))
+
+	// Center lies on the chord's perpendicular bisector
+	mx, my := (v.X+next.X)/2, (v.Y+next.Y)/2
+	d := radius * math.Cos(theta/2)
+	nx, ny := -(next.Y-v.Y)/chord, (next.X-v.X)/chord
+	if theta < 0 {
+		nx, ny = -nx, -ny
+	}
+	cx, cy := mx+nx*d, my+ny*d
+
+	start := math.Atan2(v.Y-cy, v.X-cx)
+	n := int(math.Ceil(math.Abs(theta) / (2 * math.Pi) * hatchArcSegments))
+	var pts [][2]float64
+	for i := 1; i < n; i++ {
+		a := start + theta*float64(i)/float64(n)
+		pts = append(pts, [2]float64{cx + radius*math.Cos(a), cy + radius*math.Sin(a)})
+	}
+	return pts
+}
+
+// curvePoints returns the points of an arc or ellipse edge from its start up
+// to, but excluding, its end.
+func (e *HatchEdge) curvePoints() [][2]float64 {
+	start, sweep := e.StartAngle, e.EndAngle-e.StartAngle
+	if e.Clockwise {
+		for sweep > 0 {
+			sweep -= 360
+		}
+	} else {
+		for sweep <= 0 {
+			sweep += 360
+		}
+	}
+	start *= math.Pi / 180
+	sweep *= math.Pi / 180
+
+	a, b := e.Radius, e.Radius
+	sin, cos := 0.0, 1.0
+	if e.Type == HatchEdgeEllipse {
+		a = math.Hypot(e.MajorAxisX, e.MajorAxisY)
+		b = a * e.MinorRatio
+		sin, cos = e.MajorAxisY/a, e.MajorAxisX/a
+	}
+
+	n := max(int(math.Ceil(math.Abs(sweep)/(2*math.Pi)*hatchArcSegments)), 1)
+	pts := make([][2]float64, 0, n)
+	for i := 0; i < n; i++ {
+		t := start + sweep*float64(i)/float64(n)
+		lx, ly := a*math.Cos(t), b*math.Sin(t)
+		pts = append(pts, [2]float64{e.CenterX + lx*cos - ly*sin, e.CenterY + lx*sin + ly*cos})
+	}
+	return pts
+}
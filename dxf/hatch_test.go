@@ -0,0 +1,132 @@
+package dxf
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestHatchGroupCodes_Solid(t *testing.T) {
+	hatch := NewHatch([]HatchBoundary{
+		PolygonBoundary(0, 0, 10, 0, 10, 10, 0, 10),
+		CircleBoundary(5, 5, 2),
+	}, WithHatchTrueColor(0x336699))
+	out := ToString(&Document{Entities: []Entity{hatch}})
+
+	for _, want := range []string{
+		"  0\nHATCH\n100\nAcDbEntity\n",
+		"100\nAcDbHatch\n",
+		"  2\nSOLID\n 70\n1\n 71\n0\n 91\n2\n",
+		" 92\n3\n 72\n0\n 73\n1\n 93\n4\n",
+		" 92\n1\n 93\n1\n 72\n2\n",
+		" 62\n0\n420\n3368601\n",
+		" 75\n0\n 76\n1\n 98\n0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(out, " 52\n") {
+		t.Error("solid hatch should not write pattern data")
+	}
+}
+
+func TestHatchGroupCodes_Pattern(t *testing.T) {
+	hatch := NewHatch([]HatchBoundary{PolygonBoundary(0, 0, 10, 0, 10, 10)},
+		WithHatchPattern("_USER", 45, 2),
+		WithHatchPatternLines(UserPatternLines(45, 2)...))
+	out := ToString(&Document{Entities: []Entity{hatch}})
+
+	for _, want := range []string{
+		"  2\n_USER\n 70\n0\n",
+		" 76\n0\n 52\n45.000000\n 41\n2.000000\n 77\n0\n 78\n1\n 53\n45.000000\n",
+		" 79\n0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestHatchGroupCodes_ClockwiseArc(t *testing.T) {
+	edge := ArcEdge(0, 0, 1, 90, 0)
+	edge.Clockwise = true
+	codes := (&HatchBoundary{Edges: []HatchEdge{edge}}).groupCodes()
+
+	var start, end float64
+	var ccw = -1
+	for _, gc := range codes {
+		switch gc.Code {
+		case 50:
+			start = gc.Value.(float64)
+		case 51:
+			end = gc.Value.(float64)
+		case 73:
+			ccw = gc.Value.(int)
+		}
+	}
+	if start != 270 || end != 360 || ccw != 0 {
+		t.Errorf("got start %v end %v flag %d, want 270 360 0", start, end, ccw)
+	}
+}
+
+func TestHatchArea(t *testing.T) {
+	tests := []struct {
+		name  string
+		hatch *Hatch
+		want  float64
+	}{
+		{
+			name:  "square",
+			hatch: NewHatch([]HatchBoundary{PolygonBoundary(0, 0, 10, 0, 10, 10, 0, 10)}),
+			want:  100,
+		},
+		{
+			name: "square with hole",
+			hatch: NewHatch([]HatchBoundary{
+				PolygonBoundary(0, 0, 10, 0, 10, 10, 0, 10),
+				PolygonBoundary(2, 2, 2, 4, 4, 4, 4, 2),
+			}),
+			want: 96,
+		},
+		{
+			name: "half disc from bulge",
+			hatch: NewHatch([]HatchBoundary{{Vertices: []HatchVertex{
+				{X: 10, Y: 0, Bulge: 1},
+				{X: -10, Y: 0},
+			}}}),
+			want: math.Pi * 50,
+		},
+		{
+			name: "ellipse",
+			hatch: NewHatch([]HatchBoundary{{Edges: []HatchEdge{
+				EllipseEdge(0, 0, 0, 10, 0.5, 0, 360),
+			}}}),
+			want: math.Pi * 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hatch.Area(); math.Abs(got-tt.want)/tt.want > 0.01 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHatchBoundingBox(t *testing.T) {
+	hatch := NewHatch([]HatchBoundary{CircleBoundary(0, 0, 10)})
+	minX, minY, maxX, maxY := hatch.BoundingBox()
+	if minX != -10 || minY != -10 || maxX != 10 || maxY != 10 {
+		t.Errorf("got (%v, %v, %v, %v), want (-10, -10, 10, 10)", minX, minY, maxX, maxY)
+	}
+
+	moved := hatch.Translate(5, 5)
+	if minX, _, maxX, _ := moved.BoundingBox(); minX != -5 || maxX != 15 {
+		t.Errorf("translated: got x range %v..%v, want -5..15", minX, maxX)
+	}
+	if hatch.Boundaries[0].Edges[0].CenterX != 0 {
+		t.Error("Translate modified the original hatch")
+	}
+}
@@ -0,0 +1,627 @@
+package dxf
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// hatchTolerance is the distance below which hatching lines and boundary
+// points are treated as coincident.
+const hatchTolerance = 1e-6
+
+// hatchMaxLinesPerPitch is the largest number of lines per repeat that is
+// recognized, matching the Jw_cad 1, 2 and 3 line hatch types (1線, 2線, 3線).
+const hatchMaxLinesPerPitch = 3
+
+// hatchLine is a hatching line in the coordinates of its family: offset is
+// the signed distance from the origin across the lines, and start and end
+// bound its span along them.
+type hatchLine struct {
+	index      int
+	line       *jww.Line
+	offset     float64
+	start, end float64
+}
+
+// hatchStyle identifies the hatching lines that can belong to one pattern.
+type hatchStyle struct {
+	layer     string
+	color     int
+	trueColor int // -1 when unset
+	colorName string
+	penStyle  byte
+	group     uint16
+	angle     int64 // Direction in 1/10000 degrees, in [0, 180°)
+}
+
+// hatchFamily holds the hatching lines sharing a style and direction.
+type hatchFamily struct {
+	hatchStyle
+	lines []hatchLine
+}
+
+// convertHatching recognizes the regions filled by Jw_cad hatching in list
+// and converts them to pattern HATCH entities. Hatching is stored as plain
+// lines flagged as hatch lines (see jww.Line.IsHatch); parallel lines of the
+// same style that neighbour each other form a region, whose pattern angle,
+// spacing and line count per repeat (up to hatchMaxLinesPerPitch) are
+// inferred from the line offsets. The boundary is the closed chain of
+// unflagged lines in list that the hatching lines end on or, failing that,
+// the convex hull of the hatching when it is convex. The unflagged lines are
+// looked up through a grid (see segmentGrid) so that large drawings are not
+// searched once per hatching line.
+//
+// It maps the list index of each converted line to its replacement: the HATCH
+// for the first line of each region and nil for the others. Regions that are
+// not recognized, and flagged arcs and points, are not mapped and are
+// converted as drawn.
+func convertHatching(list []jww.Entity, doc *jww.Document, cfg *convertConfig) map[int]*Hatch {
+	var keys []hatchStyle
+	families := make(map[hatchStyle]*hatchFamily)
+	var segments []*jww.Line
+	for i, e := range list {
+		ln, ok := e.(*jww.Line)
+		if !ok {
+			continue
+		}
+		if !ln.IsHatch() {
+			segments = append(segments, ln)
+			continue
+		}
+		dx, dy := ln.EndX-ln.StartX, ln.EndY-ln.StartY
+		if math.Hypot(dx, dy) <= hatchTolerance {
+			continue
+		}
+
+		angle := math.Mod(math.Atan2(dy, dx)*180/math.Pi+360, 180)
+		key := hatchStyle{
+			layer:    cfg.layerName(doc, ln.LayerGroup, ln.Layer),
+			penStyle: ln.PenStyle,
+			group:    ln.LayerGroup,
+			angle:    int64(math.Round(angle*1e4)) % (180 * 1e4),
+		}
+		var trueColor *int
+		key.color, trueColor, key.colorName = entityColor(doc, ln.PenColor)
+		key.trueColor = -1
+		if trueColor != nil {
+			key.trueColor = *trueColor
+		}
+
+		f, ok := families[key]
+		if !ok {
+			f = &hatchFamily{hatchStyle: key}
+			families[key] = f
+			keys = append(keys, key)
+		}
+		sin, cos := math.Sincos(float64(key.angle) / 1e4 * math.Pi / 180)
+		t1, t2 := ln.StartX*cos+ln.StartY*sin, ln.EndX*cos+ln.EndY*sin
+		f.lines = append(f.lines, hatchLine{
+			index:  i,
+			line:   ln,
+			offset: ln.StartY*cos - ln.StartX*sin,
+			start:  math.Min(t1, t2),
+			end:    math.Max(t1, t2),
+		})
+	}
+
+	replaced := make(map[int]*Hatch)
+	if len(keys) == 0 {
+		return replaced
+	}
+	grid := newSegmentGrid(segments)
+	for _, key := range keys {
+		f := families[key]
+		for _, region := range f.regions() {
+			hatch := f.regionHatch(region, grid, doc)
+			if hatch == nil {
+				continue
+			}
+			first := region[0].index
+			for _, hl := range region {
+				replaced[hl.index] = nil
+				first = min(first, hl.index)
+			}
+			replaced[first] = hatch
+		}
+	}
+	return replaced
+}
+
+// regions splits the lines of a family into regions, linking every line to
+// the nearest line above it whose span overlaps its own. The lines are swept
+// from the top down, recording in a spanTree the lowest line passed so far
+// at each position along the lines.
+func (f *hatchFamily) regions() [][]hatchLine {
+	lines := slices.Clone(f.lines)
+	slices.SortStableFunc(lines, func(a, b hatchLine) int { return cmp.Compare(a.offset, b.offset) })
+
+	parent := make([]int, len(lines))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// Spans are indexed by the elementary intervals between their ends; two
+	// spans overlap when they share one
+	coords := make([]float64, 0, 2*len(lines))
+	for _, hl := range lines {
+		coords = append(coords, hl.start, hl.end)
+	}
+	slices.Sort(coords)
+	coords = slices.Compact(coords)
+	span := func(hl hatchLine) (int, int) {
+		lo, _ := slices.BinarySearch(coords, hl.start)
+		hi, _ := slices.BinarySearch(coords, hl.end)
+		return lo, hi
+	}
+
+	// Lines are sorted by offset, so the lowest line is the one with the
+	// smallest index. Lines closer than hatchTolerance are not linked.
+	tree := newSpanTree(len(coords) - 1)
+	next := len(lines) - 1
+	for i := len(lines) - 1; i >= 0; i-- {
+		for ; next > i && lines[next].offset-lines[i].offset > hatchTolerance; next-- {
+			lo, hi := span(lines[next])
+			tree.lower(lo, hi, next)
+		}
+		lo, hi := span(lines[i])
+		if j := tree.min(lo, hi); j < len(lines) {
+			parent[find(j)] = find(i)
+		}
+	}
+
+	var roots []int
+	byRoot := make(map[int][]hatchLine)
+	for i, hl := range lines {
+		root := find(i)
+		if _, ok := byRoot[root]; !ok {
+			roots = append(roots, root)
+		}
+		byRoot[root] = append(byRoot[root], hl)
+	}
+	regions := make([][]hatchLine, len(roots))
+	for i, root := range roots {
+		regions[i] = byRoot[root]
+	}
+	return regions
+}
+
+// spanTree is a segment tree over n positions holding the smallest value
+// recorded at each, math.MaxInt where none is.
+type spanTree struct {
+	n int
+	// low is the smallest value recorded anywhere under a node, all the
+	// smallest value recorded for its whole range.
+	low, all []int
+}
+
+func newSpanTree(n int) *spanTree {
+	t := &spanTree{n: max(n, 1), low: make([]int, 4*max(n, 1)), all: make([]int, 4*max(n, 1))}
+	for i := range t.low {
+		t.low[i], t.all[i] = math.MaxInt, math.MaxInt
+	}
+	return t
+}
+
+// lower records v at positions [lo, hi).
+func (t *spanTree) lower(lo, hi, v int) {
+	var walk func(node, l, r int)
+	walk = func(node, l, r int) {
+		if hi <= l || r <= lo {
+			return
+		}
+		t.low[node] = min(t.low[node], v)
+		if lo <= l && r <= hi {
+			t.all[node] = min(t.all[node], v)
+			return
+		}
+		m := (l + r) / 2
+		walk(2*node+1, l, m)
+		walk(2*node+2, m, r)
+	}
+	walk(0, 0, t.n)
+}
+
+// min returns the smallest value recorded at positions [lo, hi).
+func (t *spanTree) min(lo, hi int) int {
+	var walk func(node, l, r int) int
+	walk = func(node, l, r int) int {
+		if hi <= l || r <= lo {
+			return math.MaxInt
+		}
+		if lo <= l && r <= hi {
+			return t.low[node]
+		}
+		m := (l + r) / 2
+		return min(t.all[node], walk(2*node+1, l, m), walk(2*node+2, m, r))
+	}
+	return walk(0, 0, t.n)
+}
+
+// regionHatch builds the pattern HATCH of a region, or returns nil when the
+// line spacing is not periodic or no boundary is found.
+func (f *hatchFamily) regionHatch(region []hatchLine, grid *segmentGrid, doc *jww.Document) *Hatch {
+	// Distinct offsets of the region, already sorted
+	var offsets []float64
+	for _, hl := range region {
+		if n := len(offsets); n == 0 || hl.offset-offsets[n-1] > hatchTolerance {
+			offsets = append(offsets, hl.offset)
+		}
+	}
+	gaps := make([]float64, len(offsets)-1)
+	for i := range gaps {
+		gaps[i] = offsets[i+1] - offsets[i]
+	}
+	perPitch := hatchPeriod(gaps)
+	if perPitch == 0 {
+		return nil
+	}
+
+	boundaries := hatchBoundaryLoops(region, grid)
+	if boundaries == nil {
+		boundaries = hatchHullBoundary(region)
+	}
+	if boundaries == nil {
+		return nil
+	}
+
+	angle := float64(f.angle) / 1e4
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	var pitch float64
+	for _, g := range gaps[:perPitch] {
+		pitch += g
+	}
+
+	var dashes []float64
+	if lt, ok := jwwLineType(doc, f.penStyle, f.group); ok {
+		dashes = lt.Pattern
+	}
+	lines := make([]HatchPatternLine, perPitch)
+	offset := offsets[0]
+	for i := range lines {
+		lines[i] = HatchPatternLine{
+			Angle:   angle,
+			BaseX:   -sin * offset,
+			BaseY:   cos * offset,
+			OffsetX: -sin * pitch,
+			OffsetY: cos * pitch,
+			Dashes:  dashes,
+		}
+		offset += gaps[i]
+	}
+
+	hatch := &Hatch{
+		Layer:        f.layer,
+		Color:        f.color,
+		Pattern:      "_USER",
+		PatternAngle: angle,
+		PatternScale: 1,
+		PatternLines: lines,
+		Boundaries:   boundaries,
+	}
+	if f.trueColor >= 0 {
+		trueColor := f.trueColor
+		hatch.TrueColor = &trueColor
+		hatch.ColorName = f.colorName
+	}
+	return hatch
+}
+
+// hatchPeriod returns the smallest number of lines per repeat with which the
+// gaps between hatching lines are periodic, or 0 when there is none up to
+// hatchMaxLinesPerPitch. Every candidate must repeat at least once.
+func hatchPeriod(gaps []float64) int {
+	for k := 1; k <= hatchMaxLinesPerPitch && k < len(gaps); k++ {
+		periodic := true
+		for i := 0; i+k < len(gaps); i++ {
+			if math.Abs(gaps[i]-gaps[i+k]) > hatchTolerance+1e-4*gaps[i] {
+				periodic = false
+				break
+			}
+		}
+		if periodic {
+			return k
+		}
+	}
+	return 0
+}
+
+// hatchBoundaryLoops finds the boundary of a region among the unflagged
+// lines: the lines that the hatching lines end on, closed up by the lines
+// joining their ends, must chain into loops. Loops inside an odd number of
+// other loops are holes and run clockwise. It returns nil when the lines do
+// not form loops or do not enclose the hatching.
+func hatchBoundaryLoops(region []hatchLine, grid *segmentGrid) []HatchBoundary {
+	segments := grid.segments
+	included := make(map[int]bool)
+	for _, hl := range region {
+		ln := hl.line
+		for _, p := range [][2]float64{{ln.StartX, ln.StartY}, {ln.EndX, ln.EndY}} {
+			found := false
+			for _, i := range grid.near(p[0], p[1]) {
+				if pointSegmentDistance(p[0], p[1], segments[i]) <= hatchTolerance {
+					included[i] = true
+					found = true
+				}
+			}
+			if !found {
+				return nil
+			}
+		}
+	}
+
+	// Add the lines joining the ends of the included lines, e.g. the sides
+	// of a rectangle parallel to the hatching
+	ends := make(map[hatchPoint]bool)
+	for i := range included {
+		s := segments[i]
+		ends[hatchKey(s.StartX, s.StartY)] = true
+		ends[hatchKey(s.EndX, s.EndY)] = true
+	}
+	for end := range ends {
+		for _, i := range grid.byEnd[end] {
+			s := segments[i]
+			if ends[hatchKey(s.StartX, s.StartY)] && ends[hatchKey(s.EndX, s.EndY)] {
+				included[i] = true
+			}
+		}
+	}
+	order := make([]int, 0, len(included))
+	for i := range included {
+		order = append(order, i)
+	}
+	slices.Sort(order)
+
+	// Every end must join exactly two lines
+	incident := make(map[hatchPoint][]int)
+	order = slices.DeleteFunc(order, func(i int) bool {
+		s := segments[i]
+		a, b := hatchKey(s.StartX, s.StartY), hatchKey(s.EndX, s.EndY)
+		if a == b {
+			return true
+		}
+		incident[a] = append(incident[a], i)
+		incident[b] = append(incident[b], i)
+		return false
+	})
+	for _, segs := range incident {
+		if len(segs) != 2 {
+			return nil
+		}
+	}
+
+	used := make(map[int]bool)
+	var loops [][]HatchVertex
+	for _, i := range order {
+		if used[i] {
+			continue
+		}
+		var loop []HatchVertex
+		s := segments[i]
+		start := hatchKey(s.StartX, s.StartY)
+		x, y := s.StartX, s.StartY
+		for {
+			used[i] = true
+			loop = append(loop, HatchVertex{X: x, Y: y})
+			s = segments[i]
+			if hatchKey(s.StartX, s.StartY) == hatchKey(x, y) {
+				x, y = s.EndX, s.EndY
+			} else {
+				x, y = s.StartX, s.StartY
+			}
+			if hatchKey(x, y) == start {
+				break
+			}
+			next := incident[hatchKey(x, y)]
+			i = next[0]
+			if used[i] {
+				i = next[1]
+			}
+		}
+		loops = append(loops, loop)
+	}
+
+	// The hatching must lie inside the boundary
+	for _, hl := range region {
+		ln := hl.line
+		if !insideLoops(loops, (ln.StartX+ln.EndX)/2, (ln.StartY+ln.EndY)/2) {
+			return nil
+		}
+	}
+
+	boundaries := make([]HatchBoundary, len(loops))
+	for i, loop := range loops {
+		depth := 0
+		for j, other := range loops {
+			if j != i && insideLoops([][]HatchVertex{other}, loop[0].X, loop[0].Y) {
+				depth++
+			}
+		}
+		if (polygonArea(loop) < 0) == (depth%2 == 0) {
+			slices.Reverse(loop)
+		}
+		boundaries[i] = HatchBoundary{Vertices: loop, Hole: depth%2 == 1}
+	}
+	return boundaries
+}
+
+// hatchPoint is a point rounded to hatchTolerance, identifying the lines
+// that share an end.
+type hatchPoint struct{ x, y int64 }
+
+func hatchKey(x, y float64) hatchPoint {
+	return hatchPoint{int64(math.Round(x / hatchTolerance)), int64(math.Round(y / hatchTolerance))}
+}
+
+// segmentGrid indexes the unflagged lines searched for hatching boundaries.
+// Each line is listed in the square cells it passes within hatchTolerance
+// of, about as many cells as there are lines, and by its ends.
+type segmentGrid struct {
+	segments   []*jww.Line
+	minX, minY float64
+	size       float64
+	cols, rows int
+	cells      [][]int
+	byEnd      map[hatchPoint][]int
+}
+
+func newSegmentGrid(segments []*jww.Line) *segmentGrid {
+	g := &segmentGrid{segments: segments, byEnd: make(map[hatchPoint][]int)}
+	if len(segments) == 0 {
+		return g
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i, s := range segments {
+		minX, maxX = min(minX, s.StartX, s.EndX), max(maxX, s.StartX, s.EndX)
+		minY, maxY = min(minY, s.StartY, s.EndY), max(maxY, s.StartY, s.EndY)
+		a, b := hatchKey(s.StartX, s.StartY), hatchKey(s.EndX, s.EndY)
+		g.byEnd[a] = append(g.byEnd[a], i)
+		if b != a {
+			g.byEnd[b] = append(g.byEnd[b], i)
+		}
+	}
+	n := min(int(math.Ceil(math.Sqrt(float64(len(segments))))), 1024)
+	g.minX, g.minY, g.size, g.cols, g.rows = minX, minY, 1, 1, 1
+	if extent := max(maxX-minX, maxY-minY); extent/float64(n) > hatchTolerance && !math.IsInf(extent, 0) {
+		g.size = extent / float64(n)
+		g.cols = int((maxX-minX)/g.size) + 1
+		g.rows = int((maxY-minY)/g.size) + 1
+	}
+	g.cells = make([][]int, g.cols*g.rows)
+
+	for i, s := range segments {
+		x0, x1 := min(s.StartX, s.EndX), max(s.StartX, s.EndX)
+		dx := s.EndX - s.StartX
+		for c := g.col(x0 - hatchTolerance); c <= g.col(x1+hatchTolerance); c++ {
+			// The part of the line whose points can lie within
+			// hatchTolerance of the column
+			left := g.minX + float64(c)*g.size
+			xa := math.Max(x0, left-hatchTolerance)
+			xb := math.Min(x1, left+g.size+hatchTolerance)
+			ya, yb := math.Min(s.StartY, s.EndY), math.Max(s.StartY, s.EndY)
+			if math.Abs(dx) > hatchTolerance {
+				ya = s.StartY + (xa-s.StartX)*(s.EndY-s.StartY)/dx
+				yb = s.StartY + (xb-s.StartX)*(s.EndY-s.StartY)/dx
+				ya, yb = math.Min(ya, yb), math.Max(ya, yb)
+			}
+			for r := g.row(ya - hatchTolerance); r <= g.row(yb+hatchTolerance); r++ {
+				g.cells[r*g.cols+c] = append(g.cells[r*g.cols+c], i)
+			}
+		}
+	}
+	return g
+}
+
+func (g *segmentGrid) col(x float64) int {
+	return max(0, min(g.cols-1, int(math.Floor((x-g.minX)/g.size))))
+}
+
+func (g *segmentGrid) row(y float64) int {
+	return max(0, min(g.rows-1, int(math.Floor((y-g.minY)/g.size))))
+}
+
+// near returns the indexes of the lines that can lie within hatchTolerance
+// of (x, y).
+func (g *segmentGrid) near(x, y float64) []int {
+	if len(g.cells) == 0 {
+		return nil
+	}
+	return g.cells[g.row(y)*g.cols+g.col(x)]
+}
+
+// hatchHullBoundary returns the convex hull of the hatching line ends as the
+// boundary of a region, or nil when a line end lies inside the hull, i.e.
+// the hatched region is not convex.
+func hatchHullBoundary(region []hatchLine) []HatchBoundary {
+	points := make([]HatchVertex, 0, 2*len(region))
+	for _, hl := range region {
+		ln := hl.line
+		points = append(points, HatchVertex{X: ln.StartX, Y: ln.StartY}, HatchVertex{X: ln.EndX, Y: ln.EndY})
+	}
+	hull := convexHull(points)
+	if len(hull) < 3 || polygonArea(hull) <= hatchTolerance {
+		return nil
+	}
+
+	for _, p := range points {
+		onHull := false
+		for i, a := range hull {
+			b := hull[(i+1)%len(hull)]
+			seg := &jww.Line{StartX: a.X, StartY: a.Y, EndX: b.X, EndY: b.Y}
+			if pointSegmentDistance(p.X, p.Y, seg) <= hatchTolerance {
+				onHull = true
+				break
+			}
+		}
+		if !onHull {
+			return nil
+		}
+	}
+	return []HatchBoundary{{Vertices: hull}}
+}
+
+// convexHull returns the convex hull of points, counterclockwise and without
+// collinear vertices (Andrew's monotone chain).
+func convexHull(points []HatchVertex) []HatchVertex {
+	pts := slices.Clone(points)
+	slices.SortFunc(pts, func(a, b HatchVertex) int {
+		if c := cmp.Compare(a.X, b.X); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Y, b.Y)
+	})
+	cross := func(o, a, b HatchVertex) float64 {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+
+	var hull []HatchVertex
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range pts {
+			for len(hull) >= start+2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1]
+		slices.Reverse(pts)
+	}
+	return hull
+}
+
+// insideLoops reports whether (x, y) lies inside the region bounded by loops,
+// using the even-odd rule.
+func insideLoops(loops [][]HatchVertex, x, y float64) bool {
+	inside := false
+	for _, loop := range loops {
+		for i, a := range loop {
+			b := loop[(i+1)%len(loop)]
+			if (a.Y > y) != (b.Y > y) && x < a.X+(y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// pointSegmentDistance returns the distance from (x, y) to the line segment s.
+func pointSegmentDistance(x, y float64, s *jww.Line) float64 {
+	dx, dy := s.EndX-s.StartX, s.EndY-s.StartY
+	lengthSq := dx*dx + dy*dy
+	t := 0.0
+	if lengthSq > 0 {
+		t = math.Max(0, math.Min(1, ((x-s.StartX)*dx+(y-s.StartY)*dy)/lengthSq))
+	}
+	return math.Hypot(x-(s.StartX+t*dx), y-(s.StartY+t*dy))
+}
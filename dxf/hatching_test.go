@@ -0,0 +1,205 @@
+package dxf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// hatchingLine returns a line flagged as drawn by the hatching command.
+func hatchingLine(x1, y1, x2, y2 float64) *jww.Line {
+	return &jww.Line{
+		EntityBase: jww.EntityBase{PenStyle: 1, PenColor: 3, Flag: 0x0020},
+		StartX:     x1, StartY: y1, EndX: x2, EndY: y2,
+	}
+}
+
+// rectangleLines returns the four sides of a rectangle as separate lines.
+func rectangleLines(x0, y0, x1, y1 float64) []jww.Entity {
+	side := func(ax, ay, bx, by float64) *jww.Line {
+		return &jww.Line{EntityBase: jww.EntityBase{PenStyle: 1, PenColor: 1}, StartX: ax, StartY: ay, EndX: bx, EndY: by}
+	}
+	return []jww.Entity{
+		side(x0, y0, x1, y0),
+		side(x1, y0, x1, y1),
+		side(x1, y1, x0, y1),
+		side(x0, y1, x0, y0),
+	}
+}
+
+func TestHatchPeriod(t *testing.T) {
+	tests := []struct {
+		gaps []float64
+		want int
+	}{
+		{[]float64{10, 10, 10}, 1},
+		{[]float64{2, 8, 2, 8, 2}, 2},
+		{[]float64{1, 1, 8, 1, 1, 8}, 3},
+		{[]float64{10, 15, 2}, 0},
+		{[]float64{10}, 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := hatchPeriod(tt.gaps); got != tt.want {
+			t.Errorf("hatchPeriod(%v) = %d, want %d", tt.gaps, got, tt.want)
+		}
+	}
+}
+
+func TestConvertHatching_Boundary(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = rectangleLines(0, 0, 100, 50)
+	for y := 5.0; y < 50; y += 10 {
+		doc.Entities = append(doc.Entities, hatchingLine(0, y, 100, y))
+	}
+
+	result := ConvertDocument(doc)
+
+	if len(result.Entities) != 5 {
+		t.Fatalf("expected 4 lines and a hatch, got %d entities", len(result.Entities))
+	}
+	hatch, ok := result.Entities[4].(*Hatch)
+	if !ok {
+		t.Fatalf("expected *Hatch, got %T", result.Entities[4])
+	}
+	if hatch.Pattern != "_USER" || hatch.PatternAngle != 0 || hatch.Color != 3 {
+		t.Errorf("pattern %q angle %v color %d", hatch.Pattern, hatch.PatternAngle, hatch.Color)
+	}
+	if len(hatch.PatternLines) != 1 {
+		t.Fatalf("expected 1 pattern line, got %d", len(hatch.PatternLines))
+	}
+	if pl := hatch.PatternLines[0]; pl.BaseY != 5 || pl.OffsetY != 10 || math.Abs(pl.OffsetX) > 1e-12 {
+		t.Errorf("pattern line: got %+v", pl)
+	}
+	if len(hatch.Boundaries) != 1 || len(hatch.Boundaries[0].Vertices) != 4 {
+		t.Fatalf("expected the rectangle as boundary, got %+v", hatch.Boundaries)
+	}
+	if area := hatch.Area(); area != 5000 {
+		t.Errorf("area: got %v, want 5000", area)
+	}
+	if polygonArea(hatch.Boundaries[0].Vertices) < 0 {
+		t.Error("expected a counterclockwise outer boundary")
+	}
+
+	// Exploded hatching keeps the lines
+	if result := ConvertDocument(doc, WithExplodedHatching()); len(result.Entities) != 9 {
+		t.Errorf("expected 9 lines when exploded, got %d entities", len(result.Entities))
+	}
+}
+
+func TestConvertHatching_Hole(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = append(rectangleLines(0, 0, 40, 40), rectangleLines(10, 10, 30, 30)...)
+	for x := 2.5; x < 40; x += 5 {
+		if x > 10 && x < 30 {
+			doc.Entities = append(doc.Entities, hatchingLine(x, 0, x, 10), hatchingLine(x, 30, x, 40))
+			continue
+		}
+		doc.Entities = append(doc.Entities, hatchingLine(x, 0, x, 40))
+	}
+
+	result := ConvertDocument(doc)
+
+	if len(result.Entities) != 9 {
+		t.Fatalf("expected 8 lines and a hatch, got %d entities", len(result.Entities))
+	}
+	hatch := result.Entities[8].(*Hatch)
+	if hatch.PatternAngle != 90 || len(hatch.Boundaries) != 2 {
+		t.Fatalf("expected a 90° hatch with a hole, got angle %v and %d boundaries", hatch.PatternAngle, len(hatch.Boundaries))
+	}
+	if area := hatch.Area(); math.Abs(area-1200) > 1e-9 {
+		t.Errorf("area: got %v, want 1200", area)
+	}
+	if hatch.Boundaries[0].Hole == hatch.Boundaries[1].Hole {
+		t.Error("expected one outline and one hole boundary")
+	}
+}
+
+func TestConvertHatching_DoubleLines(t *testing.T) {
+	doc := createTestDocument()
+	// Two lines 2 apart every 10, at 45° through a convex region; no
+	// boundary lines, so the hull of the hatching is used
+	for _, o := range []float64{0, 2, 10, 12, 20, 22} {
+		d := o / math.Sqrt2
+		doc.Entities = append(doc.Entities, hatchingLine(-d, d, 50-d, 50+d))
+	}
+
+	result := ConvertDocument(doc)
+
+	if len(result.Entities) != 1 {
+		t.Fatalf("expected a hatch, got %d entities", len(result.Entities))
+	}
+	hatch := result.Entities[0].(*Hatch)
+	if hatch.PatternAngle != 45 || len(hatch.PatternLines) != 2 {
+		t.Fatalf("expected two 45° pattern lines, got angle %v and %d lines", hatch.PatternAngle, len(hatch.PatternLines))
+	}
+	for i, want := range []float64{0, 2} {
+		pl := hatch.PatternLines[i]
+		if got := math.Hypot(pl.BaseX, pl.BaseY); math.Abs(got-want) > 1e-9 {
+			t.Errorf("line %d: base offset %v, want %v", i, got, want)
+		}
+		if got := math.Hypot(pl.OffsetX, pl.OffsetY); math.Abs(got-10) > 1e-9 {
+			t.Errorf("line %d: spacing %v, want 10", i, got)
+		}
+	}
+	if area, want := hatch.Area(), 50*math.Sqrt2*22; math.Abs(area-want) > 1e-6 {
+		t.Errorf("area: got %v, want %v", area, want)
+	}
+}
+
+func TestConvertHatching_Unrecognized(t *testing.T) {
+	doc := createTestDocument()
+	// Irregular spacing
+	for _, y := range []float64{0, 10, 25, 27} {
+		doc.Entities = append(doc.Entities, hatchingLine(0, y, 10, y))
+	}
+	// An L-shaped region without boundary lines is not convex
+	for _, y := range []float64{30, 35, 40} {
+		doc.Entities = append(doc.Entities, hatchingLine(0, y, 10, y))
+	}
+	for _, y := range []float64{45, 50, 55} {
+		doc.Entities = append(doc.Entities, hatchingLine(0, y, 5, y))
+	}
+
+	result := ConvertDocument(doc)
+
+	for _, e := range result.Entities {
+		if _, ok := e.(*Line); !ok {
+			t.Errorf("expected only lines, got %T", e)
+		}
+	}
+	if len(result.Entities) != 10 {
+		t.Errorf("expected 10 lines, got %d", len(result.Entities))
+	}
+}
+
+func TestConvertHatching_ManyRegions(t *testing.T) {
+	// Rectangles side by side share the offsets of their hatching lines, and
+	// the hatching of each column of rectangles forms one region
+	doc := createTestDocument()
+	for i := range 10 {
+		for j := range 10 {
+			x, y := float64(30*i), float64(30*j)
+			doc.Entities = append(doc.Entities, rectangleLines(x, y, x+20, y+20)...)
+			for dy := 5.0; dy < 20; dy += 5 {
+				doc.Entities = append(doc.Entities, hatchingLine(x, y+dy, x+20, y+dy))
+			}
+		}
+	}
+
+	result := ConvertDocument(doc)
+
+	var hatches int
+	for _, e := range result.Entities {
+		if hatch, ok := e.(*Hatch); ok {
+			hatches++
+			if len(hatch.Boundaries) != 10 || hatch.Area() != 4000 {
+				t.Errorf("expected 10 boundaries enclosing 4000, got %d enclosing %v", len(hatch.Boundaries), hatch.Area())
+			}
+		}
+	}
+	if hatches != 10 || len(result.Entities) != 410 {
+		t.Errorf("expected 400 lines and 10 hatches, got %d hatches of %d entities", hatches, len(result.Entities))
+	}
+}
@@ -1,6 +1,9 @@
 package dxf
 
-import "math"
+import (
+	"math"
+	"strings"
+)
 
 // Length calculates the length of a Line entity.
 //
@@ -229,6 +232,29 @@ func (e *Ellipse) BoundingBox() (minX, minY, maxX, maxY float64) {
 	return
 }
 
+// BoundingBox returns the bounding box of an Image entity, covering all four
+// corners of the full (unclipped) image.
+//
+// Example:
+//
+//	img := &dxf.Image{X: 0, Y: 0, UX: 0.5, VY: 0.5, Width: 200, Height: 100}
+//	minX, minY, maxX, maxY := img.BoundingBox() // Returns (0, 0, 100, 50)
+func (i *Image) BoundingBox() (minX, minY, maxX, maxY float64) {
+	w, h := float64(i.Width), float64(i.Height)
+	xs := []float64{i.X, i.X + i.UX*w, i.X + i.VX*h, i.X + i.UX*w + i.VX*h}
+	ys := []float64{i.Y, i.Y + i.UY*w, i.Y + i.VY*h, i.Y + i.UY*w + i.VY*h}
+
+	minX, maxX = xs[0], xs[0]
+	minY, maxY = ys[0], ys[0]
+	for k := 1; k < 4; k++ {
+		minX = math.Min(minX, xs[k])
+		maxX = math.Max(maxX, xs[k])
+		minY = math.Min(minY, ys[k])
+		maxY = math.Max(maxY, ys[k])
+	}
+	return
+}
+
 // BoundingBox returns the bounding box of a Point entity.
 // Returns (x, y, x, y) since it's a single point.
 //
@@ -240,20 +266,56 @@ func (p *Point) BoundingBox() (minX, minY, maxX, maxY float64) {
 	return p.X, p.Y, p.X, p.Y
 }
 
-// BoundingBox returns the approximate bounding box of a Text entity.
-// Note: This is a simplified calculation that doesn't account for actual font metrics.
+// TextEms returns the layout width of s in character heights, following
+// the Jw_cad convention that single-byte (ASCII and half-width katakana)
+// characters are half as wide as full-width characters.
+//
+// Example:
+//
+//	ems := dxf.TextEms("A図") // Returns 1.5
+func TextEms(s string) float64 {
+	ems := 0.0
+	for _, r := range s {
+		if r <= 0x7F || (r >= 0xFF61 && r <= 0xFF9F) {
+			ems += 0.5
+		} else {
+			ems += 1
+		}
+	}
+	return ems
+}
+
+// Width returns the layout width of a Text entity: its height times the
+// width factor for every full-width character, and half of that for
+// half-width characters (see TextEms). Converted JWW texts get a width factor
+// that makes this match the extent of the original text.
+//
+// Example:
+//
+//	text := dxf.NewText(0, 0, "図面", dxf.WithTextHeight(5))
+//	w := text.Width() // Returns 10
+func (t *Text) Width() float64 {
+	widthFactor := t.WidthFactor
+	if widthFactor == 0 {
+		widthFactor = 1
+	}
+	return TextEms(t.Content) * t.Height * widthFactor
+}
+
+// BoundingBox returns the bounding box of a Text entity, spanning its layout
+// width (see Width) and height, rotated by its rotation angle. Font specific
+// glyph shapes and the oblique angle are not taken into account.
 // Returns (minX, minY, maxX, maxY).
 //
 // Example:
 //
 //	text := dxf.NewText(10, 10, "Hello", dxf.WithTextHeight(5))
-//	minX, minY, maxX, maxY := text.BoundingBox()
+//	minX, minY, maxX, maxY := text.BoundingBox() // Returns (10, 10, 22.5, 15)
 func (t *Text) BoundingBox() (minX, minY, maxX, maxY float64) {
-	// Simplified: estimate width as height * length * 0.6 (typical aspect ratio)
-	estimatedWidth := t.Height * float64(len(t.Content)) * 0.6
+	width := t.Width()
 
 	if t.Rotation == 0 {
-		return t.X, t.Y, t.X + estimatedWidth, t.Y + t.Height
+		return t.X, t.Y, t.X + width, t.Y + t.Height
 	}
 
 	// For rotated text, calculate the corners and find min/max
@@ -264,8 +326,8 @@ func (t *Text) BoundingBox() (minX, minY, maxX, maxY float64) {
 	// Four corners of the text box
 	corners := [][2]float64{
 		{0, 0},
-		{estimatedWidth, 0},
-		{estimatedWidth, t.Height},
+		{width, 0},
+		{width, t.Height},
 		{0, t.Height},
 	}
 
@@ -284,6 +346,109 @@ func (t *Text) BoundingBox() (minX, minY, maxX, maxY float64) {
 	return
 }
 
+// BoundingBox returns the bounding box of a Dimension entity: the extent of
+// its Geometry together with its definition and text points. Dimensions
+// referencing an existing block by BlockName only cover their points.
+// Returns (minX, minY, maxX, maxY).
+func (d *Dimension) BoundingBox() (minX, minY, maxX, maxY float64) {
+	points := [][2]float64{{d.DefX, d.DefY}, {d.TextX, d.TextY}}
+	switch d.DimType {
+	case DimLinear, DimAligned:
+		points = append(points, [2]float64{d.X1, d.Y1}, [2]float64{d.X2, d.Y2})
+	case DimAngular:
+		points = append(points, [2]float64{d.X2, d.Y2}, [2]float64{d.X4, d.Y4})
+	case DimDiameter, DimRadius:
+		points = append(points, [2]float64{d.X3, d.Y3})
+	}
+
+	minX, minY, maxX, maxY = (&Document{Entities: d.Geometry}).BoundingBox()
+	for _, p := range points {
+		minX = math.Min(minX, p[0])
+		maxX = math.Max(maxX, p[0])
+		minY = math.Min(minY, p[1])
+		maxY = math.Max(maxY, p[1])
+	}
+	return minX, minY, maxX, maxY
+}
+
+// mtextLineSpacing is the distance between MTEXT baselines in character
+// heights at a line spacing factor of 1.
+const mtextLineSpacing = 5.0 / 3.0
+
+// Size returns the layout width and height of an MText entity. The width is
+// the column width when set, otherwise the widest line (see TextEms); the
+// height spans all paragraphs at the line spacing factor. Lines are not
+// wrapped and inline height changes are ignored.
+//
+// Example:
+//
+//	mtext := dxf.NewMText(0, 0, "AB\\PCD", dxf.WithMTextHeight(3))
+//	w, h := mtext.Size() // Returns (3, 8)
+func (m *MText) Size() (width, height float64) {
+	lines := strings.Split(MTextPlain(m.Content), "\n")
+
+	width = m.Width
+	if width <= 0 {
+		for _, line := range lines {
+			width = math.Max(width, TextEms(line)*m.Height)
+		}
+	}
+
+	spacing := m.LineSpacing
+	if spacing == 0 {
+		spacing = 1
+	}
+	height = m.Height + float64(len(lines)-1)*m.Height*mtextLineSpacing*spacing
+	return width, height
+}
+
+// BoundingBox returns the bounding box of an MText entity, placing its layout
+// box (see Size) at the insertion point according to the attachment point
+// and rotating it by the rotation angle.
+// Returns (minX, minY, maxX, maxY).
+//
+// Example:
+//
+//	mtext := dxf.NewMText(10, 10, "Hello", dxf.WithMTextHeight(4))
+//	minX, minY, maxX, maxY := mtext.BoundingBox() // Returns (10, 6, 20, 10)
+func (m *MText) BoundingBox() (minX, minY, maxX, maxY float64) {
+	width, height := m.Size()
+
+	attachment := m.AttachmentPoint
+	if attachment < MTextTopLeft || attachment > MTextBottomRight {
+		attachment = MTextTopLeft
+	}
+	col := float64((attachment - 1) % 3) // 0 = left, 1 = center, 2 = right
+	row := float64((attachment - 1) / 3) // 0 = top, 1 = middle, 2 = bottom
+	left := -col * width / 2
+	top := row * height / 2
+
+	angle := m.Rotation * math.Pi / 180.0
+	cos := math.Cos(angle)
+	sin := math.Sin(angle)
+
+	corners := [][2]float64{
+		{left, top},
+		{left + width, top},
+		{left + width, top - height},
+		{left, top - height},
+	}
+
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+
+	for _, corner := range corners {
+		x := m.X + corner[0]*cos - corner[1]*sin
+		y := m.Y + corner[0]*sin + corner[1]*cos
+		minX = math.Min(minX, x)
+		maxX = math.Max(maxX, x)
+		minY = math.Min(minY, y)
+		maxY = math.Max(maxY, y)
+	}
+
+	return minX, minY, maxX, maxY
+}
+
 // BoundingBox returns the bounding box of a Solid entity.
 // Returns (minX, minY, maxX, maxY).
 //
@@ -326,6 +491,50 @@ func (s *Solid) IsTriangle() bool {
 	return s.X3 == s.X4 && s.Y3 == s.Y4
 }
 
+// BoundingBox returns the bounding box of a Hatch entity, covering all its
+// boundaries with curved edges flattened.
+// Returns (minX, minY, maxX, maxY).
+//
+// Example:
+//
+//	hatch := dxf.NewHatch([]dxf.HatchBoundary{dxf.CircleBoundary(0, 0, 10)})
+//	minX, minY, maxX, maxY := hatch.BoundingBox() // Returns (-10, -10, 10, 10)
+func (h *Hatch) BoundingBox() (minX, minY, maxX, maxY float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+
+	for i := range h.Boundaries {
+		for _, p := range h.Boundaries[i].outline() {
+			minX = math.Min(minX, p[0])
+			maxX = math.Max(maxX, p[0])
+			minY = math.Min(minY, p[1])
+			maxY = math.Max(maxY, p[1])
+		}
+	}
+	return
+}
+
+// Area calculates the filled area of a Hatch entity as the sum of the signed
+// areas of its boundaries, with curved edges flattened. Holes must run in the
+// opposite direction of the boundary enclosing them, as the JWW converter
+// creates them.
+//
+// Example:
+//
+//	hatch := dxf.NewHatch([]dxf.HatchBoundary{dxf.PolygonBoundary(0, 0, 10, 0, 10, 10, 0, 10)})
+//	area := hatch.Area() // Returns 100
+func (h *Hatch) Area() float64 {
+	var sum float64
+	for i := range h.Boundaries {
+		pts := h.Boundaries[i].outline()
+		for j := range pts {
+			k := (j + 1) % len(pts)
+			sum += pts[j][0]*pts[k][1] - pts[k][0]*pts[j][1]
+		}
+	}
+	return math.Abs(sum) / 2
+}
+
 // BoundingBox returns the bounding box of the entire Document.
 // Returns (minX, minY, maxX, maxY) encompassing all entities.
 //
@@ -357,8 +566,16 @@ func (d *Document) BoundingBox() (minX, minY, maxX, maxY float64) {
 			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
 		case *Text:
 			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
+		case *MText:
+			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
+		case *Dimension:
+			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
+		case *Hatch:
+			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
 		case *Solid:
 			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
+		case *Image:
+			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
 		default:
 			continue
 		}
@@ -382,29 +599,7 @@ func (d *Document) FilterByLayer(layerName string) []Entity {
 	var filtered []Entity
 
 	for _, entity := range d.Entities {
-		var layer string
-		switch e := entity.(type) {
-		case *Line:
-			layer = e.Layer
-		case *Circle:
-			layer = e.Layer
-		case *Arc:
-			layer = e.Layer
-		case *Ellipse:
-			layer = e.Layer
-		case *Point:
-			layer = e.Layer
-		case *Text:
-			layer = e.Layer
-		case *Solid:
-			layer = e.Layer
-		case *Insert:
-			layer = e.Layer
-		default:
-			continue
-		}
-
-		if layer == layerName {
+		if layer := entityLayer(entity); layer != nil && *layer == layerName {
 			filtered = append(filtered, entity)
 		}
 	}
@@ -412,6 +607,38 @@ func (d *Document) FilterByLayer(layerName string) []Entity {
 	return filtered
 }
 
+// entityLayer returns a pointer to the layer name of an entity, or nil for
+// entity types without one.
+func entityLayer(entity Entity) *string {
+	switch e := entity.(type) {
+	case *Line:
+		return &e.Layer
+	case *Circle:
+		return &e.Layer
+	case *Arc:
+		return &e.Layer
+	case *Ellipse:
+		return &e.Layer
+	case *Point:
+		return &e.Layer
+	case *Text:
+		return &e.Layer
+	case *MText:
+		return &e.Layer
+	case *Dimension:
+		return &e.Layer
+	case *Hatch:
+		return &e.Layer
+	case *Solid:
+		return &e.Layer
+	case *Insert:
+		return &e.Layer
+	case *Image:
+		return &e.Layer
+	}
+	return nil
+}
+
 // CountByType returns a map of entity type names to their counts.
 //
 // Example:
@@ -205,3 +205,26 @@ func TestDocumentCountByType(t *testing.T) {
 		t.Errorf("Expected 1 point, got %d", counts["POINT"])
 	}
 }
+
+func TestTextEms(t *testing.T) {
+	cases := map[string]float64{"": 0, "AB": 1, "図面": 2, "A図": 1.5, "ｱｲ": 1}
+	for s, want := range cases {
+		if got := TextEms(s); got != want {
+			t.Errorf("TextEms(%q): got %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestTextBoundingBox(t *testing.T) {
+	text := NewText(10, 10, "図面", WithTextHeight(5), WithTextWidthFactor(0.5))
+	minX, minY, maxX, maxY := text.BoundingBox()
+	if minX != 10 || minY != 10 || maxX != 15 || maxY != 15 {
+		t.Errorf("got (%v, %v, %v, %v), want (10, 10, 15, 15)", minX, minY, maxX, maxY)
+	}
+
+	text.Rotation = 90
+	minX, minY, maxX, maxY = text.BoundingBox()
+	if math.Abs(minX-5) > 1e-9 || math.Abs(minY-10) > 1e-9 || math.Abs(maxX-10) > 1e-9 || math.Abs(maxY-15) > 1e-9 {
+		t.Errorf("rotated: got (%v, %v, %v, %v), want (5, 10, 10, 15)", minX, minY, maxX, maxY)
+	}
+}
@@ -0,0 +1,160 @@
+package dxf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"image"
+	_ "image/gif"  // Register GIF for image.DecodeConfig
+	_ "image/jpeg" // Register JPEG for image.DecodeConfig
+	_ "image/png"  // Register PNG for image.DecodeConfig
+	"io"
+	"math"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// convertImage converts a JWW image reference to a DXF IMAGE entity.
+//
+// The "^@BM" reference gives the drawn size of the visible (trimmed) part of
+// the image and places its lower-left corner at the reference point. The full
+// image is positioned so that the trimmed region lands there, and the trimming
+// is expressed as a rectangular clipping boundary.
+func convertImage(ref *jww.ImageRef, doc *jww.Document, cfg *convertConfig) Entity {
+	if ref.Width <= 0 || ref.Height <= 0 {
+		return nil
+	}
+
+	base := ref.Base()
+	color, trueColor, colorName := entityColor(doc, base.PenColor)
+	trimW, trimH := imageTrimSize(ref)
+	fullW, fullH := ref.Width/trimW, ref.Height/trimH
+	px, py := imagePixelSize(ref, doc)
+
+	sin, cos := math.Sincos(ref.Angle * math.Pi / 180)
+	ox := -ref.TrimX * fullW
+	oy := -ref.TrimY * fullH
+
+	img := &Image{
+		Layer:     cfg.layerName(doc, base.LayerGroup, base.Layer),
+		Color:     color,
+		TrueColor: trueColor,
+		ColorName: colorName,
+		ImageDef:  ref.FileName(),
+		X:         ref.X + ox*cos - oy*sin,
+		Y:         ref.Y + ox*sin + oy*cos,
+		UX:        fullW / float64(px) * cos,
+		UY:        fullW / float64(px) * sin,
+		VX:        -fullH / float64(py) * sin,
+		VY:        fullH / float64(py) * cos,
+		Width:     px,
+		Height:    py,
+	}
+
+	if ref.TrimX != 0 || ref.TrimY != 0 || trimW != 1 || trimH != 1 {
+		// Clipping uses pixel coordinates with the origin at the top-left
+		// pixel center, while JWW trimming is measured from the bottom-left.
+		img.Clipped = true
+		img.ClipX1 = ref.TrimX*float64(px) - 0.5
+		img.ClipY1 = (1-ref.TrimY-trimH)*float64(py) - 0.5
+		img.ClipX2 = (ref.TrimX+trimW)*float64(px) - 0.5
+		img.ClipY2 = (1-ref.TrimY)*float64(py) - 0.5
+	}
+
+	return img
+}
+
+// convertImageDefs creates an ImageDef for every distinct image file
+// referenced from the drawing or its blocks. Bundled images can be written
+// next to the DXF by WriteImageFiles, which decompresses them through the
+// ImageDef's Load; external images keep their original path.
+func convertImageDefs(doc *jww.Document) []ImageDef {
+	var defs []ImageDef
+	seen := make(map[string]bool)
+
+	add := func(entities []jww.Entity) {
+		for _, e := range entities {
+			ref, ok := e.(*jww.ImageRef)
+			if !ok || seen[ref.FileName()] {
+				continue
+			}
+			seen[ref.FileName()] = true
+
+			px, py := imagePixelSize(ref, doc)
+			def := ImageDef{
+				Name:     ref.FileName(),
+				FileName: ref.Path,
+				Width:    px,
+				Height:   py,
+			}
+			if img := doc.FindImage(ref); img != nil {
+				def.FileName = ref.FileName()
+				def.Load = func() ([]byte, error) { return img.Decompress(0) }
+			}
+			defs = append(defs, def)
+		}
+	}
+
+	add(doc.Entities)
+	for _, bd := range doc.BlockDefs {
+		add(bd.Entities)
+	}
+
+	return defs
+}
+
+// imageTrimSize returns the trimmed width and height ratios of ref, or 1, 1
+// when they are unusable.
+func imageTrimSize(ref *jww.ImageRef) (float64, float64) {
+	if ref.TrimWidth <= 0 || ref.TrimHeight <= 0 {
+		return 1, 1
+	}
+	return ref.TrimWidth, ref.TrimHeight
+}
+
+// imagePixelSize returns the pixel size of the image referenced by ref.
+// The size is read from the header of the bundled image file. When it cannot
+// be determined (external files, unknown formats) one pixel per drawing unit
+// of the untrimmed drawn size is assumed, which keeps the aspect ratio.
+func imagePixelSize(ref *jww.ImageRef, doc *jww.Document) (int, int) {
+	if img := doc.FindImage(ref); img != nil {
+		if w, h, ok := decodeImageSize(img); ok {
+			return w, h
+		}
+	}
+	trimW, trimH := imageTrimSize(ref)
+	return max(1, int(math.Round(ref.Width/trimW))), max(1, int(math.Round(ref.Height/trimH)))
+}
+
+// imageHeaderLimit is the number of bytes of an image file read at most to
+// find its pixel size, enough for the metadata JPEG files carry before their
+// frame header.
+const imageHeaderLimit = 1 << 20
+
+// decodeImageSize reads the pixel size from the header of a bundled image,
+// decompressing no more than imageHeaderLimit bytes. BMP is decoded directly;
+// other formats use the registered image decoders.
+func decodeImageSize(img *jww.Image) (int, int, bool) {
+	r, err := img.Open()
+	if err != nil {
+		return 0, 0, false
+	}
+	defer r.Close()
+
+	br := bufio.NewReader(io.LimitReader(r, imageHeaderLimit))
+	if hdr, err := br.Peek(26); err == nil && hdr[0] == 'B' && hdr[1] == 'M' {
+		// BITMAPFILEHEADER (14 bytes), then the BITMAPINFOHEADER width and
+		// height; a negative height marks a top-down bitmap.
+		w := int32(binary.LittleEndian.Uint32(hdr[18:22]))
+		h := int32(binary.LittleEndian.Uint32(hdr[22:26]))
+		if h < 0 {
+			h = -h
+		}
+		return int(w), int(h), w > 0 && h > 0
+	}
+
+	cfg, _, err := image.DecodeConfig(br)
+	if err != nil || cfg.Width <= 0 || cfg.Height <= 0 {
+		return 0, 0, false
+	}
+	return cfg.Width, cfg.Height, true
+}
@@ -0,0 +1,164 @@
+package dxf
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// testPNG returns an encoded PNG of the given pixel size.
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestConvertImage(t *testing.T) {
+	doc := createTestDocument()
+	doc.Images = []jww.Image{{Name: "photo.png", Data: testPNG(t, 200, 100)}}
+	doc.Entities = []jww.Entity{&jww.ImageRef{
+		X: 1000, Y: 500, Width: 400, Height: 200, Angle: 90,
+		Path: "%temp%photo.png", TrimWidth: 1, TrimHeight: 1,
+	}}
+
+	result := ConvertDocument(doc)
+
+	if len(result.Entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(result.Entities))
+	}
+	img, ok := result.Entities[0].(*Image)
+	if !ok {
+		t.Fatalf("expected *Image, got %T", result.Entities[0])
+	}
+	if img.Width != 200 || img.Height != 100 {
+		t.Errorf("pixel size: got %dx%d, want 200x100", img.Width, img.Height)
+	}
+	if img.X != 1000 || img.Y != 500 {
+		t.Errorf("insertion point: got (%v, %v), want (1000, 500)", img.X, img.Y)
+	}
+	// Rotated 90°: U points up, V points left, 2 drawing units per pixel
+	if math.Abs(img.UX) > 1e-9 || math.Abs(img.UY-2) > 1e-9 || math.Abs(img.VX+2) > 1e-9 || math.Abs(img.VY) > 1e-9 {
+		t.Errorf("pixel vectors: got U (%v, %v) V (%v, %v)", img.UX, img.UY, img.VX, img.VY)
+	}
+	if img.Clipped {
+		t.Error("expected no clipping")
+	}
+
+	if len(result.ImageDefs) != 1 {
+		t.Fatalf("expected 1 image def, got %d", len(result.ImageDefs))
+	}
+	def := result.ImageDefs[0]
+	if def.Name != img.ImageDef || def.FileName != "photo.png" || def.Width != 200 {
+		t.Errorf("image def: got %q %q width %d", def.Name, def.FileName, def.Width)
+	}
+	if def.Data != nil || def.Load == nil {
+		t.Fatal("expected the bundled image to be loaded on demand")
+	}
+	if data, err := def.Load(); err != nil || !bytes.Equal(data, doc.Images[0].Data) {
+		t.Errorf("image def data does not match the bundled image: %v", err)
+	}
+}
+
+func TestConvertImage_Trimmed(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.ImageRef{
+		X: 0, Y: 0, Width: 50, Height: 100,
+		Path:  `C:\scan\plan.bmp`,
+		TrimX: 0.5, TrimY: 0, TrimWidth: 0.5, TrimHeight: 1,
+	}}
+
+	result := ConvertDocument(doc)
+
+	img := result.Entities[0].(*Image)
+	// External file: one pixel per drawing unit of the untrimmed size
+	if img.Width != 100 || img.Height != 100 {
+		t.Errorf("pixel size: got %dx%d, want 100x100", img.Width, img.Height)
+	}
+	// The right half is visible, so the full image starts 50 units to the left
+	if img.X != -50 || img.Y != 0 {
+		t.Errorf("insertion point: got (%v, %v), want (-50, 0)", img.X, img.Y)
+	}
+	if !img.Clipped || img.ClipX1 != 49.5 || img.ClipX2 != 99.5 || img.ClipY1 != -0.5 || img.ClipY2 != 99.5 {
+		t.Errorf("clip: got %v (%v, %v)-(%v, %v)", img.Clipped, img.ClipX1, img.ClipY1, img.ClipX2, img.ClipY2)
+	}
+	if def := result.ImageDefs[0]; def.FileName != `C:\scan\plan.bmp` || def.Data != nil || def.Load != nil {
+		t.Errorf("external image def: got %q with %d bytes", def.FileName, len(def.Data))
+	}
+}
+
+func TestWriteDocument_Images(t *testing.T) {
+	doc := &Document{
+		Entities: []Entity{
+			&Image{Layer: "0", ImageDef: "a.png", UX: 1, VY: 1, Width: 10, Height: 10},
+			&Image{Layer: "0", ImageDef: "a.png", X: 20, UX: 1, VY: 1, Width: 10, Height: 10},
+		},
+		ImageDefs: []ImageDef{{Name: "a.png", FileName: "a.png", Width: 10, Height: 10}},
+	}
+
+	out := ToString(doc)
+
+	for _, want := range []string{"CLASSES", "OBJECTS", "ACAD_IMAGE_DICT", "IMAGEDEF\n", "AcDbRasterImageDefReactor"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if n := strings.Count(out, "  0\nIMAGEDEF_REACTOR\n"); n != 2 {
+		t.Errorf("expected 2 IMAGEDEF_REACTOR objects, got %d", n)
+	}
+
+	img := doc.Entities[0].(*Image)
+	if img.defHandle == "" || img.reactorHandle == "" || img.handle == "" {
+		t.Fatal("expected image handles to be assigned")
+	}
+	if !strings.Contains(out, "340\n"+img.defHandle+"\n") || !strings.Contains(out, "360\n"+img.reactorHandle+"\n") {
+		t.Error("image does not reference its IMAGEDEF and reactor")
+	}
+}
+
+func TestWriteDocument_NoImages(t *testing.T) {
+	out := ToString(&Document{Entities: []Entity{NewLine(0, 0, 1, 1)}})
+	if strings.Contains(out, "OBJECTS") || strings.Contains(out, "CLASSES") {
+		t.Error("expected no CLASSES/OBJECTS sections without images")
+	}
+}
+
+func TestWriteImageFiles(t *testing.T) {
+	dir := t.TempDir()
+	doc := &Document{ImageDefs: []ImageDef{
+		{Name: "a", FileName: "a.png", Data: []byte("png")},
+		{Name: "b", FileName: `C:\ext\b.png`},
+		{Name: "c", FileName: "c.png", Load: func() ([]byte, error) { return []byte("loaded"), nil }},
+	}}
+
+	if err := WriteImageFiles(doc, dir); err != nil {
+		t.Fatalf("WriteImageFiles failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "a.png"))
+	if err != nil || string(data) != "png" {
+		t.Errorf("a.png: got %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "c.png")); err != nil || string(data) != "loaded" {
+		t.Errorf("c.png: got %q, %v", data, err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected only the bundled images to be written, got %d files", len(entries))
+	}
+
+	doc.ImageDefs[2].Load = func() ([]byte, error) { return nil, jww.ErrImageTooLarge }
+	if err := WriteImageFiles(doc, dir); !errors.Is(err, jww.ErrImageTooLarge) {
+		t.Errorf("expected the load error, got %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package dxf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// convertLineTypes creates the LTYPE definitions for every JWW line type used
+// by the drawing and its blocks. JWW dash lengths are paper sizes, so each
+// line type is emitted once per layer group scale it is drawn at, with the
+// dashes converted to drawing units.
+func convertLineTypes(doc *jww.Document) []LineType {
+	s := newLineTypeSet(doc)
+	s.visit(doc.Entities)
+	for _, bd := range doc.BlockDefs {
+		s.visit(bd.Entities)
+	}
+	return s.lineTypes
+}
+
+// lineTypeSet collects the line types used by entities in order of first use.
+type lineTypeSet struct {
+	doc       *jww.Document
+	lineTypes []LineType
+	seen      map[string]bool
+}
+
+func newLineTypeSet(doc *jww.Document) *lineTypeSet {
+	return &lineTypeSet{doc: doc, seen: make(map[string]bool)}
+}
+
+// visit adds the line types used by entities.
+func (s *lineTypeSet) visit(entities []jww.Entity) {
+	for _, e := range entities {
+		switch v := e.(type) {
+		case *jww.Dimension:
+			s.add(v.Line.Base())
+			s.add(v.AuxLines[0].Base())
+			s.add(v.AuxLines[1].Base())
+			s.add(v.Text.Base())
+		case *jww.CircleSolid:
+			// Filled regions and outlines are drawn continuous
+		default:
+			s.add(e.Base())
+		}
+	}
+}
+
+func (s *lineTypeSet) add(base *jww.EntityBase) {
+	lt, ok := jwwLineType(s.doc, base.PenStyle, base.LayerGroup)
+	if !ok || s.seen[lt.Name] {
+		return
+	}
+	s.seen[lt.Name] = true
+	s.lineTypes = append(s.lineTypes, lt)
+}
+
+// lineTypeName returns the DXF line type name for a JWW pen style drawn in the
+// given layer group. Styles without a usable pattern in the file header fall
+// back to the standard names of mapLineType.
+func lineTypeName(doc *jww.Document, penStyle byte, layerGroup uint16) string {
+	if lt, ok := jwwLineType(doc, penStyle, layerGroup); ok {
+		return lt.Name
+	}
+	return mapLineType(penStyle)
+}
+
+// jwwLineType builds the DXF line type for a JWW pen style drawn in the given
+// layer group from the patterns stored in the file header:
+//   - 2-9: line types 2-9 (線種2～9)
+//   - 16-19: double-length line types 6-9 (倍長線種)
+//   - 30-62: SXF extended line types (Ver.4.20 and later)
+//
+// Names are "JWW<n>", with "_S<scale>" appended for layer group scales other
+// than 1:1. It reports false for continuous lines, random lines and styles
+// without a pattern.
+func jwwLineType(doc *jww.Document, penStyle byte, layerGroup uint16) (LineType, bool) {
+	var dashes []float64 // Paper millimetres
+	desc := fmt.Sprintf("Jw_cad line type %d", penStyle)
+
+	switch {
+	case penStyle >= 2 && penStyle <= 9:
+		dashes = patternDashes(doc.Header.LineTypes[penStyle], &doc.Header)
+	case penStyle >= 16 && penStyle <= 19:
+		dashes = patternDashes(doc.Header.DoubleLineTypes[penStyle-16], &doc.Header)
+		desc = fmt.Sprintf("Jw_cad double-length line type %d", penStyle-10)
+	case penStyle >= 30 && int(penStyle)-30 < len(doc.Header.SXFLineTypes):
+		sxf := &doc.Header.SXFLineTypes[penStyle-30]
+		dashes = sxfDashes(sxf, &doc.Header)
+		if name := strings.TrimSpace(sxf.Name); name != "" {
+			desc = name
+		}
+	}
+	if len(dashes) == 0 {
+		return LineType{}, false
+	}
+
+	scale := layerGroupScale(doc, layerGroup)
+	name := fmt.Sprintf("JWW%d", penStyle)
+	if scale != 1 {
+		name = fmt.Sprintf("%s_S%g", name, scale)
+		desc = fmt.Sprintf("%s (1:%g)", desc, scale)
+	}
+
+	pattern := make([]float64, len(dashes))
+	for i, d := range dashes {
+		pattern[i] = d * scale
+	}
+	return LineType{Name: name, Description: desc, Pattern: pattern}, true
+}
+
+// patternDashes converts a JWW dot pattern to dash lengths in paper
+// millimetres. Each dot of the pattern spans the printer pitch of the line
+// type (m_anPrtTokushuSenPich, プリンタ出力ピッチ), or the screen pitch when
+// no printer pitch is set, in printer dots like the printer line widths of
+// the pen tables (see printerDotSize). Dashes of a single dot are written as
+// DXF dots, which have no length.
+func patternDashes(def jww.LineTypeDef, hd *jww.Header) []float64 {
+	pitch := def.PrinterPitch
+	if pitch == 0 {
+		pitch = def.Pitch
+	}
+	if pitch == 0 {
+		return nil
+	}
+
+	runs := def.Dashes()
+	dashes := make([]float64, len(runs))
+	for i, r := range runs {
+		if r != 1 {
+			dashes[i] = float64(r) * float64(pitch) * printerDotSize(hd)
+		}
+	}
+	return dashes
+}
+
+// sxfDashes returns the dash lengths of an SXF line type. Its pitches are
+// alternating segment and gap lengths in paper millimetres; when none are
+// set the dot pattern is used instead.
+func sxfDashes(lt *jww.SXFLineType, hd *jww.Header) []float64 {
+	n := min(int(lt.Segments), len(lt.Pitches))
+
+	var dashes []float64
+	for i := 0; i < n; i++ {
+		p := lt.Pitches[i]
+		if p <= 0 {
+			return patternDashes(lt.LineTypeDef, hd)
+		}
+		if i%2 == 1 {
+			p = -p
+		}
+		dashes = append(dashes, p)
+	}
+	if len(dashes) < 2 {
+		return patternDashes(lt.LineTypeDef, hd)
+	}
+	return dashes
+}
+
+// layerGroupScale returns the scale denominator of a layer group, or 1 when
+// it is unknown.
+func layerGroupScale(doc *jww.Document, layerGroup uint16) float64 {
+	if int(layerGroup) >= len(doc.LayerGroups) {
+		return 1
+	}
+	if scale := doc.LayerGroups[layerGroup].Scale; scale > 0 {
+		return scale
+	}
+	return 1
+}
@@ -0,0 +1,126 @@
+package dxf
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+func TestConvertLineTypes(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[1].Scale = 100
+	// Line type 2: 12 dots on, 4 off at a printer pitch of 3 (0.127 mm per
+	// dot at 600 dpi)
+	doc.Header.LineTypes[2] = jww.LineTypeDef{Pattern: 0xFFF0, UnitDots: 16, Pitch: 1, PrinterPitch: 3}
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{PenStyle: 2, LayerGroup: 0}, EndX: 10},
+		&jww.Line{EntityBase: jww.EntityBase{PenStyle: 2, LayerGroup: 1}, EndX: 10},
+		&jww.Line{EntityBase: jww.EntityBase{PenStyle: 2, LayerGroup: 1}, EndY: 10},
+		&jww.Line{EntityBase: jww.EntityBase{PenStyle: 1}, EndX: 10},
+	}
+
+	result := ConvertDocument(doc)
+
+	if len(result.LineTypes) != 2 {
+		t.Fatalf("expected 2 line types, got %d", len(result.LineTypes))
+	}
+	checkPattern := func(lt LineType, name string, want []float64) {
+		t.Helper()
+		if lt.Name != name {
+			t.Errorf("name: got %q, want %q", lt.Name, name)
+		}
+		if len(lt.Pattern) != len(want) {
+			t.Fatalf("%s pattern: got %v, want %v", name, lt.Pattern, want)
+		}
+		for i := range want {
+			if math.Abs(lt.Pattern[i]-want[i]) > 1e-9 {
+				t.Errorf("%s pattern: got %v, want %v", name, lt.Pattern, want)
+				break
+			}
+		}
+	}
+	checkPattern(result.LineTypes[0], "JWW2", []float64{1.524, -0.508})
+	checkPattern(result.LineTypes[1], "JWW2_S100", []float64{152.4, -50.8})
+
+	wantNames := []string{"JWW2", "JWW2_S100", "JWW2_S100", "CONTINUOUS"}
+	for i, e := range result.Entities {
+		if got := e.(*Line).LineType; got != wantNames[i] {
+			t.Errorf("entity %d line type: got %q, want %q", i, got, wantNames[i])
+		}
+	}
+
+	out := ToString(result)
+	if !strings.Contains(out, "  2\nJWW2_S100\n") {
+		t.Error("LTYPE table missing JWW2_S100")
+	}
+}
+
+func TestConvertLineTypes_Fallback(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.Line{EntityBase: jww.EntityBase{PenStyle: 3}, EndX: 10}}
+
+	result := ConvertDocument(doc)
+
+	if len(result.LineTypes) != 0 {
+		t.Errorf("expected no line types without header patterns, got %d", len(result.LineTypes))
+	}
+	if got := result.Entities[0].(*Line).LineType; got != "DASHDOT" {
+		t.Errorf("line type: got %q, want DASHDOT", got)
+	}
+}
+
+func TestJwwLineType_DoubleAndSXF(t *testing.T) {
+	doc := createTestDocument()
+	doc.Header.DoubleLineTypes[0] = jww.LineTypeDef{Pattern: 0b1100, UnitDots: 4, PrinterPitch: 10}
+	doc.Header.SXFLineTypes = make([]jww.SXFLineType, 33)
+	doc.Header.SXFLineTypes[2] = jww.SXFLineType{
+		Name:     "dash dot",
+		Segments: 4,
+		Pitches:  [10]float64{5, 1, 0.5, 1},
+	}
+
+	lt, ok := jwwLineType(doc, 16, 0)
+	if !ok || lt.Name != "JWW16" || len(lt.Pattern) != 2 || math.Abs(lt.Pattern[0]-20*25.4/600) > 1e-9 {
+		t.Errorf("double-length line type: got %+v, %v", lt, ok)
+	}
+
+	lt, ok = jwwLineType(doc, 32, 0)
+	if !ok || lt.Description != "dash dot" {
+		t.Fatalf("SXF line type: got %+v, %v", lt, ok)
+	}
+	want := []float64{5, -1, 0.5, -1}
+	for i := range want {
+		if lt.Pattern[i] != want[i] {
+			t.Errorf("SXF pattern: got %v, want %v", lt.Pattern, want)
+			break
+		}
+	}
+
+	if _, ok := jwwLineType(doc, 31, 0); ok {
+		t.Error("expected an undefined SXF line type to be continuous")
+	}
+	if _, ok := jwwLineType(doc, 12, 0); ok {
+		t.Error("expected random lines to be continuous")
+	}
+}
+
+func TestPatternDashes(t *testing.T) {
+	// Dash, gap, dot, gap at 300 dpi: 1 dot = 25.4/300 mm
+	hd := &jww.Header{PrintSkipDisplayOnly: 10}
+	def := jww.LineTypeDef{Pattern: 0b11110100, UnitDots: 8, PrinterPitch: 3}
+
+	got := patternDashes(def, hd)
+	dot := 25.4 / 300 * 3
+	want := []float64{4 * dot, -dot, 0, -2 * dot}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("got %v, want %v", got, want)
+			break
+		}
+	}
+}
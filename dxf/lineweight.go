@@ -0,0 +1,54 @@
+package dxf
+
+import "math"
+
+// Special DXF lineweight values (group code 370).
+const (
+	// LineWeightByLayer uses the weight of the entity's layer.
+	LineWeightByLayer = -1
+
+	// LineWeightByBlock uses the weight of the enclosing block insert.
+	LineWeightByBlock = -2
+
+	// LineWeightDefault uses the application's default weight.
+	LineWeightDefault = -3
+)
+
+// lineWeights are the standard DXF lineweights in 1/100 mm, excluding 0.
+var lineWeights = []int{5, 9, 13, 15, 18, 20, 25, 30, 35, 40, 50, 53, 60, 70, 80, 90, 100, 106, 120, 140, 158, 200, 211}
+
+// NearestLineWeight returns the standard DXF lineweight (in 1/100 mm) closest
+// to the given width in 1/100 mm. Widths beyond the largest standard value
+// return 211 (2.11 mm), and non-positive widths return 0, which writers treat
+// as "not set".
+//
+// Example:
+//
+//	lw := dxf.NearestLineWeight(27) // Returns 25 (0.25 mm)
+func NearestLineWeight(hundredthsMM float64) int {
+	if hundredthsMM <= 0 {
+		return 0
+	}
+
+	best, bestDist := lineWeights[0], math.Inf(1)
+	for _, lw := range lineWeights {
+		if d := math.Abs(float64(lw) - hundredthsMM); d < bestDist {
+			best, bestDist = lw, d
+		}
+	}
+	return best
+}
+
+// withLineWeight inserts the lineweight group code 370 after the linetype
+// (group code 6) when lineWeight is set.
+func withLineWeight(codes []GroupCode, lineWeight int) []GroupCode {
+	if lineWeight == 0 {
+		return codes
+	}
+	for k, gc := range codes {
+		if gc.Code == 6 {
+			return append(codes[:k+1], append([]GroupCode{{370, lineWeight}}, codes[k+1:]...)...)
+		}
+	}
+	return codes
+}
@@ -0,0 +1,50 @@
+package dxf
+
+import "testing"
+
+func TestNearestLineWeight(t *testing.T) {
+	cases := []struct {
+		width float64
+		want  int
+	}{
+		{0, 0},
+		{-5, 0},
+		{1, 5},
+		{27, 25},
+		{28, 30},
+		{50, 50},
+		{104, 106},
+		{500, 211},
+	}
+
+	for _, c := range cases {
+		if got := NearestLineWeight(c.width); got != c.want {
+			t.Errorf("NearestLineWeight(%v): got %d, want %d", c.width, got, c.want)
+		}
+	}
+}
+
+func TestLineGroupCodes_LineWeight(t *testing.T) {
+	line := &Line{Layer: "0", LineType: "CONTINUOUS", LineWeight: 35}
+
+	codes := line.GroupCodes()
+	found := false
+	for k, gc := range codes {
+		if gc.Code == 370 {
+			found = true
+			if gc.Value != 35 || codes[k-1].Code != 6 {
+				t.Errorf("lineweight: got %v after code %d", gc.Value, codes[k-1].Code)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected group code 370")
+	}
+
+	line.LineWeight = 0
+	for _, gc := range line.GroupCodes() {
+		if gc.Code == 370 {
+			t.Error("expected no group code 370 for an unset lineweight")
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package dxf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MTextEscape escapes s for use as literal MTEXT content: backslashes and
+// braces are escaped and newlines become paragraph breaks (\P).
+//
+// Example:
+//
+//	content := dxf.MTextEscape("C:\\dir {1}") // Returns "C:\\\\dir \\{1\\}"
+func MTextEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', '{', '}':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\P`)
+		case '\r':
+			// Dropped; "\r\n" is a single paragraph break
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// MTextParagraphs joins already formatted paragraphs with paragraph breaks.
+//
+// Example:
+//
+//	content := dxf.MTextParagraphs("Line 1", dxf.MTextUnderline("Line 2"))
+func MTextParagraphs(paragraphs ...string) string {
+	return strings.Join(paragraphs, `\P`)
+}
+
+// MTextUnderline wraps formatted content in underline codes (\L...\l).
+func MTextUnderline(s string) string {
+	return `\L` + s + `\l`
+}
+
+// MTextOverline wraps formatted content in overline codes (\O...\o).
+func MTextOverline(s string) string {
+	return `\O` + s + `\o`
+}
+
+// MTextStrikethrough wraps formatted content in strikethrough codes (\K...\k).
+func MTextStrikethrough(s string) string {
+	return `\K` + s + `\k`
+}
+
+// MTextStack returns a stacked pair of texts without a fraction bar, as used
+// for tolerances (\Supper^lower;).
+//
+// Example:
+//
+//	tol := dxf.MTextStack("+0.1", "-0.2")
+func MTextStack(upper, lower string) string {
+	return `\S` + mtextStackEscape(upper) + "^" + mtextStackEscape(lower) + ";"
+}
+
+// MTextFraction returns a stacked fraction with a horizontal bar
+// (\Snumerator/denominator;).
+//
+// Example:
+//
+//	half := dxf.MTextFraction("1", "2")
+func MTextFraction(numerator, denominator string) string {
+	return `\S` + mtextStackEscape(numerator) + "/" + mtextStackEscape(denominator) + ";"
+}
+
+// mtextStackEscape escapes the characters that end or split a stack.
+func mtextStackEscape(s string) string {
+	return strings.NewReplacer("^", `\^`, "/", `\/`, "#", `\#`, ";", `\;`).Replace(s)
+}
+
+// MTextFont switches formatted content to a TrueType font family with the
+// given weight and slant, limited to the content by a brace group
+// ({\fFont|b1|i0;...}).
+//
+// Example:
+//
+//	title := dxf.MTextFont("ＭＳ ゴシック", true, false, "図面名")
+func MTextFont(family string, bold, italic bool, s string) string {
+	return fmt.Sprintf(`{\f%s|b%d|i%d;%s}`, family, boolDigit(bold), boolDigit(italic), s)
+}
+
+// MTextHeight scales the character height of formatted content by the given
+// factor ({\H2x;...}).
+func MTextHeight(factor float64, s string) string {
+	return `{\H` + strconv.FormatFloat(factor, 'f', -1, 64) + `x;` + s + `}`
+}
+
+// MTextColor sets the ACI color of formatted content ({\C1;...}).
+func MTextColor(color int, s string) string {
+	return fmt.Sprintf(`{\C%d;%s}`, color, s)
+}
+
+func boolDigit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// MTextPlain strips the formatting codes from MTEXT content and returns the
+// displayed text, with paragraph breaks as newlines. Stacked texts are
+// rendered as "upper/lower".
+//
+// Example:
+//
+//	text := dxf.MTextPlain(`{\fArial|b1;Title}\PA \S1/2;`) // Returns "Title\nA 1/2"
+func MTextPlain(content string) string {
+	var b strings.Builder
+	runes := []rune(content)
+
+	// skipTo returns the index just past the next ';' at or after i.
+	skipTo := func(i int) int {
+		for i < len(runes) && runes[i] != ';' {
+			i++
+		}
+		return i + 1
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case '{', '}':
+			continue
+		case '\n':
+			b.WriteByte('\n')
+			continue
+		case '\\':
+		default:
+			b.WriteRune(r)
+			continue
+		}
+
+		if i+1 >= len(runes) {
+			break
+		}
+		i++
+		switch c := runes[i]; c {
+		case 'P':
+			b.WriteByte('\n')
+		case '~':
+			b.WriteByte(' ')
+		case 'L', 'l', 'O', 'o', 'K', 'k':
+			// Line decorations take no space
+		case 'f', 'F', 'H', 'W', 'Q', 'T', 'A', 'C', 'c', 'p':
+			i = skipTo(i) - 1
+		case 'S':
+			end := skipTo(i) - 1
+			for j := i + 1; j < end && j < len(runes); j++ {
+				switch ch := runes[j]; {
+				case ch == '\\' && j+1 < end:
+					j++
+					b.WriteRune(runes[j])
+				case ch == '^' || ch == '#':
+					b.WriteByte('/')
+				default:
+					b.WriteRune(ch)
+				}
+			}
+			i = end
+		case 'U':
+			if i+5 < len(runes) && runes[i+1] == '+' {
+				if v, err := strconv.ParseUint(string(runes[i+2:i+6]), 16, 32); err == nil {
+					b.WriteRune(rune(v))
+					i += 5
+					continue
+				}
+			}
+			b.WriteRune(c)
+		default:
+			// Escaped literal (\\, \{, \}) or unknown code
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
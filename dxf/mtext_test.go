@@ -0,0 +1,114 @@
+package dxf
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestMTextFormatting(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"escape", MTextEscape("a\\b {c}\r\nd"), `a\\b \{c\}\Pd`},
+		{"paragraphs", MTextParagraphs("one", "two"), `one\Ptwo`},
+		{"underline", MTextUnderline("u"), `\Lu\l`},
+		{"overline", MTextOverline("o"), `\Oo\o`},
+		{"strikethrough", MTextStrikethrough("k"), `\Kk\k`},
+		{"stack", MTextStack("+0.1", "-0.2"), `\S+0.1^-0.2;`},
+		{"fraction", MTextFraction("1", "2"), `\S1/2;`},
+		{"stack escape", MTextFraction("a/b", "c"), `\Sa\/b/c;`},
+		{"font", MTextFont("Arial", true, false, "T"), `{\fArial|b1|i0;T}`},
+		{"height", MTextHeight(1.5, "h"), `{\H1.5x;h}`},
+		{"color", MTextColor(1, "red"), `{\C1;red}`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestMTextPlain(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"plain", "plain"},
+		{`{\fArial|b1|i0;Title}\PA \S1/2;`, "Title\nA 1/2"},
+		{`\LUnder\l and {\H2x;\C3;big}`, "Under and big"},
+		{`\S+0.1^-0.2;`, "+0.1/-0.2"},
+		{`a\\b \{c\}`, `a\b {c}`},
+		{`\U+56F3\~x`, "図 x"},
+	}
+	for _, tt := range tests {
+		if got := MTextPlain(tt.content); got != tt.want {
+			t.Errorf("MTextPlain(%q): got %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestMTextGroupCodes(t *testing.T) {
+	mtext := NewMText(1, 2, "Line 1\nLine 2", WithMTextAttachment(MTextBottomRight))
+	out := ToString(&Document{Entities: []Entity{mtext}})
+
+	for _, want := range []string{"  0\nMTEXT\n", " 71\n9\n", "  1\nLine 1\\PLine 2\n", "  7\nSTANDARD\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestMTextGroupCodes_LongContent(t *testing.T) {
+	content := strings.Repeat("abcdefghij", 60)
+	codes := (&MText{Content: content}).GroupCodes()
+
+	var joined strings.Builder
+	var chunks3, chunks1 int
+	for _, gc := range codes {
+		switch gc.Code {
+		case 3:
+			chunks3++
+			joined.WriteString(gc.Value.(string))
+		case 1:
+			chunks1++
+			joined.WriteString(gc.Value.(string))
+		}
+		if s, ok := gc.Value.(string); ok && len(s) > mtextChunkSize {
+			t.Errorf("group %d exceeds %d bytes", gc.Code, mtextChunkSize)
+		}
+	}
+	if chunks3 != 2 || chunks1 != 1 {
+		t.Errorf("expected 2 group 3 chunks and 1 group 1 chunk, got %d and %d", chunks3, chunks1)
+	}
+	if joined.String() != content {
+		t.Error("chunks do not reassemble to the content")
+	}
+}
+
+func TestMTextBoundingBox(t *testing.T) {
+	tests := []struct {
+		name  string
+		mtext *MText
+		want  [4]float64
+	}{
+		{"top left", NewMText(10, 10, "Hello", WithMTextHeight(4)), [4]float64{10, 6, 20, 10}},
+		{"two lines", NewMText(0, 0, `AB\PCD`, WithMTextHeight(3)), [4]float64{0, -8, 3, 0}},
+		{"middle center", NewMText(0, 0, "x", WithMTextHeight(2), WithMTextWidth(10),
+			WithMTextAttachment(MTextMiddleCenter)), [4]float64{-5, -1, 5, 1}},
+		{"rotated", NewMText(0, 0, "図", WithMTextHeight(2),
+			WithMTextAttachment(MTextBottomLeft), WithMTextRotation(90)), [4]float64{-2, 0, 0, 2}},
+	}
+	for _, tt := range tests {
+		minX, minY, maxX, maxY := tt.mtext.BoundingBox()
+		got := [4]float64{minX, minY, maxX, maxY}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
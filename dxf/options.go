@@ -0,0 +1,194 @@
+package dxf
+
+import "github.com/f4ah6o/jww-parser/jww"
+
+// ConvertOption configures how ConvertDocument converts a JWW document.
+type ConvertOption func(*convertConfig)
+
+// convertConfig holds the settings applied by ConvertOption functions.
+type convertConfig struct {
+	// fontMap maps JWW font names to DXF font files, overriding DefaultFontMap.
+	fontMap map[string]string
+
+	// explodeDimensions converts dimensions to their component entities
+	// instead of DIMENSION entities.
+	explodeDimensions bool
+
+	// explodeHatching keeps hatching lines as drawn instead of recognizing
+	// pattern HATCH entities.
+	explodeHatching bool
+
+	// attributeLayers collects the sub-layers for attribute flags when
+	// WithAttributeLayers is given.
+	attributeLayers *attributeLayers
+
+	// solidFill selects the entities JWW solids are converted to.
+	solidFill SolidFillMode
+
+	// progress receives the progress of the conversion when WithProgress is
+	// given. converted counts the JWW entities converted so far, out of
+	// total when known.
+	progress  jww.ProgressFunc
+	converted int
+	total     int
+
+	// layers holds the layer names of the converted document, built on
+	// first use by layerName.
+	layers *layerNames
+
+	// blocks holds the block names of the converted document, built on
+	// first use by blockName.
+	blocks blockNames
+}
+
+// layerName returns the DXF layer name for a JWW layer group and layer of
+// doc, the document being converted.
+func (c *convertConfig) layerName(doc *jww.Document, layerGroup, layer uint16) string {
+	if c.layers == nil {
+		c.layers = newLayerNames(doc)
+	}
+	return c.layers.name(layerGroup, layer)
+}
+
+// blockName returns the DXF block name for a JWW block definition number of
+// doc, the document being converted.
+func (c *convertConfig) blockName(doc *jww.Document, defNumber uint32) string {
+	if c.blocks == nil {
+		c.blocks = newBlockNames(doc)
+	}
+	return c.blocks.name(defNumber)
+}
+
+// progressInterval is the number of entities converted or written between
+// progress reports.
+const progressInterval = 1024
+
+// count counts a JWW entity converted, reporting the progress every
+// progressInterval entities.
+func (c *convertConfig) count() {
+	if c.converted++; c.converted%progressInterval == 0 {
+		c.report()
+	}
+}
+
+// report reports the entities converted so far as jww.StageConverting.
+func (c *convertConfig) report() {
+	if c.progress != nil {
+		c.progress(jww.Progress{Stage: jww.StageConverting, Entities: c.converted, TotalEntities: c.total})
+	}
+}
+
+// newConvertConfig returns the default settings with opts applied.
+func newConvertConfig(opts []ConvertOption) *convertConfig {
+	cfg := &convertConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithFontMap maps JWW font names (e.g. "ＭＳ ゴシック") to the font files
+// used by the generated text styles. Entries take precedence over
+// DefaultFontMap. A value of the form "font.shx,bigfont.shx" selects an SHX
+// font with a big font.
+//
+// Example:
+//
+//	dxfDoc := dxf.ConvertDocument(doc, dxf.WithFontMap(map[string]string{
+//		"ＭＳ ゴシック": "romans.shx,extfont2.shx",
+//	}))
+func WithFontMap(fonts map[string]string) ConvertOption {
+	return func(c *convertConfig) {
+		if c.fontMap == nil {
+			c.fontMap = make(map[string]string)
+		}
+		for name, file := range fonts {
+			c.fontMap[name] = file
+		}
+	}
+}
+
+// WithExplodedDimensions converts JWW dimensions to separate lines and texts,
+// as drawn, instead of DIMENSION entities.
+//
+// Example:
+//
+//	dxfDoc := dxf.ConvertDocument(doc, dxf.WithExplodedDimensions())
+func WithExplodedDimensions() ConvertOption {
+	return func(c *convertConfig) {
+		c.explodeDimensions = true
+	}
+}
+
+// WithExplodedHatching keeps JWW hatching (ハッチ) as the lines drawn by
+// Jw_cad instead of converting recognized regions to pattern HATCH entities.
+//
+// Example:
+//
+//	dxfDoc := dxf.ConvertDocument(doc, dxf.WithExplodedHatching())
+func WithExplodedHatching() ConvertOption {
+	return func(c *convertConfig) {
+		c.explodeHatching = true
+	}
+}
+
+// WithAttributeLayers moves entities carrying JWW attribute flags (属性フラグ)
+// to sub-layers named after their layer and attribute, e.g. "壁_HATCH" for
+// hatching, "壁_DIM" for dimension parts and values, "壁_FITTING" for fittings
+// (建具) and "壁_FIGURE" for figures (図形). The sub-layers copy the
+// properties of their layer. See AttributeLayerSuffix for all suffixes.
+//
+// Example:
+//
+//	dxfDoc := dxf.ConvertDocument(doc, dxf.WithAttributeLayers())
+func WithAttributeLayers() ConvertOption {
+	return func(c *convertConfig) {
+		c.attributeLayers = &attributeLayers{parents: make(map[string]string)}
+	}
+}
+
+// SolidFillMode selects how JWW solids (ソリッド) are converted.
+type SolidFillMode int
+
+const (
+	// SolidFillHatch converts each JWW solid to a solid filled HATCH. Circle
+	// solids get exact arc and ellipse boundary edges. This is the default.
+	SolidFillHatch SolidFillMode = iota
+
+	// SolidFillMerged converts JWW solids to HATCH entities like
+	// SolidFillHatch, and merges quadrilateral solids of the same layer and
+	// color that share whole edges into one HATCH per connected region.
+	SolidFillMerged
+
+	// SolidFillSolid converts JWW solids to DXF SOLID entities, approximating
+	// circle solids with triangles and quadrilaterals.
+	SolidFillSolid
+)
+
+// WithSolidFill selects how JWW solids are converted.
+//
+// Example:
+//
+//	dxfDoc := dxf.ConvertDocument(doc, dxf.WithSolidFill(dxf.SolidFillMerged))
+func WithSolidFill(mode SolidFillMode) ConvertOption {
+	return func(c *convertConfig) {
+		c.solidFill = mode
+	}
+}
+
+// WithProgress reports the progress of the conversion to fn: ConvertDocument
+// reports the JWW entities converted as jww.StageConverting, and
+// ConvertStream also reports the DXF file written as jww.StageWriting. Use
+// Writer.SetProgress for the writing of a converted Document, and
+// jww.ParseOptions.Progress for parsing.
+//
+// Example:
+//
+//	dxfDoc := dxf.ConvertDocument(doc, dxf.WithProgress(func(p jww.Progress) {
+//		fmt.Printf("%s: %d/%d entities\n", p.Stage, p.Entities, p.TotalEntities)
+//	}))
+func WithProgress(fn jww.ProgressFunc) ConvertOption {
+	return func(c *convertConfig) {
+		c.progress = fn
+	}
+}
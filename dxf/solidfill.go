@@ -0,0 +1,417 @@
+package dxf
+
+import (
+	"math"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// solidMergeTolerance is the distance below which solid vertices are treated
+// as the same point when merging solids.
+const solidMergeTolerance = 1e-6
+
+// convertSolidHatch converts a JWW solid (ソリッド) to a solid filled HATCH
+// with a polygon boundary. Triangles, stored with two equal points, get a
+// three vertex boundary. It returns nil for solids that enclose no area, with
+// all corners on one line, which CAD readers reject as HATCH boundaries.
+func convertSolidHatch(s *jww.Solid, doc *jww.Document, cfg *convertConfig) *Hatch {
+	color, trueColor, colorName := entityColor(doc, s.PenColor)
+	if s.PenColor == 10 {
+		color, trueColor = rgbColor(s.Color)
+	}
+
+	// DXF SOLID vertex order is 1-2-4-3 around the outline
+	xy := []float64{s.Point1X, s.Point1Y, s.Point2X, s.Point2Y, s.Point4X, s.Point4Y, s.Point3X, s.Point3Y}
+	boundary := PolygonBoundary(xy...)
+	vertices := boundary.Vertices[:0]
+	for i, v := range boundary.Vertices {
+		prev := boundary.Vertices[(i+3)%4]
+		if v.X == prev.X && v.Y == prev.Y {
+			continue
+		}
+		vertices = append(vertices, v)
+	}
+	boundary.Vertices = vertices
+
+	// Solids thinner than the merge tolerance count as lines
+	var perimeter float64
+	for i, v := range vertices {
+		next := vertices[(i+1)%len(vertices)]
+		perimeter += math.Hypot(next.X-v.X, next.Y-v.Y)
+	}
+	if len(vertices) < 3 || math.Abs(polygonArea(vertices)) <= solidMergeTolerance*perimeter {
+		return nil
+	}
+
+	return &Hatch{
+		Layer:      cfg.layerName(doc, s.LayerGroup, s.Layer),
+		Color:      color,
+		TrueColor:  trueColor,
+		ColorName:  colorName,
+		Pattern:    "SOLID",
+		Boundaries: []HatchBoundary{boundary},
+	}
+}
+
+// convertCircleSolidHatch converts a JWW circle solid (円ソリッド) to a solid
+// filled HATCH bounded by arc or ellipse edges; circumference solids fill
+// the band given by circumferenceSolidBand. It returns nil for regions that
+// cannot be represented, including circumference solids of unknown width,
+// which convertCircleSolid draws as their outline instead.
+func convertCircleSolidHatch(cs *jww.CircleSolid, doc *jww.Document, cfg *convertConfig) *Hatch {
+	base := cs.Base()
+	color, trueColor, colorName := entityColor(doc, base.PenColor)
+	if base.PenColor == 10 {
+		color, trueColor = rgbColor(cs.Color)
+	}
+
+	full := cs.IsFullCircle()
+	start, end := cs.StartAngle, cs.StartAngle+cs.ArcAngle
+	if full {
+		start, end = 0, 2*math.Pi
+	}
+
+	// pointAt returns the point at parameter t on an ellipse with the solid's
+	// center and tilt and the given semi-axes.
+	sin, cos := math.Sincos(cs.TiltAngle)
+	pointAt := func(a, b, t float64) (float64, float64) {
+		lx, ly := a*math.Cos(t), b*math.Sin(t)
+		return cs.CenterX + lx*cos - ly*sin, cs.CenterY + lx*sin + ly*cos
+	}
+	// curve returns the edge from parameter t1 to t2 on an ellipse with the
+	// solid's center and tilt and the given semi-axes.
+	curve := func(a, b, t1, t2 float64) HatchEdge {
+		var e HatchEdge
+		switch {
+		case a == b:
+			e = ArcEdge(cs.CenterX, cs.CenterY, a, radToDeg(cs.TiltAngle+t1), radToDeg(cs.TiltAngle+t2))
+		case b > a:
+			// The major axis is perpendicular to the tilt; shift the
+			// parameters to measure them from it
+			e = EllipseEdge(cs.CenterX, cs.CenterY, -b*sin, b*cos, a/b, radToDeg(t1)-90, radToDeg(t2)-90)
+		default:
+			e = EllipseEdge(cs.CenterX, cs.CenterY, a*cos, a*sin, b/a, radToDeg(t1), radToDeg(t2))
+		}
+		e.Clockwise = t2 < t1
+		return e
+	}
+
+	// ring returns the boundary of the region between two ellipses
+	ring := func(outerA, outerB, innerA, innerB float64) []HatchBoundary {
+		if full {
+			// The hole runs opposite to the outer boundary
+			return []HatchBoundary{
+				{Edges: []HatchEdge{curve(outerA, outerB, start, end)}},
+				{Edges: []HatchEdge{curve(innerA, innerB, end, start)}, Hole: true},
+			}
+		}
+		sx, sy := pointAt(outerA, outerB, start)
+		ex, ey := pointAt(outerA, outerB, end)
+		isx, isy := pointAt(innerA, innerB, start)
+		iex, iey := pointAt(innerA, innerB, end)
+		return []HatchBoundary{{Edges: []HatchEdge{
+			curve(outerA, outerB, start, end),
+			LineEdge(ex, ey, iex, iey),
+			curve(innerA, innerB, end, start),
+			LineEdge(isx, isy, sx, sy),
+		}}}
+	}
+
+	a, b := cs.Radius, cs.Radius*cs.Flatness
+	sx, sy := pointAt(a, b, start)
+	ex, ey := pointAt(a, b, end)
+
+	var boundaries []HatchBoundary
+	switch cs.Kind() {
+	case jww.CircleSolidSector, jww.CircleSolidDisc:
+		edges := []HatchEdge{curve(a, b, start, end)}
+		if !full {
+			edges = append(edges, LineEdge(ex, ey, cs.CenterX, cs.CenterY), LineEdge(cs.CenterX, cs.CenterY, sx, sy))
+		}
+		boundaries = []HatchBoundary{{Edges: edges}}
+
+	case jww.CircleSolidSegment:
+		edges := []HatchEdge{curve(a, b, start, end)}
+		if !full {
+			edges = append(edges, LineEdge(ex, ey, sx, sy))
+		}
+		boundaries = []HatchBoundary{{Edges: edges}}
+
+	case jww.CircleSolidOuterArc:
+		// Bounded by the end tangents, which meet on the bisector at
+		// R/cos(sweep/2) in the unscaled circle
+		half := (end - start) / 2
+		if full || math.Abs(math.Cos(half)) < 1e-9 {
+			return nil
+		}
+		d := cs.Radius / math.Cos(half)
+		tx, ty := pointAt(d, d*cs.Flatness, start+half)
+		boundaries = []HatchBoundary{{Edges: []HatchEdge{
+			curve(a, b, start, end),
+			LineEdge(ex, ey, tx, ty),
+			LineEdge(tx, ty, sx, sy),
+		}}}
+
+	case jww.CircleSolidRing, jww.CircleSolidRingOffset:
+		innerA := cs.InnerRadius()
+		innerB := innerA * cs.Flatness
+		if cs.Kind() == jww.CircleSolidRingOffset {
+			// Same difference between outer and inner on both axes
+			innerB = cs.Radius*cs.Flatness - (cs.Radius - innerA)
+		}
+		if innerA <= 0 || innerB <= 0 {
+			return nil
+		}
+		boundaries = ring(a, b, innerA, innerB)
+
+	case jww.CircleSolidCircumference:
+		outerA, outerB, innerA, innerB, ok := circumferenceSolidBand(cs, doc)
+		if !ok {
+			return nil
+		}
+		boundaries = ring(outerA, outerB, innerA, innerB)
+
+	default:
+		return nil
+	}
+
+	return &Hatch{
+		Layer:      cfg.layerName(doc, base.LayerGroup, base.Layer),
+		Color:      color,
+		TrueColor:  trueColor,
+		ColorName:  colorName,
+		Pattern:    "SOLID",
+		Boundaries: boundaries,
+	}
+}
+
+// solidVertex is a solid vertex rounded to solidMergeTolerance.
+type solidVertex struct {
+	x, y int64
+}
+
+// solidEdge is a directed boundary edge of a solid being merged.
+type solidEdge struct {
+	from, to solidVertex
+	solid    int
+	removed  bool
+}
+
+// mergeSolidHatches merges the single polygon hatches converted from JWW
+// solids that share a layer and color and touch along whole edges. Each
+// connected region becomes one HATCH, placed where its first solid was, whose
+// boundaries are the outline and holes of the region. Edges shared by two
+// solids are dropped; solids that only touch at a vertex or along part of an
+// edge stay separate.
+func mergeSolidHatches(entities []Entity, solids []*Hatch) []Entity {
+	type style struct {
+		layer     string
+		color     int
+		trueColor int
+		colorName string
+	}
+
+	// Group the solids by style, in order of appearance
+	var styles []style
+	groups := make(map[style][]*Hatch)
+	for _, h := range solids {
+		s := style{layer: h.Layer, color: h.Color, trueColor: -1, colorName: h.ColorName}
+		if h.TrueColor != nil {
+			s.trueColor = *h.TrueColor
+		}
+		if _, ok := groups[s]; !ok {
+			styles = append(styles, s)
+		}
+		groups[s] = append(groups[s], h)
+	}
+
+	replaced := make(map[*Hatch]*Hatch)
+	for _, s := range styles {
+		for first, merged := range mergeSolidGroup(groups[s]) {
+			replaced[first] = merged
+		}
+	}
+
+	result := entities[:0]
+	for _, e := range entities {
+		h, ok := e.(*Hatch)
+		if !ok {
+			result = append(result, e)
+			continue
+		}
+		if merged, ok := replaced[h]; ok {
+			if merged != nil {
+				result = append(result, merged)
+			}
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// mergeSolidGroup merges solids of the same style. It maps the hatches of
+// merged solids to their replacement: the merged hatch for the first solid of
+// each region and nil for the others. Solids without area are not mapped.
+func mergeSolidGroup(solids []*Hatch) map[*Hatch]*Hatch {
+	points := make(map[solidVertex]HatchVertex)
+	key := func(v HatchVertex) solidVertex {
+		k := solidVertex{
+			x: int64(math.Round(v.X / solidMergeTolerance)),
+			y: int64(math.Round(v.Y / solidMergeTolerance)),
+		}
+		if _, ok := points[k]; !ok {
+			points[k] = HatchVertex{X: v.X, Y: v.Y}
+		}
+		return k
+	}
+
+	parent := make([]int, len(solids))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// Add the counterclockwise edges of every solid, cancelling edges run in
+	// the opposite direction by an adjacent solid
+	var edges []solidEdge
+	open := make(map[[2]solidVertex][]int)
+	skipped := make(map[int]bool)
+	for i, h := range solids {
+		vertices := h.Boundaries[0].Vertices
+		area := polygonArea(vertices)
+		if area == 0 {
+			// Degenerate solids are kept as they are
+			skipped[i] = true
+			continue
+		}
+		if area < 0 {
+			reversed := make([]HatchVertex, len(vertices))
+			for j, v := range vertices {
+				reversed[len(vertices)-1-j] = v
+			}
+			vertices = reversed
+		}
+		for j := range vertices {
+			from, to := key(vertices[j]), key(vertices[(j+1)%len(vertices)])
+			if from == to {
+				continue
+			}
+			reverse := [2]solidVertex{to, from}
+			if n := len(open[reverse]); n > 0 {
+				k := open[reverse][n-1]
+				open[reverse] = open[reverse][:n-1]
+				edges[k].removed = true
+				parent[find(i)] = find(edges[k].solid)
+				continue
+			}
+			open[[2]solidVertex{from, to}] = append(open[[2]solidVertex{from, to}], len(edges))
+			edges = append(edges, solidEdge{from: from, to: to, solid: i})
+		}
+	}
+
+	// Collect the remaining edges of every region
+	regions := make(map[int][]int)
+	for k := range edges {
+		if !edges[k].removed {
+			root := find(edges[k].solid)
+			regions[root] = append(regions[root], k)
+		}
+	}
+
+	replaced := make(map[*Hatch]*Hatch, len(solids))
+	done := make(map[int]bool)
+	for i, h := range solids {
+		if skipped[i] {
+			continue
+		}
+		root := find(i)
+		if done[root] {
+			replaced[h] = nil
+			continue
+		}
+		done[root] = true
+		merged := *h
+		merged.Boundaries = chainSolidEdges(edges, regions[root], points)
+		replaced[h] = &merged
+	}
+	return replaced
+}
+
+// chainSolidEdges joins the directed edges of a region into closed polygon
+// boundaries, dropping vertices in the middle of straight runs.
+func chainSolidEdges(edges []solidEdge, region []int, points map[solidVertex]HatchVertex) []HatchBoundary {
+	outgoing := make(map[solidVertex][]int)
+	for _, k := range region {
+		outgoing[edges[k].from] = append(outgoing[edges[k].from], k)
+	}
+
+	used := make(map[int]bool, len(region))
+	var boundaries []HatchBoundary
+	for _, k := range region {
+		if used[k] {
+			continue
+		}
+		var loop []HatchVertex
+		start := edges[k].from
+		for {
+			used[k] = true
+			loop = append(loop, points[edges[k].from])
+			next := edges[k].to
+			if next == start {
+				break
+			}
+			k = -1
+			for _, n := range outgoing[next] {
+				if !used[n] {
+					k = n
+					break
+				}
+			}
+			if k < 0 {
+				break
+			}
+		}
+		if loop = dropCollinearVertices(loop); len(loop) >= 3 {
+			// Outlines run counterclockwise and holes clockwise
+			boundaries = append(boundaries, HatchBoundary{Vertices: loop, Hole: polygonArea(loop) < 0})
+		}
+	}
+	return boundaries
+}
+
+// dropCollinearVertices removes the vertices of a closed polygon that lie on
+// the straight line between their neighbours.
+func dropCollinearVertices(loop []HatchVertex) []HatchVertex {
+	for changed := true; changed && len(loop) > 3; {
+		changed = false
+		for i := 0; i < len(loop) && len(loop) > 3; i++ {
+			prev, v, next := loop[(i+len(loop)-1)%len(loop)], loop[i], loop[(i+1)%len(loop)]
+			ax, ay := v.X-prev.X, v.Y-prev.Y
+			bx, by := next.X-v.X, next.Y-v.Y
+			if math.Abs(ax*by-ay*bx) <= solidMergeTolerance*math.Hypot(ax, ay)*math.Hypot(bx, by) && ax*bx+ay*by > 0 {
+				loop = append(loop[:i], loop[i+1:]...)
+				changed = true
+				i--
+			}
+		}
+	}
+	return loop
+}
+
+// polygonArea returns the signed area of a polygon, positive when its
+// vertices run counterclockwise.
+func polygonArea(vertices []HatchVertex) float64 {
+	var sum float64
+	for i, v := range vertices {
+		next := vertices[(i+1)%len(vertices)]
+		sum += v.X*next.Y - next.X*v.Y
+	}
+	return sum / 2
+}
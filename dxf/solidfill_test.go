@@ -0,0 +1,187 @@
+package dxf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// testQuad returns a JWW solid covering the rectangle (x0, y0)-(x1, y1),
+// stored in the DXF SOLID vertex order.
+func testQuad(x0, y0, x1, y1 float64, penColor uint16) *jww.Solid {
+	return &jww.Solid{
+		EntityBase: jww.EntityBase{PenColor: penColor},
+		Point1X:    x0, Point1Y: y0,
+		Point2X: x1, Point2Y: y0,
+		Point3X: x0, Point3Y: y1,
+		Point4X: x1, Point4Y: y1,
+	}
+}
+
+func TestConvertSolidHatch(t *testing.T) {
+	doc := createTestDocument()
+	quad := testQuad(0, 0, 10, 5, 10)
+	quad.Color = 0x000000FF // COLORREF red
+	doc.Entities = []jww.Entity{quad}
+
+	result := ConvertDocument(doc)
+
+	hatch, ok := result.Entities[0].(*Hatch)
+	if !ok {
+		t.Fatalf("expected *Hatch, got %T", result.Entities[0])
+	}
+	if hatch.TrueColor == nil || *hatch.TrueColor != 0xFF0000 || hatch.Color != 1 {
+		t.Errorf("color: got %d/%v, want 1/0xFF0000", hatch.Color, hatch.TrueColor)
+	}
+	want := []HatchVertex{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}, {X: 0, Y: 5}}
+	if got := hatch.Boundaries[0].Vertices; len(got) != 4 || got[2] != want[2] || got[3] != want[3] {
+		t.Errorf("vertices: got %v, want %v", got, want)
+	}
+
+	// Triangles repeat their last point
+	triangle := testQuad(0, 0, 10, 5, 1)
+	triangle.Point4X, triangle.Point4Y = triangle.Point3X, triangle.Point3Y
+	if got := convertSolidHatch(triangle, doc, newConvertConfig(nil)).Boundaries[0].Vertices; len(got) != 3 {
+		t.Errorf("triangle: got %d vertices, want 3", len(got))
+	}
+}
+
+func TestConvertDocument_DegenerateSolids(t *testing.T) {
+	doc := createTestDocument()
+	point := testQuad(5, 5, 5, 5, 1)
+	line := &jww.Solid{
+		EntityBase: jww.EntityBase{PenColor: 1},
+		Point2X:    1, Point2Y: 1,
+		Point3X: 2, Point3Y: 2,
+		Point4X: 3, Point4Y: 3,
+	}
+	doc.Entities = []jww.Entity{point, line, testQuad(0, 0, 10, 10, 1)}
+
+	result := ConvertDocument(doc)
+
+	// Solids enclosing no area are kept as SOLID entities
+	want := []string{"SOLID", "SOLID", "HATCH"}
+	if len(result.Entities) != len(want) {
+		t.Fatalf("expected %d entities, got %d", len(want), len(result.Entities))
+	}
+	for i, e := range result.Entities {
+		if e.EntityType() != want[i] {
+			t.Errorf("entity %d: got %s, want %s", i, e.EntityType(), want[i])
+		}
+	}
+}
+
+func TestConvertCircleSolidHatch_Ellipse(t *testing.T) {
+	doc := createTestDocument()
+	// Quarter of an ellipse taller than wide, tilted by 30°
+	cs := &jww.CircleSolid{
+		EntityBase: jww.EntityBase{PenStyle: 101},
+		CenterX:    5, CenterY: 5,
+		Radius: 10, Flatness: 2, TiltAngle: math.Pi / 6,
+		StartAngle: math.Pi / 4, ArcAngle: math.Pi / 2,
+	}
+
+	hatch := convertCircleSolidHatch(cs, doc, newConvertConfig(nil))
+	if hatch == nil {
+		t.Fatal("expected a hatch")
+	}
+	edges := hatch.Boundaries[0].Edges
+	if len(edges) != 3 || edges[0].Type != HatchEdgeEllipse {
+		t.Fatalf("expected an ellipse edge and two lines, got %+v", edges)
+	}
+	if e := edges[0]; math.Abs(math.Hypot(e.MajorAxisX, e.MajorAxisY)-20) > 1e-9 || e.MinorRatio != 0.5 {
+		t.Errorf("axes: got major (%v, %v) ratio %v", e.MajorAxisX, e.MajorAxisY, e.MinorRatio)
+	}
+
+	// The curve must end where the closing line starts
+	pts := edges[0].curvePoints()
+	sx, sy := edges[2].X2, edges[2].Y2
+	if math.Hypot(pts[0][0]-sx, pts[0][1]-sy) > 1e-9 {
+		t.Errorf("curve starts at %v, want (%v, %v)", pts[0], sx, sy)
+	}
+	if area, want := hatch.Area(), math.Pi*10*20/4; math.Abs(area-want)/want > 0.01 {
+		t.Errorf("area: got %v, want %v", area, want)
+	}
+}
+
+func TestConvertDocument_MergedSolids(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		testQuad(0, 0, 10, 10, 1),
+		&jww.Line{EndX: 1},
+		testQuad(10, 0, 20, 10, 1),
+		testQuad(20, 0, 30, 10, 2),  // Different color
+		testQuad(40, 40, 50, 50, 1), // Not adjacent
+	}
+
+	result := ConvertDocument(doc, WithSolidFill(SolidFillMerged))
+
+	if len(result.Entities) != 4 {
+		t.Fatalf("expected 4 entities, got %d", len(result.Entities))
+	}
+	merged, ok := result.Entities[0].(*Hatch)
+	if !ok {
+		t.Fatalf("expected the merged hatch first, got %T", result.Entities[0])
+	}
+	if _, ok := result.Entities[1].(*Line); !ok {
+		t.Errorf("expected the line second, got %T", result.Entities[1])
+	}
+	if len(merged.Boundaries) != 1 || len(merged.Boundaries[0].Vertices) != 4 {
+		t.Errorf("expected a single rectangle, got %+v", merged.Boundaries)
+	}
+	if area := merged.Area(); area != 200 {
+		t.Errorf("area: got %v, want 200", area)
+	}
+
+	// The default mode keeps one hatch per solid
+	if result := ConvertDocument(doc); len(result.Entities) != 5 {
+		t.Errorf("expected 5 entities without merging, got %d", len(result.Entities))
+	}
+}
+
+func TestConvertDocument_MergedSolidsWithHole(t *testing.T) {
+	doc := createTestDocument()
+	for y := 0.0; y < 30; y += 10 {
+		for x := 0.0; x < 30; x += 10 {
+			if x == 10 && y == 10 {
+				continue
+			}
+			// Alternate the vertex order, as drawn by hand
+			quad := testQuad(x, y, x+10, y+10, 1)
+			if int(x+y)%20 == 0 {
+				quad = testQuad(x, y+10, x+10, y, 1)
+			}
+			doc.Entities = append(doc.Entities, quad)
+		}
+	}
+
+	result := ConvertDocument(doc, WithSolidFill(SolidFillMerged))
+
+	if len(result.Entities) != 1 {
+		t.Fatalf("expected 1 hatch, got %d entities", len(result.Entities))
+	}
+	hatch := result.Entities[0].(*Hatch)
+	if len(hatch.Boundaries) != 2 {
+		t.Fatalf("expected outline and hole, got %d boundaries", len(hatch.Boundaries))
+	}
+	for i, b := range hatch.Boundaries {
+		if len(b.Vertices) != 4 {
+			t.Errorf("boundary %d: got %d vertices, want 4", i, len(b.Vertices))
+		}
+	}
+	if area := hatch.Area(); area != 800 {
+		t.Errorf("area: got %v, want 800", area)
+	}
+
+	// Only the outline is an external boundary path
+	var flags []int
+	for _, gc := range hatch.GroupCodes() {
+		if gc.Code == 92 {
+			flags = append(flags, gc.Value.(int))
+		}
+	}
+	if len(flags) != 2 || flags[0] != 1|2 || flags[1] != 2 {
+		t.Errorf("boundary path flags: got %v, want [3 2]", flags)
+	}
+}
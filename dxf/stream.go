@@ -0,0 +1,234 @@
+package dxf
+
+import (
+	"bufio"
+	"io"
+	"iter"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// ConvertStream converts the JWW file read by dec and writes it to w as a
+// DXF file, holding neither the JWW entities nor the converted DXF entities
+// in memory: only the header, the block definitions and the bundled images
+// are kept. Use it for drawings too large for ConvertDocument.
+//
+// The DXF tables come before the entities, so the entity list is read
+// several times: once for the line types, text styles and layers the
+// entities use, once for the anonymous blocks of the dimensions when there
+// are any, and once to write the entities.
+//
+// The output is the same as that of ConvertDocument with these exceptions,
+// as they need the whole entity list at once: hatching outside blocks is
+// written as the lines drawn (see WithExplodedHatching), SolidFillMerged does
+// not merge solids outside blocks, and image handles are numbered
+// differently.
+//
+// Example:
+//
+//	f, _ := os.Open("survey.jww")
+//	defer f.Close()
+//	st, _ := f.Stat()
+//
+//	out, _ := os.Create("survey.dxf")
+//	defer out.Close()
+//
+//	if err := dxf.ConvertStream(out, jww.NewDecoder(f, st.Size())); err != nil {
+//		return err
+//	}
+func ConvertStream(w io.Writer, dec *jww.Decoder, opts ...ConvertOption) error {
+	return convertStream(w, dec, opts)
+}
+
+// streamSource is the part of jww.Decoder read by ConvertStream.
+type streamSource interface {
+	Header() (*jww.Document, error)
+	Entities() iter.Seq2[jww.Entity, error]
+	BlockDefs() ([]jww.BlockDef, error)
+	Images() ([]jww.Image, error)
+}
+
+// convertStream implements ConvertStream for any source.
+func convertStream(w io.Writer, dec streamSource, opts []ConvertOption) error {
+	cfg := newConvertConfig(opts)
+
+	header, err := dec.Header()
+	if err != nil {
+		return err
+	}
+	doc := *header
+
+	// First pass: collect what the tables need. Block names are not known
+	// yet, which only affects the INSERT entities discarded here.
+	lineTypes := newLineTypeSet(&doc)
+	textStyles := newTextStyleSet(&doc, cfg)
+	weights := make(lineWeightCounts)
+	var imageRefs []jww.Entity
+	seenImages := make(map[string]bool)
+	dimensions := false
+	entities := 0
+	cfg.report()
+	for e, err := range dec.Entities() {
+		if err != nil {
+			return err
+		}
+		cfg.count()
+		list := []jww.Entity{e}
+		lineTypes.visit(list)
+		textStyles.visit(list)
+		if ref, ok := e.(*jww.ImageRef); ok && !seenImages[ref.FileName()] {
+			seenImages[ref.FileName()] = true
+			imageRefs = append(imageRefs, ref)
+		}
+
+		converted := convertStreamEntity(e, &doc, cfg)
+		entities += len(converted)
+		weights.add(converted)
+		for _, c := range converted {
+			if dim, ok := c.(*Dimension); ok && len(dim.Geometry) > 0 {
+				dimensions = true
+			}
+		}
+	}
+
+	if doc.BlockDefs, err = dec.BlockDefs(); err != nil {
+		return err
+	}
+	cfg.blocks = newBlockNames(&doc)
+	if doc.Images, err = dec.Images(); err != nil {
+		return err
+	}
+	for _, bd := range doc.BlockDefs {
+		lineTypes.visit(bd.Entities)
+		textStyles.visit(bd.Entities)
+	}
+
+	// Image definitions for the first reference to each file, then those
+	// of the blocks
+	images := doc
+	images.Entities = imageRefs
+
+	dxfDoc := &Document{
+		Layers:     convertLayers(&doc, cfg),
+		LineTypes:  lineTypes.lineTypes,
+		TextStyles: textStyles.styles,
+		DimStyles:  convertDimStyles(&doc),
+		Blocks:     convertBlocks(&doc, cfg),
+		ImageDefs:  convertImageDefs(&images),
+	}
+	if cfg.attributeLayers != nil {
+		dxfDoc.Layers = cfg.attributeLayers.addLayers(dxfDoc.Layers)
+	}
+	weights.assign(dxfDoc.Layers)
+	cfg.report()
+
+	bw := bufio.NewWriter(w)
+	sw := NewWriter(bw)
+	sw.SetProgress(cfg.progress)
+	sw.totalEntities = entities
+	if err := sw.writeStream(dxfDoc, dimensions, func(fn func([]Entity) error) error {
+		for e, err := range dec.Entities() {
+			if err != nil {
+				return err
+			}
+			if err := fn(convertStreamEntity(e, &doc, cfg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// convertStreamEntity converts an entity of the entity list read by
+// ConvertStream, moving it to its attribute sub-layer with
+// WithAttributeLayers.
+func convertStreamEntity(e jww.Entity, doc *jww.Document, cfg *convertConfig) []Entity {
+	converted := convertListEntity(e, doc, cfg)
+	if cfg.attributeLayers != nil {
+		cfg.attributeLayers.assign(converted, jww.DecodeAttributes(e))
+	}
+	return converted
+}
+
+// writeStream writes doc like WriteDocument, with the entities produced by
+// each call of entities instead of doc.Entities. entities is called once
+// more to write the anonymous blocks of the dimensions when dimensions is
+// true, and must produce the same entities each time.
+func (w *Writer) writeStream(doc *Document, dimensions bool, entities func(func([]Entity) error) error) error {
+	w.report()
+	w.assignImageHandles(doc)
+	names := w.assignDimensionBlocks(doc)
+
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	if len(doc.ImageDefs) > 0 {
+		if err := w.writeClasses(); err != nil {
+			return err
+		}
+	}
+	if err := w.writeTables(doc); err != nil {
+		return err
+	}
+
+	// BLOCKS section, followed by the dimension blocks of the entities
+	if err := w.writeSection("BLOCKS"); err != nil {
+		return err
+	}
+	for _, block := range append(doc.Blocks[:len(doc.Blocks):len(doc.Blocks)], w.dimBlocks...) {
+		if err := w.writeBlock(block); err != nil {
+			return err
+		}
+	}
+	if dimensions {
+		blockNames := names
+		if err := entities(func(converted []Entity) error {
+			for _, block := range blockNames.assign(nil, converted) {
+				if err := w.writeBlock(block); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	if err := w.writeEndSection(); err != nil {
+		return err
+	}
+
+	// ENTITIES section
+	if err := w.writeSection("ENTITIES"); err != nil {
+		return err
+	}
+	if err := entities(func(converted []Entity) error {
+		names.assign(nil, converted)
+		w.linkImages(converted)
+		for _, entity := range converted {
+			if err := w.writeEntity(entity); err != nil {
+				return err
+			}
+			w.countEntity()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := w.writeEndSection(); err != nil {
+		return err
+	}
+
+	if len(w.images) > 0 {
+		if err := w.writeObjects(doc); err != nil {
+			return err
+		}
+	}
+	if err := w.writeGroupCode(0, "EOF"); err != nil {
+		return err
+	}
+	w.report()
+	return nil
+}
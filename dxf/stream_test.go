@@ -0,0 +1,163 @@
+package dxf
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// docSource serves a parsed document to convertStream like a jww.Decoder.
+type docSource struct {
+	doc *jww.Document
+	err error // Returned after the entities
+}
+
+func (s docSource) Header() (*jww.Document, error) {
+	header := *s.doc
+	header.Entities, header.BlockDefs, header.Images = nil, nil, nil
+	return &header, nil
+}
+
+func (s docSource) Entities() iter.Seq2[jww.Entity, error] {
+	return func(yield func(jww.Entity, error) bool) {
+		for _, e := range s.doc.Entities {
+			if !yield(e, nil) {
+				return
+			}
+		}
+		if s.err != nil {
+			yield(nil, s.err)
+		}
+	}
+}
+
+func (s docSource) BlockDefs() ([]jww.BlockDef, error) { return s.doc.BlockDefs, nil }
+func (s docSource) Images() ([]jww.Image, error)       { return s.doc.Images, nil }
+
+// streamTestDocument returns a document with entities of several layers,
+// line types and text styles, and dimensions inside and outside a block.
+func streamTestDocument() *jww.Document {
+	doc := createTestDocument()
+	doc.Header.DimensionSettings = [5]uint32{1000000, 0, 0, 0, 0}
+	doc.Header.Pens[2].PrinterWidth = 6
+	doc.LayerGroups[0].Layers[1].Name = "壁"
+
+	dim := &jww.Dimension{
+		Line: jww.Line{StartX: 0, EndX: 100},
+		Text: jww.Text{StartX: 45, EndX: 55, Content: "100"},
+	}
+	thick := &jww.Line{EndX: 10}
+	thick.PenColor = 2
+	dashed := &jww.Line{EndY: 10}
+	dashed.PenStyle = 2
+	dashed.Layer = 1
+	hatch := &jww.Line{EndX: 5, EndY: 5}
+	hatch.Flag = 0x0080 // Attribute flag
+	text := &jww.Text{StartX: 5, Content: "テキスト", SizeX: 3, SizeY: 3, FontName: "ＭＳ ゴシック"}
+	text.Layer = 1
+
+	doc.Entities = []jww.Entity{thick, dashed, dim, text, hatch, &jww.Block{DefNumber: 1}, dim}
+	doc.BlockDefs = []jww.BlockDef{{Number: 1, Name: "B", Entities: []jww.Entity{dim, &jww.Text{Content: "B", FontName: "ＭＳ 明朝"}}}}
+	return doc
+}
+
+func TestConvertStream_MatchesConvertDocument(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []ConvertOption
+	}{
+		{"default", nil},
+		{"attribute layers", []ConvertOption{WithAttributeLayers()}},
+		{"exploded dimensions", []ConvertOption{WithExplodedDimensions(), WithExplodedHatching()}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			want := ToString(ConvertDocument(streamTestDocument(), tt.opts...))
+
+			var sb strings.Builder
+			if err := convertStream(&sb, docSource{doc: streamTestDocument()}, tt.opts); err != nil {
+				t.Fatalf("convertStream failed: %v", err)
+			}
+			if got := sb.String(); got != want {
+				t.Errorf("output differs from ConvertDocument:\n%s", firstDifference(got, want))
+			}
+		})
+	}
+}
+
+// firstDifference shows the lines around the first difference of got and
+// want.
+func firstDifference(got, want string) string {
+	g, w := strings.Split(got, "\n"), strings.Split(want, "\n")
+	for i := 0; i < len(g) && i < len(w); i++ {
+		if g[i] != w[i] {
+			from := max(i-6, 0)
+			return "got:\n" + strings.Join(g[from:min(i+6, len(g))], "\n") +
+				"\nwant:\n" + strings.Join(w[from:min(i+6, len(w))], "\n")
+		}
+	}
+	return "lengths differ"
+}
+
+func TestConvertStream_Images(t *testing.T) {
+	doc := createTestDocument()
+	doc.Images = []jww.Image{{Name: "photo.png", Data: testPNG(t, 20, 10)}}
+	ref := &jww.ImageRef{Path: "%temp%photo.png", Width: 40, Height: 20, TrimWidth: 1, TrimHeight: 1}
+	doc.Entities = []jww.Entity{ref, ref}
+
+	var sb strings.Builder
+	if err := convertStream(&sb, docSource{doc: doc}, nil); err != nil {
+		t.Fatalf("convertStream failed: %v", err)
+	}
+	out := sb.String()
+
+	if n := strings.Count(out, "  0\nIMAGEDEF\n"); n != 1 {
+		t.Errorf("expected 1 IMAGEDEF, got %d", n)
+	}
+	if n := strings.Count(out, "  0\nIMAGEDEF_REACTOR\n"); n != 2 {
+		t.Errorf("expected 2 IMAGEDEF_REACTOR objects, got %d", n)
+	}
+	if i, j := strings.Index(out, "CLASSES"), strings.Index(out, "TABLES"); i < 0 || i > j {
+		t.Error("expected the CLASSES section before the tables")
+	}
+}
+
+func TestConvertStream_Error(t *testing.T) {
+	errBroken := errors.New("broken entity")
+	doc := streamTestDocument()
+
+	var sb strings.Builder
+	err := convertStream(&sb, docSource{doc: doc, err: errBroken}, nil)
+	if !errors.Is(err, errBroken) {
+		t.Errorf("expected the decoder error, got %v", err)
+	}
+}
+
+func TestConvertStream_Progress(t *testing.T) {
+	var reports []jww.Progress
+	var sb strings.Builder
+	err := convertStream(&sb, docSource{doc: streamTestDocument()}, []ConvertOption{
+		WithProgress(func(p jww.Progress) { reports = append(reports, p) }),
+	})
+	if err != nil {
+		t.Fatalf("convertStream failed: %v", err)
+	}
+
+	// Converting, then writing, ending with the whole file
+	var stages []jww.ProgressStage
+	for _, p := range reports {
+		if len(stages) == 0 || stages[len(stages)-1] != p.Stage {
+			stages = append(stages, p.Stage)
+		}
+	}
+	if want := []jww.ProgressStage{jww.StageConverting, jww.StageWriting}; !slices.Equal(stages, want) {
+		t.Errorf("stages: got %v, want %v", stages, want)
+	}
+	last := reports[len(reports)-1]
+	if last.Bytes != int64(sb.Len()) || last.Entities != last.TotalEntities || last.Entities == 0 {
+		t.Errorf("last report: got %+v, want %d bytes", last, sb.Len())
+	}
+}
@@ -0,0 +1,81 @@
+package dxf
+
+import (
+	"math"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// textWidthFactor returns the width factor that makes a JWW text fill its
+// laid-out extent (StartX/Y to EndX/Y), which already includes the character
+// spacing. Without an extent the width is computed from the character width
+// SizeX and the spacing between characters.
+func textWidthFactor(t *jww.Text, height float64, style textStyleKey) float64 {
+	ems := TextEms(t.Content)
+	if ems == 0 || height <= 0 {
+		return style.widthFactor
+	}
+
+	length := math.Hypot(t.EndX-t.StartX, t.EndY-t.StartY)
+	if length <= 0 {
+		n := float64(len([]rune(t.Content)))
+		length = ems*height*style.widthFactor + (n-1)*t.Spacing
+	}
+	return math.Round(length/(ems*height)*10000) / 10000
+}
+
+// convertVerticalText lays out a vertical JWW text (縦字) as one upright TEXT
+// per character. Characters run from the start point downwards, that is at
+// the text angle minus 90°, and sit to the right of that line. Their pitch is
+// taken from the laid-out extent, or from the height and spacing without one.
+func convertVerticalText(t *jww.Text, doc *jww.Document, cfg *convertConfig) []Entity {
+	runes := []rune(t.Content)
+	if len(runes) == 0 {
+		return nil
+	}
+
+	base := t.Base()
+	layerName := cfg.layerName(doc, base.LayerGroup, base.Layer)
+	color, trueColor, colorName := entityColor(doc, base.PenColor)
+	lineType := lineTypeName(doc, base.PenStyle, base.LayerGroup)
+	style, height := jwwTextStyle(t, doc)
+
+	n := float64(len(runes))
+	pitch := height + t.Spacing
+	if length := math.Hypot(t.EndX-t.StartX, t.EndY-t.StartY); length > 0 {
+		pitch = (length + t.Spacing) / n
+	}
+
+	sin, cos := math.Sincos(t.Angle * math.Pi / 180)
+	// Down the column and across it, in drawing coordinates
+	downX, downY := sin, -cos
+	acrossX, acrossY := cos, sin
+	cellWidth := height * style.widthFactor
+
+	entities := make([]Entity, 0, len(runes))
+	for i, r := range runes {
+		if r == ' ' || r == '　' {
+			continue
+		}
+		ch := string(r)
+		down := float64(i)*pitch + height
+		across := (cellWidth - TextEms(ch)*cellWidth) / 2 // Center half-width characters
+
+		entities = append(entities, &Text{
+			Layer:        layerName,
+			Color:        color,
+			TrueColor:    trueColor,
+			ColorName:    colorName,
+			LineType:     lineType,
+			X:            t.StartX + down*downX + across*acrossX,
+			Y:            t.StartY + down*downY + across*acrossY,
+			Height:       height,
+			Rotation:     t.Angle,
+			Content:      ch,
+			Style:        style.name(),
+			WidthFactor:  style.widthFactor,
+			ObliqueAngle: style.obliqueAngle(),
+		})
+	}
+	return entities
+}
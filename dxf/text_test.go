@@ -0,0 +1,87 @@
+package dxf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+func TestConvertText_FitsExtent(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		// 4 full-width characters, 5 high, laid out over 30 units
+		&jww.Text{StartX: 0, StartY: 0, EndX: 30, EndY: 0, SizeX: 5, SizeY: 5, Spacing: 2, Content: "平面図面"},
+		// No extent: 2 half-width characters 4 wide plus one 1 unit gap
+		&jww.Text{SizeX: 4, SizeY: 5, Spacing: 1, Content: "AB"},
+	}
+
+	result := ConvertDocument(doc)
+
+	fitted := result.Entities[0].(*Text)
+	if math.Abs(fitted.WidthFactor-1.5) > 1e-9 {
+		t.Errorf("fitted width factor: got %v, want 1.5", fitted.WidthFactor)
+	}
+	if math.Abs(fitted.Width()-30) > 1e-9 {
+		t.Errorf("fitted width: got %v, want 30", fitted.Width())
+	}
+	// The style keeps the character proportions, the entity the layout
+	if fitted.Style != "STANDARD" {
+		t.Errorf("style: got %q, want STANDARD", fitted.Style)
+	}
+
+	spaced := result.Entities[1].(*Text)
+	if math.Abs(spaced.Width()-5) > 1e-9 {
+		t.Errorf("spaced width: got %v, want 5", spaced.Width())
+	}
+}
+
+func TestConvertVerticalText(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.Text{
+		EntityBase: jww.EntityBase{Flag: 0x0020},
+		StartX:     10, StartY: 100,
+		EndX: 10, EndY: 78, // 3 characters at a pitch of 8 minus the last spacing
+		SizeX: 6, SizeY: 6, Spacing: 2,
+		Content: "縦書A",
+	}}
+
+	result := ConvertDocument(doc)
+
+	if len(result.Entities) != 3 {
+		t.Fatalf("expected 3 entities, got %d", len(result.Entities))
+	}
+	want := []struct {
+		x, y    float64
+		content string
+	}{
+		{10, 94, "縦"},
+		{10, 86, "書"},
+		{11.5, 78, "A"}, // Half-width characters are centered in the column
+	}
+	for i, w := range want {
+		txt := result.Entities[i].(*Text)
+		if txt.Content != w.content || math.Abs(txt.X-w.x) > 1e-9 || math.Abs(txt.Y-w.y) > 1e-9 {
+			t.Errorf("character %d: got %q at (%v, %v), want %q at (%v, %v)", i, txt.Content, txt.X, txt.Y, w.content, w.x, w.y)
+		}
+		if txt.Rotation != 0 || txt.Height != 6 {
+			t.Errorf("character %d: got rotation %v height %v", i, txt.Rotation, txt.Height)
+		}
+	}
+}
+
+func TestConvertVerticalText_Rotated(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.Text{
+		EntityBase: jww.EntityBase{Flag: 0x0020},
+		SizeY:      5, Angle: 90,
+		Content: "縦",
+	}}
+
+	txt := ConvertDocument(doc).Entities[0].(*Text)
+
+	// Rotated 90°: the column runs along +X
+	if math.Abs(txt.X-5) > 1e-9 || math.Abs(txt.Y) > 1e-9 || txt.Rotation != 90 {
+		t.Errorf("got (%v, %v) rotation %v, want (5, 0) rotation 90", txt.X, txt.Y, txt.Rotation)
+	}
+}
@@ -0,0 +1,176 @@
+package dxf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// DefaultFontMap maps common Jw_cad font names to TrueType font files.
+// Fonts that are not listed use their name with a ".ttf" extension.
+var DefaultFontMap = map[string]string{
+	"ＭＳ ゴシック":      "msgothic.ttc",
+	"ＭＳ Ｐゴシック":     "msgothic.ttc",
+	"MS Gothic":    "msgothic.ttc",
+	"MS UI Gothic": "msgothic.ttc",
+	"ＭＳ 明朝":        "msmincho.ttc",
+	"ＭＳ Ｐ明朝":       "msmincho.ttc",
+	"MS Mincho":    "msmincho.ttc",
+	"メイリオ":         "meiryo.ttc",
+	"游ゴシック":        "YuGothM.ttc",
+	"游明朝":          "yumin.ttf",
+}
+
+// italicObliqueAngle is the slant in degrees used for JWW italic text.
+const italicObliqueAngle = 15.0
+
+// textStyleKey identifies the DXF text style of a JWW text.
+type textStyleKey struct {
+	font        string
+	widthFactor float64
+	italic      bool
+	bold        bool
+}
+
+// jwwTextStyle returns the style key of a JWW text and its height.
+// Texts without a size use the size of their text type preset (文字種1～10),
+// or a 2.5 unit height.
+func jwwTextStyle(t *jww.Text, doc *jww.Document) (textStyleKey, float64) {
+	width, height := t.SizeX, t.SizeY
+	if n := t.TextTypeNumber(); n >= 1 && n <= 10 {
+		preset := doc.Header.TextStyles[n-1]
+		if height <= 0 {
+			height = preset.Height
+		}
+		if width <= 0 {
+			width = preset.Width
+		}
+	}
+	if height <= 0 {
+		height = 2.5 // Default text height (same as NewText builder)
+	}
+
+	widthFactor := 1.0
+	if width > 0 {
+		widthFactor = math.Round(width/height*1000) / 1000
+	}
+
+	return textStyleKey{
+		font:        strings.TrimSpace(t.FontName),
+		widthFactor: widthFactor,
+		italic:      t.IsItalic(),
+		bold:        t.IsBold(),
+	}, height
+}
+
+// name returns the DXF style name: the font name (or STANDARD) followed by
+// "_W<factor>" for non-default widths, "_I" for italic and "_B" for bold.
+func (k textStyleKey) name() string {
+	name := "STANDARD"
+	if k.font != "" {
+		name = sanitizeSymbolName(k.font)
+	}
+	if k.widthFactor != 1 {
+		name += fmt.Sprintf("_W%g", k.widthFactor)
+	}
+	if k.italic {
+		name += "_I"
+	}
+	if k.bold {
+		name += "_B"
+	}
+	return name
+}
+
+// obliqueAngle returns the character slant of the style.
+func (k textStyleKey) obliqueAngle() float64 {
+	if k.italic {
+		return italicObliqueAngle
+	}
+	return 0
+}
+
+// convertTextStyles creates a text style for every distinct font, width
+// factor and italic/bold combination used by the texts of the drawing, its
+// dimensions and its blocks.
+func convertTextStyles(doc *jww.Document, cfg *convertConfig) []TextStyle {
+	s := newTextStyleSet(doc, cfg)
+	s.visit(doc.Entities)
+	for _, bd := range doc.BlockDefs {
+		s.visit(bd.Entities)
+	}
+	return s.styles
+}
+
+// textStyleSet collects the text styles used by entities in order of first
+// use.
+type textStyleSet struct {
+	doc    *jww.Document
+	cfg    *convertConfig
+	styles []TextStyle
+	seen   map[string]bool
+}
+
+func newTextStyleSet(doc *jww.Document, cfg *convertConfig) *textStyleSet {
+	return &textStyleSet{doc: doc, cfg: cfg, seen: make(map[string]bool)}
+}
+
+// visit adds the text styles used by the texts and dimensions in entities.
+func (s *textStyleSet) visit(entities []jww.Entity) {
+	for _, e := range entities {
+		switch v := e.(type) {
+		case *jww.Text:
+			s.add(v)
+		case *jww.Dimension:
+			if v.Text.Content != "" {
+				s.add(&v.Text)
+			}
+		}
+	}
+}
+
+func (s *textStyleSet) add(t *jww.Text) {
+	key, _ := jwwTextStyle(t, s.doc)
+	name := key.name()
+	if s.seen[name] {
+		return
+	}
+	s.seen[name] = true
+	s.styles = append(s.styles, newTextStyle(name, key, s.cfg))
+}
+
+// newTextStyle builds the DXF text style for a style key, resolving the font
+// file through the configured font map.
+func newTextStyle(name string, key textStyleKey, cfg *convertConfig) TextStyle {
+	st := TextStyle{
+		Name:         name,
+		Font:         "txt",
+		WidthFactor:  key.widthFactor,
+		ObliqueAngle: key.obliqueAngle(),
+		Bold:         key.bold,
+		Italic:       key.italic,
+	}
+	if key.font == "" {
+		return st
+	}
+
+	file, ok := cfg.fontMap[key.font]
+	if !ok {
+		file, ok = DefaultFontMap[key.font]
+	}
+	if !ok {
+		file = key.font + ".ttf"
+	}
+
+	if font, bigFont, found := strings.Cut(file, ","); found {
+		st.Font, st.BigFont = strings.TrimSpace(font), strings.TrimSpace(bigFont)
+	} else {
+		st.Font = file
+	}
+	if !strings.HasSuffix(strings.ToLower(st.Font), ".shx") {
+		st.FontFamily = key.font
+	}
+	return st
+}
@@ -0,0 +1,89 @@
+package dxf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+func TestConvertTextStyles(t *testing.T) {
+	doc := createTestDocument()
+	doc.Header.TextStyles[2] = jww.TextStyle{Width: 3, Height: 4}
+	doc.Entities = []jww.Entity{
+		&jww.Text{FontName: "ＭＳ ゴシック", SizeX: 5, SizeY: 5, Content: "a"},
+		&jww.Text{FontName: "ＭＳ ゴシック", SizeX: 5, SizeY: 5, Content: "b"},
+		&jww.Text{FontName: "ＭＳ 明朝", SizeX: 2.5, SizeY: 5, TextType: 10001, Content: "c"},
+		&jww.Text{FontName: "Arial", TextType: 20003, Content: "d"},
+	}
+
+	result := ConvertDocument(doc)
+
+	if len(result.TextStyles) != 3 {
+		t.Fatalf("expected 3 text styles, got %d: %+v", len(result.TextStyles), result.TextStyles)
+	}
+
+	gothic := result.TextStyles[0]
+	if gothic.Name != "ＭＳ ゴシック" || gothic.Font != "msgothic.ttc" || gothic.FontFamily != "ＭＳ ゴシック" || gothic.WidthFactor != 1 {
+		t.Errorf("gothic style: got %+v", gothic)
+	}
+
+	mincho := result.TextStyles[1]
+	if mincho.Name != "ＭＳ 明朝_W0.5_I" || !mincho.Italic || mincho.ObliqueAngle != 15 || mincho.WidthFactor != 0.5 {
+		t.Errorf("italic mincho style: got %+v", mincho)
+	}
+
+	// Text type 3 without a size takes the preset size 3 x 4
+	arial := result.TextStyles[2]
+	if arial.Name != "Arial_W0.75_B" || !arial.Bold || arial.Font != "Arial.ttf" {
+		t.Errorf("bold arial style: got %+v", arial)
+	}
+	txt := result.Entities[3].(*Text)
+	if txt.Style != arial.Name || txt.Height != 4 || txt.WidthFactor != 0.75 {
+		t.Errorf("preset text: got style %q height %v width %v", txt.Style, txt.Height, txt.WidthFactor)
+	}
+	if c := result.Entities[2].(*Text); c.ObliqueAngle != 15 {
+		t.Errorf("italic text oblique angle: got %v, want 15", c.ObliqueAngle)
+	}
+}
+
+func TestConvertTextStyles_FontMap(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Text{FontName: "ＭＳ ゴシック", SizeY: 5, Content: "a"},
+		&jww.Text{SizeY: 5, Content: "b"},
+	}
+
+	result := ConvertDocument(doc, WithFontMap(map[string]string{
+		"ＭＳ ゴシック": "romans.shx, extfont2.shx",
+	}))
+
+	if len(result.TextStyles) != 2 {
+		t.Fatalf("expected 2 text styles, got %d", len(result.TextStyles))
+	}
+	st := result.TextStyles[0]
+	if st.Font != "romans.shx" || st.BigFont != "extfont2.shx" || st.FontFamily != "" {
+		t.Errorf("mapped SHX style: got %+v", st)
+	}
+	if st := result.TextStyles[1]; st.Name != "STANDARD" || st.Font != "txt" {
+		t.Errorf("unnamed font style: got %+v", st)
+	}
+}
+
+func TestWriteDocument_TextStyles(t *testing.T) {
+	doc := &Document{TextStyles: []TextStyle{
+		{Name: "STANDARD", Font: "ignored"},
+		{Name: "Gothic", Font: "msgothic.ttc", FontFamily: "MS Gothic", WidthFactor: 0.8, Bold: true},
+	}}
+
+	out := ToString(doc)
+
+	if strings.Contains(out, "ignored") {
+		t.Error("a style named STANDARD must not replace the built-in one")
+	}
+	for _, want := range []string{"  2\nGothic\n", " 41\n0.800000\n", "1000\nMS Gothic\n", "1071\n33554432\n", "  2\nAPPID\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
@@ -11,13 +11,16 @@ import "math"
 //	moved := line.Translate(50, 50) // Line from (50,50) to (150,150)
 func (l *Line) Translate(dx, dy float64) *Line {
 	return &Line{
-		Layer:    l.Layer,
-		Color:    l.Color,
-		X1:       l.X1 + dx,
-		Y1:       l.Y1 + dy,
-		X2:       l.X2 + dx,
-		Y2:       l.Y2 + dy,
-		LineType: l.LineType,
+		Layer:      l.Layer,
+		Color:      l.Color,
+		TrueColor:  l.TrueColor,
+		ColorName:  l.ColorName,
+		X1:         l.X1 + dx,
+		Y1:         l.Y1 + dy,
+		X2:         l.X2 + dx,
+		Y2:         l.Y2 + dy,
+		LineType:   l.LineType,
+		LineWeight: l.LineWeight,
 	}
 }
 
@@ -45,13 +48,16 @@ func (l *Line) Rotate(angleDeg, cx, cy float64) *Line {
 
 	// Translate back
 	return &Line{
-		Layer:    l.Layer,
-		Color:    l.Color,
-		X1:       rx1 + cx,
-		Y1:       ry1 + cy,
-		X2:       rx2 + cx,
-		Y2:       ry2 + cy,
-		LineType: l.LineType,
+		Layer:      l.Layer,
+		Color:      l.Color,
+		TrueColor:  l.TrueColor,
+		ColorName:  l.ColorName,
+		X1:         rx1 + cx,
+		Y1:         ry1 + cy,
+		X2:         rx2 + cx,
+		Y2:         ry2 + cy,
+		LineType:   l.LineType,
+		LineWeight: l.LineWeight,
 	}
 }
 
@@ -64,13 +70,16 @@ func (l *Line) Rotate(angleDeg, cx, cy float64) *Line {
 //	scaled := line.Scale(2.0, 0, 0) // Scale 2x from origin
 func (l *Line) Scale(factor, cx, cy float64) *Line {
 	return &Line{
-		Layer:    l.Layer,
-		Color:    l.Color,
-		X1:       cx + (l.X1-cx)*factor,
-		Y1:       cy + (l.Y1-cy)*factor,
-		X2:       cx + (l.X2-cx)*factor,
-		Y2:       cy + (l.Y2-cy)*factor,
-		LineType: l.LineType,
+		Layer:      l.Layer,
+		Color:      l.Color,
+		TrueColor:  l.TrueColor,
+		ColorName:  l.ColorName,
+		X1:         cx + (l.X1-cx)*factor,
+		Y1:         cy + (l.Y1-cy)*factor,
+		X2:         cx + (l.X2-cx)*factor,
+		Y2:         cy + (l.Y2-cy)*factor,
+		LineType:   l.LineType,
+		LineWeight: l.LineWeight,
 	}
 }
 
@@ -83,11 +92,14 @@ func (l *Line) Scale(factor, cx, cy float64) *Line {
 //	moved := circle.Translate(100, 100) // Center at (150,150)
 func (c *Circle) Translate(dx, dy float64) *Circle {
 	return &Circle{
-		Layer:   c.Layer,
-		Color:   c.Color,
-		CenterX: c.CenterX + dx,
-		CenterY: c.CenterY + dy,
-		Radius:  c.Radius,
+		Layer:      c.Layer,
+		Color:      c.Color,
+		TrueColor:  c.TrueColor,
+		ColorName:  c.ColorName,
+		LineWeight: c.LineWeight,
+		CenterX:    c.CenterX + dx,
+		CenterY:    c.CenterY + dy,
+		Radius:     c.Radius,
 	}
 }
 
@@ -100,11 +112,14 @@ func (c *Circle) Translate(dx, dy float64) *Circle {
 //	scaled := circle.Scale(2.0) // Radius becomes 50
 func (c *Circle) Scale(factor float64) *Circle {
 	return &Circle{
-		Layer:   c.Layer,
-		Color:   c.Color,
-		CenterX: c.CenterX,
-		CenterY: c.CenterY,
-		Radius:  c.Radius * factor,
+		Layer:      c.Layer,
+		Color:      c.Color,
+		TrueColor:  c.TrueColor,
+		ColorName:  c.ColorName,
+		LineWeight: c.LineWeight,
+		CenterX:    c.CenterX,
+		CenterY:    c.CenterY,
+		Radius:     c.Radius * factor,
 	}
 }
 
@@ -119,6 +134,9 @@ func (a *Arc) Translate(dx, dy float64) *Arc {
 	return &Arc{
 		Layer:      a.Layer,
 		Color:      a.Color,
+		TrueColor:  a.TrueColor,
+		ColorName:  a.ColorName,
+		LineWeight: a.LineWeight,
 		CenterX:    a.CenterX + dx,
 		CenterY:    a.CenterY + dy,
 		Radius:     a.Radius,
@@ -138,6 +156,9 @@ func (a *Arc) Scale(factor float64) *Arc {
 	return &Arc{
 		Layer:      a.Layer,
 		Color:      a.Color,
+		TrueColor:  a.TrueColor,
+		ColorName:  a.ColorName,
+		LineWeight: a.LineWeight,
 		CenterX:    a.CenterX,
 		CenterY:    a.CenterY,
 		Radius:     a.Radius * factor,
@@ -157,6 +178,9 @@ func (e *Ellipse) Translate(dx, dy float64) *Ellipse {
 	return &Ellipse{
 		Layer:      e.Layer,
 		Color:      e.Color,
+		TrueColor:  e.TrueColor,
+		ColorName:  e.ColorName,
+		LineWeight: e.LineWeight,
 		CenterX:    e.CenterX + dx,
 		CenterY:    e.CenterY + dy,
 		MajorAxisX: e.MajorAxisX,
@@ -178,6 +202,9 @@ func (e *Ellipse) Scale(factor float64) *Ellipse {
 	return &Ellipse{
 		Layer:      e.Layer,
 		Color:      e.Color,
+		TrueColor:  e.TrueColor,
+		ColorName:  e.ColorName,
+		LineWeight: e.LineWeight,
 		CenterX:    e.CenterX,
 		CenterY:    e.CenterY,
 		MajorAxisX: e.MajorAxisX * factor,
@@ -197,10 +224,12 @@ func (e *Ellipse) Scale(factor float64) *Ellipse {
 //	moved := point.Translate(50, 50) // Point at (150,250)
 func (p *Point) Translate(dx, dy float64) *Point {
 	return &Point{
-		Layer: p.Layer,
-		Color: p.Color,
-		X:     p.X + dx,
-		Y:     p.Y + dy,
+		Layer:     p.Layer,
+		Color:     p.Color,
+		TrueColor: p.TrueColor,
+		ColorName: p.ColorName,
+		X:         p.X + dx,
+		Y:         p.Y + dy,
 	}
 }
 
@@ -213,14 +242,18 @@ func (p *Point) Translate(dx, dy float64) *Point {
 //	moved := text.Translate(50, 50) // Text at (60,60)
 func (t *Text) Translate(dx, dy float64) *Text {
 	return &Text{
-		Layer:    t.Layer,
-		Color:    t.Color,
-		X:        t.X + dx,
-		Y:        t.Y + dy,
-		Height:   t.Height,
-		Rotation: t.Rotation,
-		Content:  t.Content,
-		Style:    t.Style,
+		Layer:        t.Layer,
+		Color:        t.Color,
+		TrueColor:    t.TrueColor,
+		ColorName:    t.ColorName,
+		X:            t.X + dx,
+		Y:            t.Y + dy,
+		Height:       t.Height,
+		Rotation:     t.Rotation,
+		Content:      t.Content,
+		Style:        t.Style,
+		WidthFactor:  t.WidthFactor,
+		ObliqueAngle: t.ObliqueAngle,
 	}
 }
 
@@ -233,14 +266,18 @@ func (t *Text) Translate(dx, dy float64) *Text {
 //	rotated := text.Rotate(45) // Rotation becomes 45°
 func (t *Text) Rotate(angleDeg float64) *Text {
 	return &Text{
-		Layer:    t.Layer,
-		Color:    t.Color,
-		X:        t.X,
-		Y:        t.Y,
-		Height:   t.Height,
-		Rotation: t.Rotation + angleDeg,
-		Content:  t.Content,
-		Style:    t.Style,
+		Layer:        t.Layer,
+		Color:        t.Color,
+		TrueColor:    t.TrueColor,
+		ColorName:    t.ColorName,
+		X:            t.X,
+		Y:            t.Y,
+		Height:       t.Height,
+		Rotation:     t.Rotation + angleDeg,
+		Content:      t.Content,
+		Style:        t.Style,
+		WidthFactor:  t.WidthFactor,
+		ObliqueAngle: t.ObliqueAngle,
 	}
 }
 
@@ -253,17 +290,73 @@ func (t *Text) Rotate(angleDeg float64) *Text {
 //	scaled := text.Scale(2.0) // Height becomes 10
 func (t *Text) Scale(factor float64) *Text {
 	return &Text{
-		Layer:    t.Layer,
-		Color:    t.Color,
-		X:        t.X,
-		Y:        t.Y,
-		Height:   t.Height * factor,
-		Rotation: t.Rotation,
-		Content:  t.Content,
-		Style:    t.Style,
+		Layer:        t.Layer,
+		Color:        t.Color,
+		TrueColor:    t.TrueColor,
+		ColorName:    t.ColorName,
+		X:            t.X,
+		Y:            t.Y,
+		Height:       t.Height * factor,
+		Rotation:     t.Rotation,
+		Content:      t.Content,
+		Style:        t.Style,
+		WidthFactor:  t.WidthFactor,
+		ObliqueAngle: t.ObliqueAngle,
 	}
 }
 
+// Translate moves an MText entity by the given delta values.
+// Returns a new MText instance with translated insertion point.
+//
+// Example:
+//
+//	mtext := dxf.NewMText(10, 10, "Hello")
+//	moved := mtext.Translate(50, 50) // MText at (60,60)
+func (m *MText) Translate(dx, dy float64) *MText {
+	moved := *m
+	moved.X += dx
+	moved.Y += dy
+	return &moved
+}
+
+// Rotate rotates an MText entity around a center point by the given angle in
+// degrees. Both the insertion point and the text direction are rotated.
+// Returns a new MText instance.
+//
+// Example:
+//
+//	mtext := dxf.NewMText(10, 0, "Hello")
+//	rotated := mtext.Rotate(90, 0, 0) // MText at (0,10), rotation 90°
+func (m *MText) Rotate(angleDeg, cx, cy float64) *MText {
+	angle := angleDeg * math.Pi / 180.0
+	cos := math.Cos(angle)
+	sin := math.Sin(angle)
+
+	dx, dy := m.X-cx, m.Y-cy
+	rotated := *m
+	rotated.X = cx + dx*cos - dy*sin
+	rotated.Y = cy + dx*sin + dy*cos
+	rotated.Rotation = m.Rotation + angleDeg
+	return &rotated
+}
+
+// Scale scales an MText entity from a center point by the given factor.
+// The insertion point, character height and column width are scaled.
+// Returns a new MText instance.
+//
+// Example:
+//
+//	mtext := dxf.NewMText(10, 10, "Hello", dxf.WithMTextHeight(5))
+//	scaled := mtext.Scale(2.0, 0, 0) // MText at (20,20), height 10
+func (m *MText) Scale(factor, cx, cy float64) *MText {
+	scaled := *m
+	scaled.X = cx + (m.X-cx)*factor
+	scaled.Y = cy + (m.Y-cy)*factor
+	scaled.Height = m.Height * factor
+	scaled.Width = m.Width * factor
+	return &scaled
+}
+
 // Translate moves a Solid entity by the given delta values.
 // Returns a new Solid instance with translated vertices.
 //
@@ -273,16 +366,18 @@ func (t *Text) Scale(factor float64) *Text {
 //	moved := solid.Translate(50, 50)
 func (s *Solid) Translate(dx, dy float64) *Solid {
 	return &Solid{
-		Layer: s.Layer,
-		Color: s.Color,
-		X1:    s.X1 + dx,
-		Y1:    s.Y1 + dy,
-		X2:    s.X2 + dx,
-		Y2:    s.Y2 + dy,
-		X3:    s.X3 + dx,
-		Y3:    s.Y3 + dy,
-		X4:    s.X4 + dx,
-		Y4:    s.Y4 + dy,
+		Layer:     s.Layer,
+		Color:     s.Color,
+		TrueColor: s.TrueColor,
+		ColorName: s.ColorName,
+		X1:        s.X1 + dx,
+		Y1:        s.Y1 + dy,
+		X2:        s.X2 + dx,
+		Y2:        s.Y2 + dy,
+		X3:        s.X3 + dx,
+		Y3:        s.Y3 + dy,
+		X4:        s.X4 + dx,
+		Y4:        s.Y4 + dy,
 	}
 }
 
@@ -311,16 +406,18 @@ func (s *Solid) Rotate(angleDeg, cx, cy float64) *Solid {
 	rx4, ry4 := rotatePoint(s.X4, s.Y4)
 
 	return &Solid{
-		Layer: s.Layer,
-		Color: s.Color,
-		X1:    rx1,
-		Y1:    ry1,
-		X2:    rx2,
-		Y2:    ry2,
-		X3:    rx3,
-		Y3:    ry3,
-		X4:    rx4,
-		Y4:    ry4,
+		Layer:     s.Layer,
+		Color:     s.Color,
+		TrueColor: s.TrueColor,
+		ColorName: s.ColorName,
+		X1:        rx1,
+		Y1:        ry1,
+		X2:        rx2,
+		Y2:        ry2,
+		X3:        rx3,
+		Y3:        ry3,
+		X4:        rx4,
+		Y4:        ry4,
 	}
 }
 
@@ -342,19 +439,54 @@ func (s *Solid) Scale(factor, cx, cy float64) *Solid {
 	sx4, sy4 := scalePoint(s.X4, s.Y4)
 
 	return &Solid{
-		Layer: s.Layer,
-		Color: s.Color,
-		X1:    sx1,
-		Y1:    sy1,
-		X2:    sx2,
-		Y2:    sy2,
-		X3:    sx3,
-		Y3:    sy3,
-		X4:    sx4,
-		Y4:    sy4,
+		Layer:     s.Layer,
+		Color:     s.Color,
+		TrueColor: s.TrueColor,
+		ColorName: s.ColorName,
+		X1:        sx1,
+		Y1:        sy1,
+		X2:        sx2,
+		Y2:        sy2,
+		X3:        sx3,
+		Y3:        sy3,
+		X4:        sx4,
+		Y4:        sy4,
 	}
 }
 
+// Translate moves a Hatch entity by the given delta values.
+// Returns a new Hatch instance with translated boundaries and pattern origin.
+//
+// Example:
+//
+//	hatch := dxf.NewHatch([]dxf.HatchBoundary{dxf.CircleBoundary(0, 0, 10)})
+//	moved := hatch.Translate(50, 50) // Circle centered at (50,50)
+func (h *Hatch) Translate(dx, dy float64) *Hatch {
+	moved := *h
+
+	moved.Boundaries = make([]HatchBoundary, len(h.Boundaries))
+	for i, b := range h.Boundaries {
+		vertices := make([]HatchVertex, len(b.Vertices))
+		for j, v := range b.Vertices {
+			vertices[j] = HatchVertex{X: v.X + dx, Y: v.Y + dy, Bulge: v.Bulge}
+		}
+		edges := make([]HatchEdge, len(b.Edges))
+		for j, e := range b.Edges {
+			e.X1, e.Y1, e.X2, e.Y2 = e.X1+dx, e.Y1+dy, e.X2+dx, e.Y2+dy
+			e.CenterX, e.CenterY = e.CenterX+dx, e.CenterY+dy
+			edges[j] = e
+		}
+		moved.Boundaries[i] = HatchBoundary{Vertices: vertices, Edges: edges}
+	}
+
+	moved.PatternLines = make([]HatchPatternLine, len(h.PatternLines))
+	for i, pl := range h.PatternLines {
+		pl.BaseX, pl.BaseY = pl.BaseX+dx, pl.BaseY+dy
+		moved.PatternLines[i] = pl
+	}
+	return &moved
+}
+
 // Translate moves an Insert entity by the given delta values.
 // Returns a new Insert instance with translated insertion point.
 //
@@ -366,6 +498,8 @@ func (i *Insert) Translate(dx, dy float64) *Insert {
 	return &Insert{
 		Layer:     i.Layer,
 		Color:     i.Color,
+		TrueColor: i.TrueColor,
+		ColorName: i.ColorName,
 		BlockName: i.BlockName,
 		X:         i.X + dx,
 		Y:         i.Y + dy,
@@ -386,6 +520,8 @@ func (i *Insert) Rotate(angleDeg float64) *Insert {
 	return &Insert{
 		Layer:     i.Layer,
 		Color:     i.Color,
+		TrueColor: i.TrueColor,
+		ColorName: i.ColorName,
 		BlockName: i.BlockName,
 		X:         i.X,
 		Y:         i.Y,
@@ -406,6 +542,8 @@ func (i *Insert) Scale(factor float64) *Insert {
 	return &Insert{
 		Layer:     i.Layer,
 		Color:     i.Color,
+		TrueColor: i.TrueColor,
+		ColorName: i.ColorName,
 		BlockName: i.BlockName,
 		X:         i.X,
 		Y:         i.Y,
@@ -414,3 +552,32 @@ func (i *Insert) Scale(factor float64) *Insert {
 		Rotation:  i.Rotation,
 	}
 }
+
+// Translate moves an Image entity by the given delta values.
+// Returns a new Image instance with translated insertion point.
+//
+// Example:
+//
+//	moved := img.Translate(50, 50)
+func (i *Image) Translate(dx, dy float64) *Image {
+	return &Image{
+		Layer:     i.Layer,
+		Color:     i.Color,
+		TrueColor: i.TrueColor,
+		ColorName: i.ColorName,
+		ImageDef:  i.ImageDef,
+		X:         i.X + dx,
+		Y:         i.Y + dy,
+		UX:        i.UX,
+		UY:        i.UY,
+		VX:        i.VX,
+		VY:        i.VY,
+		Width:     i.Width,
+		Height:    i.Height,
+		Clipped:   i.Clipped,
+		ClipX1:    i.ClipX1,
+		ClipY1:    i.ClipY1,
+		ClipX2:    i.ClipX2,
+		ClipY2:    i.ClipY2,
+	}
+}
@@ -106,6 +106,28 @@ func TestTextScale(t *testing.T) {
 	}
 }
 
+func TestMTextTransform(t *testing.T) {
+	mtext := NewMText(10, 0, "Hello", WithMTextHeight(5), WithMTextWidth(40))
+
+	moved := mtext.Translate(5, 5)
+	if moved.X != 15 || moved.Y != 5 || moved.Content != "Hello" {
+		t.Errorf("Translate: got %+v", moved)
+	}
+
+	rotated := mtext.Rotate(90, 0, 0)
+	if math.Abs(rotated.X) > 1e-9 || math.Abs(rotated.Y-10) > 1e-9 || rotated.Rotation != 90 {
+		t.Errorf("Rotate: got (%f, %f) at %f°", rotated.X, rotated.Y, rotated.Rotation)
+	}
+
+	scaled := mtext.Scale(2, 0, 0)
+	if scaled.X != 20 || scaled.Height != 10 || scaled.Width != 80 {
+		t.Errorf("Scale: got %+v", scaled)
+	}
+	if mtext.X != 10 || mtext.Height != 5 {
+		t.Error("transforms must not modify the original")
+	}
+}
+
 func TestSolidTranslate(t *testing.T) {
 	solid := NewSolid(0, 0, 100, 0, 50, 100, 50, 100)
 	moved := solid.Translate(50, 50)
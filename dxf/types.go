@@ -24,17 +24,35 @@
 //	w.WriteDocument(doc)
 package dxf
 
+import "strings"
+
 // Document represents a complete DXF document structure.
 // It contains layer definitions, drawing entities, and optional block definitions.
 type Document struct {
 	// Layers contains the layer definitions used by entities.
 	Layers []Layer
 
+	// LineTypes contains additional line type definitions written to the
+	// LTYPE table after the standard ones (see DefaultLineTypes).
+	LineTypes []LineType
+
+	// TextStyles contains additional text styles written to the STYLE table
+	// after the STANDARD style.
+	TextStyles []TextStyle
+
+	// DimStyles contains additional dimension styles written to the DIMSTYLE
+	// table after the STANDARD style.
+	DimStyles []DimStyle
+
 	// Entities contains all drawing entities in the document.
 	Entities []Entity
 
 	// Blocks contains reusable block definitions.
 	Blocks []Block
+
+	// ImageDefs contains the raster image files referenced by Image entities.
+	// They are written as IMAGEDEF objects in the OBJECTS section.
+	ImageDefs []ImageDef
 }
 
 // Layer represents a DXF layer definition.
@@ -50,6 +68,10 @@ type Layer struct {
 	// LineType specifies the line pattern (e.g., "CONTINUOUS", "DASHED").
 	LineType string
 
+	// LineWeight is the default line weight in 1/100 mm (group code 370) of
+	// entities drawn BYLAYER. Zero writes LineWeightDefault.
+	LineWeight int
+
 	// Frozen indicates if the layer is frozen (not visible and not printable).
 	Frozen bool
 
@@ -57,6 +79,108 @@ type Layer struct {
 	Locked bool
 }
 
+// LineType represents a DXF line type (LTYPE) definition.
+type LineType struct {
+	// Name is the unique line type name referenced by layers and entities.
+	Name string
+
+	// Description is the human-readable description of the pattern.
+	Description string
+
+	// Pattern holds the dash lengths in drawing units: positive values are
+	// dashes, negative values are gaps and zero is a dot. An empty pattern
+	// is a continuous line.
+	Pattern []float64
+}
+
+// TextStyle represents a DXF text style (STYLE table entry).
+type TextStyle struct {
+	// Name is the unique style name referenced by text entities.
+	Name string
+
+	// Font is the primary font file, e.g. "txt" (SHX) or "msgothic.ttc".
+	Font string
+
+	// BigFont is the optional SHX big font file for Asian characters.
+	BigFont string
+
+	// FontFamily is the TrueType font family name. When set it is written
+	// as ACAD extended data together with the Bold and Italic flags.
+	FontFamily string
+
+	// Height is the fixed text height; 0 lets each text set its own height.
+	Height float64
+
+	// WidthFactor is the character width factor; 0 means 1.
+	WidthFactor float64
+
+	// ObliqueAngle is the slant of the characters in degrees.
+	ObliqueAngle float64
+
+	// Bold and Italic select the TrueType font style.
+	Bold, Italic bool
+}
+
+// DimStyle represents a DXF dimension style (DIMSTYLE table entry).
+// Sizes are paper sizes that are multiplied by Scale. A zero ArrowSize or
+// TextHeight is written as the StandardDimStyle value.
+type DimStyle struct {
+	// Name is the unique style name referenced by dimension entities.
+	Name string
+
+	// Scale is the overall scale factor (DIMSCALE); 0 means 1.
+	Scale float64
+
+	// ArrowSize is the size of arrowheads (DIMASZ).
+	ArrowSize float64
+
+	// ArrowBlock is the arrowhead name (DIMBLK), e.g. "_DOT" or "_OPEN30".
+	// Empty selects the default closed filled arrow.
+	ArrowBlock string
+
+	// ExtLineOffset is the gap between the measured points and the start of
+	// the extension lines (DIMEXO).
+	ExtLineOffset float64
+
+	// ExtLineExtension is the length extension lines project beyond the
+	// dimension line (DIMEXE).
+	ExtLineExtension float64
+
+	// TextHeight is the dimension text height (DIMTXT).
+	TextHeight float64
+
+	// TextGap is the gap between the dimension line and the text (DIMGAP).
+	TextGap float64
+
+	// TextAbove places the text above the dimension line (DIMTAD) instead
+	// of centered on it.
+	TextAbove bool
+
+	// LinearFactor scales measured lengths (DIMLFAC); 0 means 1.
+	LinearFactor float64
+
+	// Suffix is the text appended to measured values (DIMPOST), e.g. "mm".
+	Suffix string
+
+	// Decimals is the number of decimal places of lengths (DIMDEC).
+	Decimals int
+
+	// SuppressTrailingZeros removes trailing decimal zeros (DIMZIN 8).
+	SuppressTrailingZeros bool
+
+	// AngleUnit is the unit of angular dimensions (DIMAUNIT):
+	// 0 for decimal degrees, 1 for degrees/minutes/seconds.
+	AngleUnit int
+
+	// AngleDecimals is the number of decimal places of angles (DIMADEC).
+	AngleDecimals int
+
+	// LineColor, ExtLineColor and TextColor are the ACI colors of the
+	// dimension line (DIMCLRD), extension lines (DIMCLRE) and text (DIMCLRT).
+	// Zero means BYBLOCK.
+	LineColor, ExtLineColor, TextColor int
+}
+
 // Entity is the interface implemented by all DXF drawing entities.
 // Each entity must provide its type name and group code representation.
 type Entity interface {
@@ -78,6 +202,25 @@ type GroupCode struct {
 	Value interface{}
 }
 
+// withTrueColor inserts group code 420 after the ACI color (62) when a true
+// color is set, followed by the color name (430) when one is given.
+func withTrueColor(codes []GroupCode, trueColor *int, colorName string) []GroupCode {
+	if trueColor == nil {
+		return codes
+	}
+	extra := []GroupCode{{420, *trueColor}}
+	if colorName != "" {
+		extra = append(extra, GroupCode{430, EscapeUnicode(colorName)})
+	}
+	for k, gc := range codes {
+		if gc.Code == 62 {
+			codes = append(codes[:k+1], append(extra, codes[k+1:]...)...)
+			break
+		}
+	}
+	return codes
+}
+
 // Line represents a DXF LINE entity.
 // A line is defined by two points in 2D or 3D space.
 type Line struct {
@@ -87,9 +230,22 @@ type Line struct {
 	// Color is the ACI color number (0 = BYLAYER, 1-255 = specific colors).
 	Color int
 
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
 	// LineType specifies the line pattern (e.g., "CONTINUOUS", "DASHED").
 	LineType string
 
+	// LineWeight is the line weight in 1/100 mm written as group code 370
+	// (see NearestLineWeight), or one of the LineWeightBy* constants.
+	// Zero omits it, so the entity uses the weight of its layer.
+	LineWeight int
+
 	// X1, Y1 are the coordinates of the line's start point.
 	X1, Y1 float64
 
@@ -102,9 +258,9 @@ func (l *Line) EntityType() string { return "LINE" }
 
 // GroupCodes returns the DXF group codes for this line entity.
 func (l *Line) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withLineWeight(withTrueColor([]GroupCode{
 		{0, "LINE"},
-		{8, l.Layer},
+		{8, EscapeUnicode(l.Layer)},
 		{62, l.Color},
 		{6, l.LineType},
 		{10, l.X1},
@@ -113,7 +269,7 @@ func (l *Line) GroupCodes() []GroupCode {
 		{11, l.X2},
 		{21, l.Y2},
 		{31, 0.0},
-	}
+	}, l.TrueColor, l.ColorName), l.LineWeight)
 }
 
 // Circle represents a DXF CIRCLE entity.
@@ -125,9 +281,22 @@ type Circle struct {
 	// Color is the ACI color number (0 = BYLAYER).
 	Color int
 
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
 	// LineType specifies the line pattern for the circle outline.
 	LineType string
 
+	// LineWeight is the line weight in 1/100 mm written as group code 370
+	// (see NearestLineWeight), or one of the LineWeightBy* constants.
+	// Zero omits it, so the entity uses the weight of its layer.
+	LineWeight int
+
 	// CenterX, CenterY are the coordinates of the circle's center point.
 	CenterX float64
 	CenterY float64
@@ -141,16 +310,16 @@ func (c *Circle) EntityType() string { return "CIRCLE" }
 
 // GroupCodes returns the DXF group codes for this circle entity.
 func (c *Circle) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withLineWeight(withTrueColor([]GroupCode{
 		{0, "CIRCLE"},
-		{8, c.Layer},
+		{8, EscapeUnicode(c.Layer)},
 		{62, c.Color},
 		{6, c.LineType},
 		{10, c.CenterX},
 		{20, c.CenterY},
 		{30, 0.0},
 		{40, c.Radius},
-	}
+	}, c.TrueColor, c.ColorName), c.LineWeight)
 }
 
 // Arc represents a DXF ARC entity.
@@ -162,9 +331,22 @@ type Arc struct {
 	// Color is the ACI color number (0 = BYLAYER).
 	Color int
 
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
 	// LineType specifies the line pattern for the arc.
 	LineType string
 
+	// LineWeight is the line weight in 1/100 mm written as group code 370
+	// (see NearestLineWeight), or one of the LineWeightBy* constants.
+	// Zero omits it, so the entity uses the weight of its layer.
+	LineWeight int
+
 	// CenterX, CenterY are the coordinates of the arc's center point.
 	CenterX float64
 	CenterY float64
@@ -183,9 +365,9 @@ type Arc struct {
 func (a *Arc) EntityType() string { return "ARC" }
 
 func (a *Arc) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withLineWeight(withTrueColor([]GroupCode{
 		{0, "ARC"},
-		{8, a.Layer},
+		{8, EscapeUnicode(a.Layer)},
 		{62, a.Color},
 		{6, a.LineType},
 		{10, a.CenterX},
@@ -194,7 +376,7 @@ func (a *Arc) GroupCodes() []GroupCode {
 		{40, a.Radius},
 		{50, a.StartAngle},
 		{51, a.EndAngle},
-	}
+	}, a.TrueColor, a.ColorName), a.LineWeight)
 }
 
 // Ellipse represents a DXF ELLIPSE entity.
@@ -206,9 +388,22 @@ type Ellipse struct {
 	// Color is the ACI color number (0 = BYLAYER).
 	Color int
 
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
 	// LineType specifies the line pattern for the ellipse.
 	LineType string
 
+	// LineWeight is the line weight in 1/100 mm written as group code 370
+	// (see NearestLineWeight), or one of the LineWeightBy* constants.
+	// Zero omits it, so the entity uses the weight of its layer.
+	LineWeight int
+
 	// CenterX, CenterY are the coordinates of the ellipse's center point.
 	CenterX float64
 	CenterY float64
@@ -231,9 +426,9 @@ type Ellipse struct {
 func (e *Ellipse) EntityType() string { return "ELLIPSE" }
 
 func (e *Ellipse) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withLineWeight(withTrueColor([]GroupCode{
 		{0, "ELLIPSE"},
-		{8, e.Layer},
+		{8, EscapeUnicode(e.Layer)},
 		{62, e.Color},
 		{6, e.LineType},
 		{10, e.CenterX},
@@ -245,7 +440,7 @@ func (e *Ellipse) GroupCodes() []GroupCode {
 		{40, e.MinorRatio},
 		{41, e.StartParam},
 		{42, e.EndParam},
-	}
+	}, e.TrueColor, e.ColorName), e.LineWeight)
 }
 
 // Point represents a DXF POINT entity.
@@ -257,6 +452,14 @@ type Point struct {
 	// Color is the ACI color number (0 = BYLAYER).
 	Color int
 
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
 	// LineType specifies the line pattern for the point marker.
 	LineType string
 
@@ -269,15 +472,15 @@ func (p *Point) EntityType() string { return "POINT" }
 
 // GroupCodes returns the DXF group codes for this point entity.
 func (p *Point) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withTrueColor([]GroupCode{
 		{0, "POINT"},
-		{8, p.Layer},
+		{8, EscapeUnicode(p.Layer)},
 		{62, p.Color},
 		{6, p.LineType},
 		{10, p.X},
 		{20, p.Y},
 		{30, 0.0},
-	}
+	}, p.TrueColor, p.ColorName)
 }
 
 // Text represents a DXF TEXT entity.
@@ -289,6 +492,14 @@ type Text struct {
 	// Color is the ACI color number (0 = BYLAYER).
 	Color int
 
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
 	// LineType specifies the line pattern applied to the text entity.
 	LineType string
 
@@ -306,6 +517,13 @@ type Text struct {
 
 	// Style is the text style name (e.g., "STANDARD").
 	Style string
+
+	// WidthFactor is the relative X scale factor (group code 41).
+	// Zero or 1 uses the default proportions.
+	WidthFactor float64
+
+	// ObliqueAngle is the slant of the characters in degrees (group code 51).
+	ObliqueAngle float64
 }
 
 // EntityType returns "TEXT".
@@ -326,10 +544,572 @@ func (t *Text) GroupCodes() []GroupCode {
 	if t.Rotation != 0 {
 		codes = append(codes, GroupCode{50, t.Rotation})
 	}
+	if t.WidthFactor != 0 && t.WidthFactor != 1 {
+		codes = append(codes, GroupCode{41, t.WidthFactor})
+	}
+	if t.ObliqueAngle != 0 {
+		codes = append(codes, GroupCode{51, t.ObliqueAngle})
+	}
 	if t.Style != "" {
-		codes = append(codes, GroupCode{7, t.Style})
+		codes = append(codes, GroupCode{7, EscapeUnicode(t.Style)})
+	}
+	return withTrueColor(codes, t.TrueColor, t.ColorName)
+}
+
+// MTEXT attachment points (group code 71): the point of the text block that
+// is placed at the insertion point.
+const (
+	MTextTopLeft      = 1
+	MTextTopCenter    = 2
+	MTextTopRight     = 3
+	MTextMiddleLeft   = 4
+	MTextMiddleCenter = 5
+	MTextMiddleRight  = 6
+	MTextBottomLeft   = 7
+	MTextBottomCenter = 8
+	MTextBottomRight  = 9
+)
+
+// MTEXT drawing directions (group code 72).
+const (
+	MTextLeftToRight = 1
+	MTextTopToBottom = 3
+	MTextByStyle     = 5
+)
+
+// mtextChunkSize is the maximum length of a single MTEXT content group.
+const mtextChunkSize = 250
+
+// MText represents a DXF MTEXT (multiline text) entity.
+// The content may contain inline formatting codes such as "\P" for a new
+// paragraph; see the MText* helper functions for building them.
+type MText struct {
+	// Layer is the name of the layer this entity belongs to.
+	Layer string
+
+	// Color is the ACI color number (0 = BYLAYER).
+	Color int
+
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
+	// X, Y are the coordinates of the insertion point.
+	X, Y float64
+
+	// Height is the nominal character height in drawing units.
+	Height float64
+
+	// Width is the reference rectangle (column) width used for word wrapping.
+	// Zero disables wrapping.
+	Width float64
+
+	// Rotation is the text rotation angle in degrees.
+	Rotation float64
+
+	// AttachmentPoint is one of the MText* attachment constants
+	// (e.g. MTextTopLeft); zero means MTextTopLeft.
+	AttachmentPoint int
+
+	// DrawingDirection is one of MTextLeftToRight, MTextTopToBottom or
+	// MTextByStyle; zero means MTextLeftToRight.
+	DrawingDirection int
+
+	// LineSpacing is the line spacing factor (0.25-4.0); zero means 1.
+	LineSpacing float64
+
+	// Content is the text including formatting codes.
+	Content string
+
+	// Style is the text style name (e.g., "STANDARD").
+	Style string
+}
+
+// EntityType returns "MTEXT".
+func (m *MText) EntityType() string { return "MTEXT" }
+
+func (m *MText) GroupCodes() []GroupCode {
+	attachment := m.AttachmentPoint
+	if attachment < MTextTopLeft || attachment > MTextBottomRight {
+		attachment = MTextTopLeft
+	}
+	direction := m.DrawingDirection
+	if direction == 0 {
+		direction = MTextLeftToRight
+	}
+
+	codes := []GroupCode{
+		{0, "MTEXT"},
+		{8, EscapeUnicode(m.Layer)},
+		{62, m.Color},
+		{10, m.X},
+		{20, m.Y},
+		{30, 0.0},
+		{40, m.Height},
+		{41, m.Width},
+		{71, attachment},
+		{72, direction},
+	}
+
+	// Long content is split into 250 character chunks: all but the last
+	// are written as group code 3, the last as group code 1.
+	chunks := mtextChunks(m.Content)
+	for _, c := range chunks[:len(chunks)-1] {
+		codes = append(codes, GroupCode{3, c})
+	}
+	codes = append(codes, GroupCode{1, chunks[len(chunks)-1]})
+
+	if m.Style != "" {
+		codes = append(codes, GroupCode{7, EscapeUnicode(m.Style)})
+	}
+	if m.Rotation != 0 {
+		codes = append(codes, GroupCode{50, m.Rotation})
+	}
+	if m.LineSpacing != 0 && m.LineSpacing != 1 {
+		codes = append(codes, GroupCode{44, m.LineSpacing})
+	}
+	return withTrueColor(codes, m.TrueColor, m.ColorName)
+}
+
+// mtextChunks escapes s, replacing newlines with paragraph breaks, and splits
+// it into chunks of at most mtextChunkSize bytes without breaking a character
+// escape apart. It returns at least one (possibly empty) chunk.
+func mtextChunks(s string) []string {
+	var chunks []string
+	var cur []byte
+	for _, r := range s {
+		var esc string
+		switch r {
+		case '\n':
+			esc = `\P`
+		case '\r':
+			continue
+		default:
+			esc = EscapeUnicode(string(r))
+		}
+		if len(cur)+len(esc) > mtextChunkSize {
+			chunks = append(chunks, string(cur))
+			cur = cur[:0]
+		}
+		cur = append(cur, esc...)
+	}
+	return append(chunks, string(cur))
+}
+
+// DIMENSION types (group code 70).
+const (
+	DimLinear   = 0 // Rotated horizontal, vertical or inclined linear dimension
+	DimAligned  = 1 // Linear dimension parallel to the measured points
+	DimAngular  = 2 // Angle between two lines
+	DimDiameter = 3
+	DimRadius   = 4
+)
+
+// dimBlockOnly is the DIMENSION type flag marking the block as referenced
+// by this dimension only.
+const dimBlockOnly = 32
+
+// Dimension represents a DXF DIMENSION entity. Its appearance is stored in an
+// anonymous block ("*D<n>"): set BlockName to reference an existing block,
+// or leave it empty and put the drawn entities in Geometry to have the
+// writer create the block.
+//
+// The definition points depend on DimType:
+//   - DimLinear, DimAligned: (X1,Y1) and (X2,Y2) are the measured points and
+//     (DefX,DefY) lies on the dimension line
+//   - DimAngular: (X1,Y1)-(X2,Y2) is the first line, (X3,Y3)-(DefX,DefY) the
+//     second one and (X4,Y4) lies on the dimension arc
+//   - DimDiameter: (DefX,DefY) and (X3,Y3) are opposite points on the circle
+//   - DimRadius: (DefX,DefY) is the center and (X3,Y3) a point on the circle
+type Dimension struct {
+	// Layer is the name of the layer this entity belongs to.
+	Layer string
+
+	// Color is the ACI color number (0 = BYLAYER).
+	Color int
+
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
+	// DimType is one of DimLinear, DimAligned, DimAngular, DimDiameter or DimRadius.
+	DimType int
+
+	// BlockName is the name of the block holding the dimension graphics.
+	BlockName string
+
+	// Geometry are the entities drawn for the dimension when BlockName is empty.
+	Geometry []Entity
+
+	// Style is the dimension style name (e.g., "STANDARD").
+	Style string
+
+	// DefX, DefY is the main definition point (group code 10).
+	DefX, DefY float64
+
+	// TextX, TextY is the middle point of the dimension text.
+	TextX, TextY float64
+
+	// X1, Y1 through X4, Y4 are the definition points 13 to 16.
+	X1, Y1, X2, Y2, X3, Y3, X4, Y4 float64
+
+	// Rotation is the angle of the dimension line of DimLinear dimensions in degrees.
+	Rotation float64
+
+	// TextRotation is the rotation of the text in degrees; 0 uses the default.
+	TextRotation float64
+
+	// Measurement is the measured length or angle (in degrees).
+	Measurement float64
+
+	// Text overrides the measured value; "" shows the measurement and "<>"
+	// within the text stands for it.
+	Text string
+
+	// blockName is the anonymous block name assigned by the writer.
+	blockName string
+}
+
+// EntityType returns "DIMENSION".
+func (d *Dimension) EntityType() string { return "DIMENSION" }
+
+func (d *Dimension) GroupCodes() []GroupCode {
+	block := d.BlockName
+	if block == "" {
+		block = d.blockName
+	}
+	dimType := d.DimType
+	if block != "" && d.BlockName == "" {
+		dimType |= dimBlockOnly
+	}
+
+	codes := []GroupCode{
+		{0, "DIMENSION"},
+		{8, EscapeUnicode(d.Layer)},
+		{62, d.Color},
+	}
+	if block != "" {
+		codes = append(codes, GroupCode{2, EscapeUnicode(block)})
+	}
+	codes = append(codes,
+		GroupCode{10, d.DefX},
+		GroupCode{20, d.DefY},
+		GroupCode{30, 0.0},
+		GroupCode{11, d.TextX},
+		GroupCode{21, d.TextY},
+		GroupCode{31, 0.0},
+		GroupCode{70, dimType},
+	)
+	if d.Text != "" {
+		codes = append(codes, GroupCode{1, EscapeUnicode(d.Text)})
+	}
+	if d.Style != "" {
+		codes = append(codes, GroupCode{3, EscapeUnicode(d.Style)})
+	}
+	codes = append(codes, GroupCode{42, d.Measurement})
+	if d.TextRotation != 0 {
+		codes = append(codes, GroupCode{53, d.TextRotation})
+	}
+
+	point := func(code int, x, y float64) {
+		codes = append(codes, GroupCode{code, x}, GroupCode{code + 10, y}, GroupCode{code + 20, 0.0})
+	}
+	switch d.DimType {
+	case DimLinear, DimAligned:
+		point(13, d.X1, d.Y1)
+		point(14, d.X2, d.Y2)
+		if d.DimType == DimLinear {
+			codes = append(codes, GroupCode{50, d.Rotation})
+		}
+	case DimAngular:
+		point(13, d.X1, d.Y1)
+		point(14, d.X2, d.Y2)
+		point(15, d.X3, d.Y3)
+		point(16, d.X4, d.Y4)
+	case DimDiameter, DimRadius:
+		point(15, d.X3, d.Y3)
+	}
+	return withTrueColor(codes, d.TrueColor, d.ColorName)
+}
+
+// HATCH boundary edge types (group code 72).
+const (
+	HatchEdgeLine    = 1
+	HatchEdgeArc     = 2
+	HatchEdgeEllipse = 3
+)
+
+// Hatch represents a DXF HATCH entity: an area bounded by one or more closed
+// boundary paths and filled solid or with a line pattern. Nested boundaries
+// alternate between filled and empty (normal hatch style).
+type Hatch struct {
+	// Layer is the name of the layer this entity belongs to.
+	Layer string
+
+	// Color is the ACI color number (0 = BYLAYER).
+	Color int
+
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
+	// Pattern is the pattern name; "" or "SOLID" selects a solid fill.
+	Pattern string
+
+	// PatternAngle is the pattern rotation in degrees.
+	PatternAngle float64
+
+	// PatternScale is the pattern scale, or the line spacing of user-defined
+	// patterns; 0 means 1.
+	PatternScale float64
+
+	// PatternLines are the pattern definition lines. They are required for
+	// readers to draw non-solid patterns; a pattern with lines is written as
+	// user-defined.
+	PatternLines []HatchPatternLine
+
+	// Boundaries are the closed boundary paths.
+	Boundaries []HatchBoundary
+}
+
+// HatchPatternLine is a family of parallel pattern lines.
+type HatchPatternLine struct {
+	// Angle is the line direction in degrees.
+	Angle float64
+
+	// BaseX, BaseY is a point on one of the lines.
+	BaseX, BaseY float64
+
+	// OffsetX, OffsetY is the offset from one line to the next.
+	OffsetX, OffsetY float64
+
+	// Dashes are the dash (positive) and gap (negative) lengths; empty for
+	// continuous lines.
+	Dashes []float64
+}
+
+// HatchBoundary is a closed hatch boundary path, given either as a polyline
+// (Vertices) or as a loop of line, arc and ellipse edges (Edges).
+type HatchBoundary struct {
+	// Vertices are the corners of a polyline boundary; the path closes back
+	// to the first vertex.
+	Vertices []HatchVertex
+
+	// Edges are the connected edges of an edge boundary, used when Vertices
+	// is empty.
+	Edges []HatchEdge
+
+	// Hole marks an inner boundary (island) of the filled region. Other
+	// boundaries are written as external paths.
+	Hole bool
+}
+
+// HatchVertex is a polyline boundary vertex. Bulge is the tangent of a
+// quarter of the included angle of an arc segment to the next vertex
+// (0 for a straight segment, positive for counterclockwise arcs).
+type HatchVertex struct {
+	X, Y  float64
+	Bulge float64
+}
+
+// HatchEdge is a boundary edge. Type selects which fields are used:
+//   - HatchEdgeLine: X1,Y1 to X2,Y2
+//   - HatchEdgeArc: CenterX, CenterY, Radius, StartAngle, EndAngle
+//   - HatchEdgeEllipse: CenterX, CenterY, MajorAxisX, MajorAxisY (relative
+//     to the center), MinorRatio, StartAngle, EndAngle (parameters)
+//
+// Angles are in degrees and run from StartAngle to EndAngle counterclockwise,
+// or clockwise when Clockwise is set.
+type HatchEdge struct {
+	Type int
+
+	X1, Y1, X2, Y2 float64
+
+	CenterX, CenterY float64
+	Radius           float64
+
+	MajorAxisX, MajorAxisY float64
+	MinorRatio             float64
+
+	StartAngle, EndAngle float64
+	Clockwise            bool
+}
+
+// IsSolid reports whether the hatch is a solid fill.
+func (h *Hatch) IsSolid() bool {
+	return h.Pattern == "" || strings.EqualFold(h.Pattern, "SOLID")
+}
+
+// EntityType returns "HATCH".
+func (h *Hatch) EntityType() string { return "HATCH" }
+
+func (h *Hatch) GroupCodes() []GroupCode {
+	solid := h.IsSolid()
+	pattern := h.Pattern
+	patternType := 1 // Predefined
+	switch {
+	case solid:
+		pattern = "SOLID"
+	case len(h.PatternLines) > 0:
+		patternType = 0 // User-defined
+	}
+	solidFlag := 0
+	if solid {
+		solidFlag = 1
+	}
+
+	codes := []GroupCode{
+		{0, "HATCH"},
+		{100, "AcDbEntity"},
+		{8, EscapeUnicode(h.Layer)},
+		{62, h.Color},
+		{100, "AcDbHatch"},
+		{10, 0.0},
+		{20, 0.0},
+		{30, 0.0},
+		{210, 0.0},
+		{220, 0.0},
+		{230, 1.0},
+		{2, EscapeUnicode(pattern)},
+		{70, solidFlag},
+		{71, 0}, // Not associative
+		{91, len(h.Boundaries)},
+	}
+	for _, b := range h.Boundaries {
+		codes = append(codes, b.groupCodes()...)
+	}
+	codes = append(codes,
+		GroupCode{75, 0}, // Normal (odd parity) style
+		GroupCode{76, patternType},
+	)
+
+	if !solid {
+		scale := h.PatternScale
+		if scale == 0 {
+			scale = 1
+		}
+		codes = append(codes,
+			GroupCode{52, h.PatternAngle},
+			GroupCode{41, scale},
+			GroupCode{77, 0},
+			GroupCode{78, len(h.PatternLines)},
+		)
+		for _, pl := range h.PatternLines {
+			codes = append(codes,
+				GroupCode{53, pl.Angle},
+				GroupCode{43, pl.BaseX},
+				GroupCode{44, pl.BaseY},
+				GroupCode{45, pl.OffsetX},
+				GroupCode{46, pl.OffsetY},
+				GroupCode{79, len(pl.Dashes)},
+			)
+			for _, d := range pl.Dashes {
+				codes = append(codes, GroupCode{49, d})
+			}
+		}
+	}
+
+	codes = append(codes, GroupCode{98, 0}) // No seed points
+	return withTrueColor(codes, h.TrueColor, h.ColorName)
+}
+
+// groupCodes returns the boundary path data of a hatch boundary.
+func (b *HatchBoundary) groupCodes() []GroupCode {
+	if len(b.Vertices) > 0 {
+		hasBulge := 0
+		for _, v := range b.Vertices {
+			if v.Bulge != 0 {
+				hasBulge = 1
+				break
+			}
+		}
+		codes := []GroupCode{
+			{92, b.pathFlags() | 2}, // Polyline path
+			{72, hasBulge},
+			{73, 1}, // Closed
+			{93, len(b.Vertices)},
+		}
+		for _, v := range b.Vertices {
+			codes = append(codes, GroupCode{10, v.X}, GroupCode{20, v.Y})
+			if hasBulge == 1 {
+				codes = append(codes, GroupCode{42, v.Bulge})
+			}
+		}
+		return append(codes, GroupCode{97, 0})
+	}
+
+	codes := []GroupCode{
+		{92, b.pathFlags()}, // Edge path
+		{93, len(b.Edges)},
+	}
+	for _, e := range b.Edges {
+		codes = append(codes, e.groupCodes()...)
+	}
+	return append(codes, GroupCode{97, 0})
+}
+
+// pathFlags returns the boundary path type flags of group code 92 other than
+// the polyline flag: 1 (external) unless the boundary is a hole.
+func (b *HatchBoundary) pathFlags() int {
+	if b.Hole {
+		return 0
+	}
+	return 1
+}
+
+// groupCodes returns the data of a boundary edge. Clockwise arcs and
+// ellipses are stored with mirrored angles (360 - angle) and the
+// counterclockwise flag cleared.
+func (e *HatchEdge) groupCodes() []GroupCode {
+	start, end, ccw := e.StartAngle, e.EndAngle, 1
+	if e.Clockwise {
+		start, end, ccw = 360-start, 360-end, 0
+	}
+
+	switch e.Type {
+	case HatchEdgeArc:
+		return []GroupCode{
+			{72, HatchEdgeArc},
+			{10, e.CenterX},
+			{20, e.CenterY},
+			{40, e.Radius},
+			{50, start},
+			{51, end},
+			{73, ccw},
+		}
+	case HatchEdgeEllipse:
+		return []GroupCode{
+			{72, HatchEdgeEllipse},
+			{10, e.CenterX},
+			{20, e.CenterY},
+			{11, e.MajorAxisX},
+			{21, e.MajorAxisY},
+			{40, e.MinorRatio},
+			{50, start},
+			{51, end},
+			{73, ccw},
+		}
+	default:
+		return []GroupCode{
+			{72, HatchEdgeLine},
+			{10, e.X1},
+			{20, e.Y1},
+			{11, e.X2},
+			{21, e.Y2},
+		}
 	}
-	return codes
 }
 
 // Solid represents a DXF SOLID entity (filled triangle or quadrilateral).
@@ -341,6 +1121,14 @@ type Solid struct {
 	// Color is the ACI color number (0 = BYLAYER).
 	Color int
 
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
 	// LineType specifies the line pattern applied to the solid's outline.
 	LineType string
 
@@ -362,9 +1150,9 @@ func (s *Solid) EntityType() string { return "SOLID" }
 
 // GroupCodes returns the DXF group codes for this solid entity.
 func (s *Solid) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withTrueColor([]GroupCode{
 		{0, "SOLID"},
-		{8, s.Layer},
+		{8, EscapeUnicode(s.Layer)},
 		{62, s.Color},
 		{6, s.LineType},
 		{10, s.X1},
@@ -379,7 +1167,7 @@ func (s *Solid) GroupCodes() []GroupCode {
 		{13, s.X4},
 		{23, s.Y4},
 		{33, 0.0},
-	}
+	}, s.TrueColor, s.ColorName)
 }
 
 // Insert represents a DXF INSERT entity (block reference).
@@ -391,6 +1179,14 @@ type Insert struct {
 	// Color is the ACI color number (0 = BYLAYER).
 	Color int
 
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
 	// LineType specifies the line pattern applied to the insert reference.
 	LineType string
 
@@ -415,12 +1211,12 @@ func (i *Insert) EntityType() string { return "INSERT" }
 
 // GroupCodes returns the DXF group codes for this insert entity.
 func (i *Insert) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withTrueColor([]GroupCode{
 		{0, "INSERT"},
-		{8, i.Layer},
+		{8, EscapeUnicode(i.Layer)},
 		{62, i.Color},
 		{6, i.LineType},
-		{2, i.BlockName},
+		{2, EscapeUnicode(i.BlockName)},
 		{10, i.X},
 		{20, i.Y},
 		{30, 0.0},
@@ -428,7 +1224,140 @@ func (i *Insert) GroupCodes() []GroupCode {
 		{42, i.ScaleY},
 		{43, 1.0}, // ScaleZ
 		{50, i.Rotation},
+	}, i.TrueColor, i.ColorName)
+}
+
+// Image represents a DXF IMAGE entity (raster image underlay).
+// The image is placed by its insertion point and the vectors spanned by a
+// single pixel, so position, scale and rotation are all encoded in X/Y, U and V.
+type Image struct {
+	// Layer is the name of the layer this entity belongs to.
+	Layer string
+
+	// Color is the ACI color number (0 = BYLAYER).
+	Color int
+
+	// TrueColor is an optional 24-bit RGB color (0xRRGGBB) written as group
+	// code 420. Color remains the fallback for applications without true color.
+	TrueColor *int
+
+	// ColorName is an optional name for TrueColor, such as the user-defined
+	// name of an SXF color, written as group code 430.
+	ColorName string
+
+	// ImageDef is the name of the referenced ImageDef.
+	ImageDef string
+
+	// X, Y are the coordinates of the insertion point (lower-left corner).
+	X, Y float64
+
+	// UX, UY is the vector of one pixel along the image's horizontal axis.
+	UX, UY float64
+
+	// VX, VY is the vector of one pixel along the image's vertical axis.
+	VX, VY float64
+
+	// Width, Height are the image size in pixels.
+	Width, Height int
+
+	// Clipped enables the rectangular clipping boundary.
+	Clipped bool
+
+	// ClipX1, ClipY1 and ClipX2, ClipY2 are opposite corners of the clipping
+	// boundary in pixel coordinates, with the origin at the upper-left pixel
+	// center (the full image spans -0.5 to Width-0.5).
+	ClipX1, ClipY1, ClipX2, ClipY2 float64
+
+	// Handles assigned by the Writer to link the entity with its IMAGEDEF
+	// and IMAGEDEF_REACTOR objects.
+	handle, defHandle, reactorHandle string
+}
+
+// EntityType returns "IMAGE".
+func (i *Image) EntityType() string { return "IMAGE" }
+
+// GroupCodes returns the DXF group codes for this image entity.
+// Object handles are only included when the image is written by a Writer.
+func (i *Image) GroupCodes() []GroupCode {
+	codes := []GroupCode{{0, "IMAGE"}}
+	if i.handle != "" {
+		codes = append(codes, GroupCode{5, i.handle})
+	}
+
+	clip := [4]float64{-0.5, -0.5, float64(i.Width) - 0.5, float64(i.Height) - 0.5}
+	flags := 1 | 2 // Show image, show when not aligned with screen
+	clipping := 0
+	if i.Clipped {
+		clip = [4]float64{i.ClipX1, i.ClipY1, i.ClipX2, i.ClipY2}
+		flags |= 4
+		clipping = 1
 	}
+
+	codes = append(codes,
+		GroupCode{100, "AcDbEntity"},
+		GroupCode{8, EscapeUnicode(i.Layer)},
+		GroupCode{62, i.Color},
+	)
+	codes = withTrueColor(codes, i.TrueColor, i.ColorName)
+	codes = append(codes,
+		GroupCode{100, "AcDbRasterImage"},
+		GroupCode{90, 0},
+		GroupCode{10, i.X},
+		GroupCode{20, i.Y},
+		GroupCode{30, 0.0},
+		GroupCode{11, i.UX},
+		GroupCode{21, i.UY},
+		GroupCode{31, 0.0},
+		GroupCode{12, i.VX},
+		GroupCode{22, i.VY},
+		GroupCode{32, 0.0},
+		GroupCode{13, float64(i.Width)},
+		GroupCode{23, float64(i.Height)},
+	)
+	if i.defHandle != "" {
+		codes = append(codes, GroupCode{340, i.defHandle})
+	}
+	codes = append(codes,
+		GroupCode{70, flags},
+		GroupCode{280, clipping},
+		GroupCode{281, 50}, // Brightness
+		GroupCode{282, 50}, // Contrast
+		GroupCode{283, 0},  // Fade
+	)
+	if i.reactorHandle != "" {
+		codes = append(codes, GroupCode{360, i.reactorHandle})
+	}
+	codes = append(codes,
+		GroupCode{71, 1}, // Rectangular clipping boundary
+		GroupCode{91, 2},
+		GroupCode{14, clip[0]},
+		GroupCode{24, clip[1]},
+		GroupCode{14, clip[2]},
+		GroupCode{24, clip[3]},
+	)
+	return codes
+}
+
+// ImageDef represents a DXF IMAGEDEF object: a raster image file referenced
+// by one or more Image entities.
+type ImageDef struct {
+	// Name is the unique image definition name.
+	Name string
+
+	// FileName is the image file path stored in the DXF. Relative paths are
+	// resolved from the directory of the DXF file.
+	FileName string
+
+	// Width, Height are the image size in pixels.
+	Width, Height int
+
+	// Data is the image file content for images that should be written next
+	// to the DXF file (see WriteImageFiles), or nil for external files.
+	Data []byte
+
+	// Load returns the image file content when Data is nil, e.g. by
+	// decompressing a bundled image only once WriteImageFiles writes it.
+	Load func() ([]byte, error)
 }
 
 // Block represents a DXF block definition.
@@ -442,6 +1371,9 @@ type Block struct {
 	BaseX float64
 	BaseY float64
 
+	// Description is the optional block description (group code 4).
+	Description string
+
 	// Entities contains the entities that comprise this block.
 	Entities []Entity
 }
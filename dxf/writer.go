@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"os"
+	"path/filepath"
 	"strings"
 	"unicode"
+
+	"github.com/f4ah6o/jww-parser/jww"
 )
 
 // Writer serializes DXF documents to an io.Writer in ASCII DXF format.
@@ -14,6 +18,21 @@ import (
 type Writer struct {
 	w          io.Writer
 	nextHandle int
+
+	// imageDefHandles maps ImageDef names to their IMAGEDEF handles, and
+	// images lists the Image entities linked to them, in write order.
+	imageDefHandles map[string]string
+	images          []*Image
+
+	// dimBlocks are the anonymous blocks created from Dimension geometry.
+	dimBlocks []Block
+
+	// progress receives the bytes and entities written, out of
+	// totalEntities, when set by SetProgress.
+	progress      jww.ProgressFunc
+	written       int64
+	entities      int
+	totalEntities int
 }
 
 // NewWriter creates a new DXF writer that outputs to the provided io.Writer.
@@ -23,6 +42,36 @@ func NewWriter(w io.Writer) *Writer {
 	return &Writer{w: w, nextHandle: 1}
 }
 
+// SetProgress reports the progress of writing to fn as jww.StageWriting: the
+// bytes and the entities of the ENTITIES section written so far, as writing
+// starts, every 1024 entities and once done.
+//
+// Example:
+//
+//	w := dxf.NewWriter(f)
+//	w.SetProgress(func(p jww.Progress) {
+//		fmt.Printf("%d/%d entities\n", p.Entities, p.TotalEntities)
+//	})
+//	err := w.WriteDocument(doc)
+func (w *Writer) SetProgress(fn jww.ProgressFunc) {
+	w.progress = fn
+}
+
+// report reports the bytes and entities written so far.
+func (w *Writer) report() {
+	if w.progress != nil {
+		w.progress(jww.Progress{Stage: jww.StageWriting, Bytes: w.written, Entities: w.entities, TotalEntities: w.totalEntities})
+	}
+}
+
+// countEntity counts an entity of the ENTITIES section written, reporting
+// the progress every progressInterval entities.
+func (w *Writer) countEntity() {
+	if w.entities++; w.entities%progressInterval == 0 {
+		w.report()
+	}
+}
+
 // getHandle returns the next available handle as a hexadecimal string.
 // Handles are unique identifiers for DXF objects and are auto-incremented.
 func (w *Writer) getHandle() string {
@@ -55,19 +104,34 @@ func EscapeUnicode(s string) string {
 //
 // The DXF file structure consists of the following sections in order:
 //  1. HEADER section - document settings and variables
-//  2. TABLES section - layer, linetype, and text style definitions
-//  3. BLOCKS section - block definitions
+//  2. TABLES section - linetype, layer, text style, application and dimension style definitions
+//  3. BLOCKS section - block definitions, including the anonymous dimension blocks
 //  4. ENTITIES section - drawing entities
 //  5. EOF marker
 //
+// When the document contains images, a CLASSES section is written after the
+// HEADER and an OBJECTS section holding the IMAGEDEF objects before EOF.
+//
 // This method orchestrates writing all sections in the correct order
 // and with proper DXF formatting.
 func (w *Writer) WriteDocument(doc *Document) error {
+	w.totalEntities = len(doc.Entities)
+	w.report()
+	w.assignImageHandles(doc)
+	w.assignDimensionBlocks(doc)
+
 	// HEADER section
 	if err := w.writeHeader(); err != nil {
 		return err
 	}
 
+	// CLASSES section (only needed for image objects)
+	if len(w.images) > 0 {
+		if err := w.writeClasses(); err != nil {
+			return err
+		}
+	}
+
 	// TABLES section
 	if err := w.writeTables(doc); err != nil {
 		return err
@@ -83,14 +147,231 @@ func (w *Writer) WriteDocument(doc *Document) error {
 		return err
 	}
 
+	// OBJECTS section (image definitions)
+	if len(w.images) > 0 {
+		if err := w.writeObjects(doc); err != nil {
+			return err
+		}
+	}
+
 	// End of file
 	if err := w.writeGroupCode(0, "EOF"); err != nil {
 		return err
 	}
 
+	w.report()
 	return nil
 }
 
+// assignImageHandles gives every ImageDef and every Image entity (including
+// those inside blocks) the handles that link them together. Images that
+// reference an unknown ImageDef are left unlinked.
+func (w *Writer) assignImageHandles(doc *Document) {
+	w.imageDefHandles = make(map[string]string)
+	w.images = nil
+	if len(doc.ImageDefs) == 0 {
+		return
+	}
+
+	for _, def := range doc.ImageDefs {
+		w.imageDefHandles[def.Name] = w.getHandle()
+	}
+
+	for _, block := range doc.Blocks {
+		w.linkImages(block.Entities)
+	}
+	w.linkImages(doc.Entities)
+}
+
+// linkImages gives the Image entities among entities the handles linking
+// them to their ImageDef.
+func (w *Writer) linkImages(entities []Entity) {
+	for _, e := range entities {
+		img, ok := e.(*Image)
+		if !ok {
+			continue
+		}
+		defHandle, ok := w.imageDefHandles[img.ImageDef]
+		if !ok {
+			continue
+		}
+		img.handle = w.getHandle()
+		img.reactorHandle = w.getHandle()
+		img.defHandle = defHandle
+		w.images = append(w.images, img)
+	}
+}
+
+// assignDimensionBlocks names an anonymous "*D<n>" block for every Dimension
+// (including those inside blocks) that has Geometry but no BlockName. The
+// blocks are written after the document's own blocks. It returns the names
+// to use for further dimensions.
+func (w *Writer) assignDimensionBlocks(doc *Document) dimBlockNames {
+	w.dimBlocks = nil
+
+	names := dimBlockNames{used: make(map[string]bool)}
+	for _, block := range doc.Blocks {
+		names.used[strings.ToUpper(block.Name)] = true
+	}
+
+	for _, block := range doc.Blocks {
+		w.dimBlocks = names.assign(w.dimBlocks, block.Entities)
+	}
+	w.dimBlocks = names.assign(w.dimBlocks, doc.Entities)
+	return names
+}
+
+// dimBlockNames hands out the names of anonymous dimension blocks, "*D1",
+// "*D2" and so on, skipping the names of the document's own blocks. Copies
+// hand out the same names.
+type dimBlockNames struct {
+	used map[string]bool
+	n    int
+}
+
+// assign names the block of every Dimension among entities that has
+// Geometry but no BlockName, and appends the blocks to blocks.
+func (names *dimBlockNames) assign(blocks []Block, entities []Entity) []Block {
+	for _, e := range entities {
+		dim, ok := e.(*Dimension)
+		if !ok || dim.BlockName != "" || len(dim.Geometry) == 0 {
+			continue
+		}
+		name := ""
+		for name == "" || names.used[name] {
+			names.n++
+			name = fmt.Sprintf("*D%d", names.n)
+		}
+		dim.blockName = name
+		blocks = append(blocks, Block{Name: name, Entities: dim.Geometry})
+	}
+	return blocks
+}
+
+// writeClasses writes the CLASSES section declaring the raster image classes.
+func (w *Writer) writeClasses() error {
+	if err := w.writeSection("CLASSES"); err != nil {
+		return err
+	}
+
+	classes := []struct {
+		name, cppName string
+		isEntity      bool
+	}{
+		{"IMAGE", "AcDbRasterImage", true},
+		{"IMAGEDEF", "AcDbRasterImageDef", false},
+		{"IMAGEDEF_REACTOR", "AcDbRasterImageDefReactor", false},
+	}
+
+	for _, c := range classes {
+		isEntity := 0
+		if c.isEntity {
+			isEntity = 1
+		}
+		for _, gc := range []GroupCode{
+			{0, "CLASS"},
+			{1, c.name},
+			{2, c.cppName},
+			{3, "ISM"},
+			{90, 127}, // Proxy capabilities
+			{280, 0},
+			{281, isEntity},
+		} {
+			if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.writeEndSection()
+}
+
+// writeObjects writes the OBJECTS section: the root dictionary, the
+// ACAD_IMAGE_DICT dictionary, and an IMAGEDEF per image definition followed
+// by the IMAGEDEF_REACTOR of every image that references it.
+func (w *Writer) writeObjects(doc *Document) error {
+	if err := w.writeSection("OBJECTS"); err != nil {
+		return err
+	}
+
+	rootHandle := w.getHandle()
+	dictHandle := w.getHandle()
+
+	codes := []GroupCode{
+		{0, "DICTIONARY"},
+		{5, rootHandle},
+		{330, "0"},
+		{100, "AcDbDictionary"},
+		{281, 1},
+		{3, "ACAD_IMAGE_DICT"},
+		{350, dictHandle},
+		{0, "DICTIONARY"},
+		{5, dictHandle},
+		{330, rootHandle},
+		{100, "AcDbDictionary"},
+		{281, 1},
+	}
+	for _, def := range doc.ImageDefs {
+		codes = append(codes,
+			GroupCode{3, EscapeUnicode(def.Name)},
+			GroupCode{350, w.imageDefHandles[def.Name]},
+		)
+	}
+
+	for _, def := range doc.ImageDefs {
+		defHandle := w.imageDefHandles[def.Name]
+		var reactors []*Image
+		for _, img := range w.images {
+			if img.defHandle == defHandle {
+				reactors = append(reactors, img)
+			}
+		}
+
+		codes = append(codes,
+			GroupCode{0, "IMAGEDEF"},
+			GroupCode{5, defHandle},
+		)
+		if len(reactors) > 0 {
+			codes = append(codes, GroupCode{102, "{ACAD_REACTORS"})
+			for _, img := range reactors {
+				codes = append(codes, GroupCode{330, img.reactorHandle})
+			}
+			codes = append(codes, GroupCode{102, "}"})
+		}
+		codes = append(codes,
+			GroupCode{330, dictHandle},
+			GroupCode{100, "AcDbRasterImageDef"},
+			GroupCode{90, 0},
+			GroupCode{1, EscapeUnicode(def.FileName)},
+			GroupCode{10, float64(def.Width)},
+			GroupCode{20, float64(def.Height)},
+			GroupCode{11, 1.0}, // Default pixel size
+			GroupCode{21, 1.0},
+			GroupCode{280, 1}, // Image is loaded
+			GroupCode{281, 0}, // No resolution units
+		)
+
+		for _, img := range reactors {
+			codes = append(codes,
+				GroupCode{0, "IMAGEDEF_REACTOR"},
+				GroupCode{5, img.reactorHandle},
+				GroupCode{330, img.handle},
+				GroupCode{100, "AcDbRasterImageDefReactor"},
+				GroupCode{90, 2},
+				GroupCode{330, img.handle},
+			)
+		}
+	}
+
+	for _, gc := range codes {
+		if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
+			return err
+		}
+	}
+
+	return w.writeEndSection()
+}
+
 func (w *Writer) writeHeader() error {
 	// Header section with essential variables for ODA compatibility
 	if err := w.writeSection("HEADER"); err != nil {
@@ -162,7 +443,7 @@ func (w *Writer) writeTables(doc *Document) error {
 	}
 
 	// LTYPE table
-	if err := w.writeLinetypeTable(); err != nil {
+	if err := w.writeLinetypeTable(doc); err != nil {
 		return err
 	}
 
@@ -172,32 +453,55 @@ func (w *Writer) writeTables(doc *Document) error {
 	}
 
 	// STYLE table (text styles)
-	if err := w.writeStyleTable(); err != nil {
+	if err := w.writeStyleTable(doc); err != nil {
+		return err
+	}
+
+	// APPID table (extended data applications)
+	if err := w.writeAppIDTable(); err != nil {
+		return err
+	}
+
+	// DIMSTYLE table (dimension styles)
+	if err := w.writeDimStyleTable(doc); err != nil {
 		return err
 	}
 
 	return w.writeEndSection()
 }
 
-func (w *Writer) writeLinetypeTable() error {
-	type linetypeDef struct {
-		name   string
-		desc   string
-		values []float64
-	}
+// DefaultLineTypes are the line types always written to the LTYPE table,
+// after BYLAYER, BYBLOCK and CONTINUOUS. Document.LineTypes are appended to
+// them; entries whose names are already present are skipped.
+var DefaultLineTypes = []LineType{
+	{"DASHED", "Dashed line", []float64{0.6, -0.3}},
+	{"DASHEDX2", "Dashed line x2", []float64{1.2, -0.6}},
+	{"DASHDOT", "Dash dot", []float64{0.6, -0.2, 0.1, -0.2}},
+	{"DASHDOTX2", "Dash dot x2", []float64{1.2, -0.4, 0.2, -0.4}},
+	{"CENTER", "Center line", []float64{1.25, -0.25, 0.25, -0.25}},
+	{"CENTERX2", "Center line x2", []float64{2.5, -0.5, 0.5, -0.5}},
+	{"DOT", "Dotted line", []float64{0.1, -0.1}},
+	{"DOTX2", "Dotted line x2", []float64{0.2, -0.2}},
+}
 
-	linetypes := []linetypeDef{
+func (w *Writer) writeLinetypeTable(doc *Document) error {
+	linetypes := []LineType{
 		{"BYLAYER", "", nil},
 		{"BYBLOCK", "", nil},
 		{"CONTINUOUS", "Solid line", nil},
-		{"DASHED", "Dashed line", []float64{0.6, -0.3}},
-		{"DASHEDX2", "Dashed line x2", []float64{1.2, -0.6}},
-		{"DASHDOT", "Dash dot", []float64{0.6, -0.2, 0.1, -0.2}},
-		{"DASHDOTX2", "Dash dot x2", []float64{1.2, -0.4, 0.2, -0.4}},
-		{"CENTER", "Center line", []float64{1.25, -0.25, 0.25, -0.25}},
-		{"CENTERX2", "Center line x2", []float64{2.5, -0.5, 0.5, -0.5}},
-		{"DOT", "Dotted line", []float64{0.1, -0.1}},
-		{"DOTX2", "Dotted line x2", []float64{0.2, -0.2}},
+	}
+	linetypes = append(linetypes, DefaultLineTypes...)
+
+	seen := make(map[string]bool)
+	for _, lt := range linetypes {
+		seen[strings.ToUpper(lt.Name)] = true
+	}
+	for _, lt := range doc.LineTypes {
+		if lt.Name == "" || seen[strings.ToUpper(lt.Name)] {
+			continue
+		}
+		seen[strings.ToUpper(lt.Name)] = true
+		linetypes = append(linetypes, lt)
 	}
 
 	if err := w.writeGroupCode(0, "TABLE"); err != nil {
@@ -220,30 +524,30 @@ func (w *Writer) writeLinetypeTable() error {
 		if err := w.writeGroupCode(5, w.getHandle()); err != nil {
 			return err
 		}
-		if err := w.writeGroupCode(2, lt.name); err != nil {
+		if err := w.writeGroupCode(2, EscapeUnicode(lt.Name)); err != nil {
 			return err
 		}
 		if err := w.writeGroupCode(70, 0); err != nil {
 			return err
 		}
-		if err := w.writeGroupCode(3, lt.desc); err != nil {
+		if err := w.writeGroupCode(3, EscapeUnicode(lt.Description)); err != nil {
 			return err
 		}
 		if err := w.writeGroupCode(72, 65); err != nil {
 			return err
 		}
-		segmentCount := len(lt.values)
+		segmentCount := len(lt.Pattern)
 		if err := w.writeGroupCode(73, segmentCount); err != nil {
 			return err
 		}
 		patternLength := 0.0
-		for _, v := range lt.values {
+		for _, v := range lt.Pattern {
 			patternLength += math.Abs(v)
 		}
 		if err := w.writeGroupCode(40, patternLength); err != nil {
 			return err
 		}
-		for _, v := range lt.values {
+		for _, v := range lt.Pattern {
 			if err := w.writeGroupCode(49, v); err != nil {
 				return err
 			}
@@ -313,12 +617,36 @@ func (w *Writer) writeLayerTable(doc *Document) error {
 		if err := w.writeGroupCode(6, layer.LineType); err != nil {
 			return err
 		}
+		lineWeight := layer.LineWeight
+		if lineWeight == 0 {
+			lineWeight = LineWeightDefault
+		}
+		if err := w.writeGroupCode(370, lineWeight); err != nil {
+			return err
+		}
 	}
 
 	return w.writeGroupCode(0, "ENDTAB")
 }
 
-func (w *Writer) writeStyleTable() error {
+// TrueType style flags stored in the ACAD extended data (group code 1071)
+// of a STYLE entry.
+const (
+	fontFlagItalic = 0x1000000
+	fontFlagBold   = 0x2000000
+)
+
+func (w *Writer) writeStyleTable(doc *Document) error {
+	styles := []TextStyle{{Name: "STANDARD", Font: "txt"}}
+	seen := map[string]bool{"STANDARD": true}
+	for _, st := range doc.TextStyles {
+		if st.Name == "" || seen[strings.ToUpper(st.Name)] {
+			continue
+		}
+		seen[strings.ToUpper(st.Name)] = true
+		styles = append(styles, st)
+	}
+
 	if err := w.writeGroupCode(0, "TABLE"); err != nil {
 		return err
 	}
@@ -328,97 +656,247 @@ func (w *Writer) writeStyleTable() error {
 	if err := w.writeGroupCode(5, w.getHandle()); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(70, 1); err != nil {
+	if err := w.writeGroupCode(70, len(styles)); err != nil {
 		return err
 	}
 
-	// STANDARD style
-	if err := w.writeGroupCode(0, "STYLE"); err != nil {
-		return err
+	for _, st := range styles {
+		widthFactor := st.WidthFactor
+		if widthFactor == 0 {
+			widthFactor = 1
+		}
+		lastHeight := st.Height
+		if lastHeight == 0 {
+			lastHeight = 2.5
+		}
+
+		codes := []GroupCode{
+			{0, "STYLE"},
+			{5, w.getHandle()},
+			{2, EscapeUnicode(st.Name)},
+			{70, 0},
+			{40, st.Height},
+			{41, widthFactor},
+			{50, st.ObliqueAngle},
+			{71, 0},
+			{42, lastHeight},
+			{3, EscapeUnicode(st.Font)},
+			{4, EscapeUnicode(st.BigFont)},
+		}
+		if st.FontFamily != "" {
+			flags := 0
+			if st.Italic {
+				flags |= fontFlagItalic
+			}
+			if st.Bold {
+				flags |= fontFlagBold
+			}
+			codes = append(codes,
+				GroupCode{1001, "ACAD"},
+				GroupCode{1000, EscapeUnicode(st.FontFamily)},
+				GroupCode{1071, flags},
+			)
+		}
+		for _, gc := range codes {
+			if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
+				return err
+			}
+		}
 	}
-	if err := w.writeGroupCode(5, w.getHandle()); err != nil {
-		return err
+
+	return w.writeGroupCode(0, "ENDTAB")
+}
+
+// writeAppIDTable registers the ACAD application name used by extended data.
+func (w *Writer) writeAppIDTable() error {
+	codes := []GroupCode{
+		{0, "TABLE"},
+		{2, "APPID"},
+		{5, w.getHandle()},
+		{70, 1},
+		{0, "APPID"},
+		{5, w.getHandle()},
+		{2, "ACAD"},
+		{70, 0},
+		{0, "ENDTAB"},
+	}
+	for _, gc := range codes {
+		if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
+			return err
+		}
 	}
-	if err := w.writeGroupCode(2, "STANDARD"); err != nil {
-		return err
+	return nil
+}
+
+// StandardDimStyle is the STANDARD dimension style always written to the
+// DIMSTYLE table, using the AutoCAD metric defaults. Document.DimStyles are
+// appended to it; entries whose names are already present are skipped.
+var StandardDimStyle = DimStyle{
+	Name:             "STANDARD",
+	ArrowSize:        2.5,
+	ExtLineOffset:    0.625,
+	ExtLineExtension: 1.25,
+	TextHeight:       2.5,
+	TextGap:          0.625,
+	Decimals:         2,
+}
+
+func (w *Writer) writeDimStyleTable(doc *Document) error {
+	styles := []DimStyle{StandardDimStyle}
+	seen := map[string]bool{"STANDARD": true}
+	for _, ds := range doc.DimStyles {
+		if ds.Name == "" || seen[strings.ToUpper(ds.Name)] {
+			continue
+		}
+		seen[strings.ToUpper(ds.Name)] = true
+		styles = append(styles, ds)
 	}
-	if err := w.writeGroupCode(70, 0); err != nil {
+
+	codes := []GroupCode{
+		{0, "TABLE"},
+		{2, "DIMSTYLE"},
+		{5, w.getHandle()},
+		{70, len(styles)},
+	}
+	for _, ds := range styles {
+		codes = append(codes, dimStyleCodes(ds, w.getHandle())...)
+	}
+	codes = append(codes, GroupCode{0, "ENDTAB"})
+
+	for _, gc := range codes {
+		if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dimStyleCodes returns the DIMSTYLE table entry for a dimension style.
+// Dimension styles carry their handle in group code 105.
+func dimStyleCodes(ds DimStyle, handle string) []GroupCode {
+	scale := ds.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	linearFactor := ds.LinearFactor
+	if linearFactor == 0 {
+		linearFactor = 1
+	}
+	orDefault := func(v, def float64) float64 {
+		if v == 0 {
+			return def
+		}
+		return v
+	}
+	textAbove := 0
+	if ds.TextAbove {
+		textAbove = 1
+	}
+	zeroSuppression := 0
+	if ds.SuppressTrailingZeros {
+		zeroSuppression = 8
+	}
+
+	codes := []GroupCode{
+		{0, "DIMSTYLE"},
+		{105, handle},
+		{2, EscapeUnicode(ds.Name)},
+		{70, 0},
+	}
+	if ds.Suffix != "" {
+		codes = append(codes, GroupCode{3, "<>" + EscapeUnicode(ds.Suffix)})
+	}
+	if ds.ArrowBlock != "" {
+		codes = append(codes, GroupCode{5, ds.ArrowBlock})
+	}
+	return append(codes,
+		GroupCode{40, scale},
+		GroupCode{41, orDefault(ds.ArrowSize, StandardDimStyle.ArrowSize)},
+		GroupCode{42, ds.ExtLineOffset},
+		GroupCode{44, ds.ExtLineExtension},
+		GroupCode{73, 0}, // Text aligned with the dimension line inside
+		GroupCode{74, 0}, // and outside the extension lines
+		GroupCode{77, textAbove},
+		GroupCode{78, zeroSuppression},
+		GroupCode{140, orDefault(ds.TextHeight, StandardDimStyle.TextHeight)},
+		GroupCode{144, linearFactor},
+		GroupCode{147, ds.TextGap},
+		GroupCode{176, ds.LineColor},
+		GroupCode{177, ds.ExtLineColor},
+		GroupCode{178, ds.TextColor},
+		GroupCode{179, ds.AngleDecimals},
+		GroupCode{271, ds.Decimals},
+		GroupCode{275, ds.AngleUnit},
+		GroupCode{278, int('.')},
+	)
+}
+
+func (w *Writer) writeBlocks(doc *Document) error {
+	if err := w.writeSection("BLOCKS"); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(40, 0.0); err != nil {
+
+	blocks := append(doc.Blocks[:len(doc.Blocks):len(doc.Blocks)], w.dimBlocks...)
+	for _, block := range blocks {
+		if err := w.writeBlock(block); err != nil {
+			return err
+		}
+	}
+
+	return w.writeEndSection()
+}
+
+// writeBlock writes a block definition with its entities.
+func (w *Writer) writeBlock(block Block) error {
+	// Anonymous blocks ("*D1") are flagged as such
+	flags := 0
+	if strings.HasPrefix(block.Name, "*") {
+		flags = 1
+	}
+
+	// Block header
+	if err := w.writeGroupCode(0, "BLOCK"); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(41, 1.0); err != nil {
+	if err := w.writeGroupCode(8, "0"); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(50, 0.0); err != nil {
+	if err := w.writeGroupCode(2, EscapeUnicode(block.Name)); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(71, 0); err != nil {
+	if err := w.writeGroupCode(70, flags); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(42, 2.5); err != nil {
+	if err := w.writeGroupCode(10, block.BaseX); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(3, "txt"); err != nil {
+	if err := w.writeGroupCode(20, block.BaseY); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(4, ""); err != nil {
+	if err := w.writeGroupCode(30, 0.0); err != nil {
 		return err
 	}
-
-	return w.writeGroupCode(0, "ENDTAB")
-}
-
-func (w *Writer) writeBlocks(doc *Document) error {
-	if err := w.writeSection("BLOCKS"); err != nil {
+	if err := w.writeGroupCode(3, EscapeUnicode(block.Name)); err != nil {
 		return err
 	}
-
-	for _, block := range doc.Blocks {
-		// Block header
-		if err := w.writeGroupCode(0, "BLOCK"); err != nil {
-			return err
-		}
-		if err := w.writeGroupCode(8, "0"); err != nil {
-			return err
-		}
-		if err := w.writeGroupCode(2, block.Name); err != nil {
+	if block.Description != "" {
+		if err := w.writeGroupCode(4, EscapeUnicode(block.Description)); err != nil {
 			return err
 		}
-		if err := w.writeGroupCode(70, 0); err != nil {
-			return err
-		}
-		if err := w.writeGroupCode(10, block.BaseX); err != nil {
-			return err
-		}
-		if err := w.writeGroupCode(20, block.BaseY); err != nil {
-			return err
-		}
-		if err := w.writeGroupCode(30, 0.0); err != nil {
-			return err
-		}
-		if err := w.writeGroupCode(3, block.Name); err != nil {
-			return err
-		}
-
-		// Block entities
-		for _, entity := range block.Entities {
-			if err := w.writeEntity(entity); err != nil {
-				return err
-			}
-		}
+	}
 
-		// Block end
-		if err := w.writeGroupCode(0, "ENDBLK"); err != nil {
-			return err
-		}
-		if err := w.writeGroupCode(8, "0"); err != nil {
+	// Block entities
+	for _, entity := range block.Entities {
+		if err := w.writeEntity(entity); err != nil {
 			return err
 		}
 	}
 
-	return w.writeEndSection()
+	// Block end
+	if err := w.writeGroupCode(0, "ENDBLK"); err != nil {
+		return err
+	}
+	return w.writeGroupCode(8, "0")
 }
 
 func (w *Writer) writeEntities(doc *Document) error {
@@ -430,6 +908,7 @@ func (w *Writer) writeEntities(doc *Document) error {
 		if err := w.writeEntity(entity); err != nil {
 			return err
 		}
+		w.countEntity()
 	}
 
 	return w.writeEndSection()
@@ -475,7 +954,8 @@ func (w *Writer) writeGroupCode(code int, value interface{}) error {
 	default:
 		line = fmt.Sprintf("%3d\n%v\n", code, v)
 	}
-	_, err := io.WriteString(w.w, line)
+	n, err := io.WriteString(w.w, line)
+	w.written += int64(n)
 	return err
 }
 
@@ -493,3 +973,27 @@ func ToString(doc *Document) string {
 	_ = w.WriteDocument(doc)
 	return sb.String()
 }
+
+// WriteImageFiles writes the content of every ImageDef that carries Data or
+// a Load function to dir, using the ImageDef's FileName. Images are loaded
+// one at a time. Write the DXF file into the same directory so the relative
+// image paths resolve.
+func WriteImageFiles(doc *Document, dir string) error {
+	for _, def := range doc.ImageDefs {
+		data := def.Data
+		if data == nil && def.Load != nil {
+			var err error
+			if data, err = def.Load(); err != nil {
+				return fmt.Errorf("writing image %s: %w", def.FileName, err)
+			}
+		}
+		if data == nil {
+			continue
+		}
+		path := filepath.Join(dir, filepath.Base(def.FileName))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("writing image %s: %w", def.FileName, err)
+		}
+	}
+	return nil
+}
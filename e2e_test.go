@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -49,19 +50,66 @@ func TestE2E_ConvertSampleFile(t *testing.T) {
 		t.Error("expected some entities")
 	}
 
-	// Verify entity types are valid DXF types
-	for i, e := range dxfDoc.Entities {
-		entityType := e.EntityType()
-		validTypes := []string{"LINE", "CIRCLE", "ARC", "ELLIPSE", "POINT", "TEXT", "SOLID", "INSERT"}
-		valid := false
-		for _, vt := range validTypes {
-			if entityType == vt {
-				valid = true
-				break
+	checkEntityTypes(t, dxfDoc)
+}
+
+// validDXFTypes are the DXF entity types the converter writes.
+var validDXFTypes = []string{
+	"LINE", "CIRCLE", "ARC", "ELLIPSE", "POINT", "TEXT", "MTEXT", "SOLID",
+	"HATCH", "DIMENSION", "INSERT", "IMAGE",
+}
+
+// checkEntityTypes reports an error for every entity of doc, in the entity
+// section or a block, whose type is not in validDXFTypes.
+func checkEntityTypes(t *testing.T, doc *dxf.Document) {
+	t.Helper()
+	for i, e := range doc.Entities {
+		if !slices.Contains(validDXFTypes, e.EntityType()) {
+			t.Errorf("entity %d has invalid DXF type: %s", i, e.EntityType())
+		}
+	}
+	for _, b := range doc.Blocks {
+		for i, e := range b.Entities {
+			if !slices.Contains(validDXFTypes, e.EntityType()) {
+				t.Errorf("block %s entity %d has invalid DXF type: %s", b.Name, i, e.EntityType())
 			}
 		}
-		if !valid {
-			t.Errorf("entity %d has invalid DXF type: %s", i, entityType)
+	}
+}
+
+// TestE2E_EntityTypes converts entities of each kind without a sample file.
+func TestE2E_EntityTypes(t *testing.T) {
+	hatch := func(y float64) *jww.Line {
+		return &jww.Line{EntityBase: jww.EntityBase{Flag: 0x0020}, StartX: 0, StartY: y, EndX: 10, EndY: y}
+	}
+	jwwDoc := &jww.Document{
+		Version: 700,
+		Entities: []jww.Entity{
+			&jww.Line{StartX: 0, StartY: 0, EndX: 10, EndY: 0},
+			&jww.Line{StartX: 10, StartY: 0, EndX: 10, EndY: 10},
+			&jww.Line{StartX: 10, StartY: 10, EndX: 0, EndY: 10},
+			&jww.Line{StartX: 0, StartY: 10, EndX: 0, EndY: 0},
+			hatch(2), hatch(4), hatch(6), hatch(8),
+			&jww.Arc{CenterX: 5, CenterY: 5, Radius: 3, Flatness: 1, IsFullCircle: true},
+			&jww.Text{StartX: 0, StartY: 20, EndX: 10, EndY: 20, SizeX: 2, SizeY: 2, Content: "TEXT"},
+			&jww.Solid{Point1X: 20, Point2X: 30, Point3X: 30, Point3Y: 10, Point4X: 20, Point4Y: 10},
+			&jww.Dimension{
+				Line: jww.Line{StartX: 0, StartY: -5, EndX: 10, EndY: -5},
+				Text: jww.Text{EntityBase: jww.EntityBase{Flag: 0x0010}, Content: "10"},
+			},
+		},
+	}
+
+	dxfDoc := dxf.ConvertDocument(jwwDoc)
+	checkEntityTypes(t, dxfDoc)
+
+	types := make(map[string]bool)
+	for _, e := range dxfDoc.Entities {
+		types[e.EntityType()] = true
+	}
+	for _, want := range []string{"LINE", "CIRCLE", "TEXT", "HATCH", "DIMENSION"} {
+		if !types[want] {
+			t.Errorf("expected a %s entity, got %v", want, types)
 		}
 	}
 }
@@ -179,6 +227,7 @@ func TestE2E_ConvertAllFiles(t *testing.T) {
 				return
 			}
 
+			checkEntityTypes(t, dxfDoc)
 			successCount++
 			t.Logf("SUCCESS: %d entities converted", len(dxfDoc.Entities))
 		})
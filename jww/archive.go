@@ -0,0 +1,589 @@
+package jww
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// MFC CArchive object tags, as read by CArchive::ReadObject.
+const (
+	// archiveNullTag marks a null object pointer.
+	archiveNullTag = 0x0000
+
+	// archiveNewClassTag announces a class definition (schema and name)
+	// followed by a new object of that class.
+	archiveNewClassTag = 0xFFFF
+
+	// archiveClassTag is set on tags referring to a class read earlier; the
+	// lower bits are its index. Tags without it refer back to an object.
+	archiveClassTag = 0x8000
+
+	// archiveBigObjectTag announces a 32-bit tag, used once the index no
+	// longer fits in 15 bits.
+	archiveBigObjectTag = 0x7FFF
+
+	// archiveBigClassTag is the class flag of a 32-bit tag.
+	archiveBigClassTag = 0x80000000
+)
+
+// archiveClass is a class definition (CRuntimeClass) read from the archive.
+type archiveClass struct {
+	name   string
+	schema uint16
+}
+
+// knownClasses are the classes decoded by archive.decode.
+var knownClasses = map[string]bool{
+	"CDataSen":    true,
+	"CDataEnko":   true,
+	"CDataTen":    true,
+	"CDataMoji":   true,
+	"CDataSolid":  true,
+	"CDataBlock":  true,
+	"CDataSunpou": true,
+	"CDataList":   true,
+}
+
+// archive decodes the objects of an MFC CArchive. Classes and objects share a
+// single table of loaded items, indexed from 1 in the order they are read
+// across the whole archive: the entity list, the block definitions and the
+// entity lists nested in them. Tags refer to this table to reuse a class or
+// to refer back to an object read earlier.
+//
+// The archive also tracks the position being read (section, block definition
+// and entity index) to locate errors and warnings, and handles damaged and
+// suspicious data according to the parse mode.
+type archive struct {
+	jr      *Reader
+	version uint32
+	loaded  []any
+
+	mode     ParseMode
+	opts     ParseOptions
+	warnings []Warning
+
+	// Objects and entities read so far, and the nesting of the block
+	// definition being read, checked against the limits of opts
+	objects  int
+	entities int
+	depth    int
+
+	// Position being read, reported in errors and warnings
+	section  Section
+	blockDef int
+	index    int
+
+	// data is the whole file, scanned to resynchronize in lenient mode. The
+	// block definition list is known to start at blockDefListAt, or -1.
+	data           []byte
+	blockDefListAt int64
+
+	// release drops the entities of the top-level entity list from the
+	// table once they are read, so that the Decoder does not hold them all
+	release bool
+
+	// size is the size of the file, reported as Progress.TotalBytes
+	size int64
+}
+
+// releasedEntity takes the place in the table of an entity dropped by
+// archive.release.
+type releasedEntity struct{}
+
+// newArchive creates an archive decoder reading objects of a file of the
+// given version.
+func newArchive(jr *Reader, version uint32) *archive {
+	return &archive{
+		jr:      jr,
+		version: version,
+		// Index 0 is the null object
+		loaded:         []any{nil},
+		opts:           ParseOptions{}.withDefaults(),
+		blockDef:       -1,
+		index:          -1,
+		blockDefListAt: -1,
+	}
+}
+
+// setOptions applies the mode, limits and context of opts, whose defaults
+// must be set.
+func (a *archive) setOptions(opts ParseOptions) {
+	a.mode = opts.Mode
+	a.opts = opts
+	a.jr.checkValues = opts.Mode == Strict
+	a.jr.maxStringLen = opts.MaxStringLen
+}
+
+// error returns err as a *ParseError located at the position being read.
+func (a *archive) error(err error) *ParseError {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return pe
+	}
+	pe = asParseError(err, a.jr.lastOffset)
+	pe.Section = a.section
+	pe.BlockDef = a.blockDef
+	pe.Index = a.index
+	return pe
+}
+
+// warn records a problem that did not stop parsing.
+func (a *archive) warn(err error) {
+	a.warnings = append(a.warnings, a.error(err).warning())
+}
+
+// suspicious reports suspicious data: it is an error in strict mode and a
+// warning otherwise, in which case nil is returned.
+func (a *archive) suspicious(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	if a.mode == Strict {
+		return a.error(err)
+	}
+	a.warn(err)
+	return nil
+}
+
+// readObject reads one object (CArchive::ReadObject). It returns nil for a
+// null object, and the object read earlier for a back-reference, in which
+// case ref is true. Errors are returned as a *ParseError carrying the offset
+// and the class of the object.
+func (a *archive) readObject() (obj any, ref bool, err error) {
+	if a.objects++; a.objects%checkInterval == 0 {
+		if err := a.opts.Context.Err(); err != nil {
+			return nil, false, a.error(err)
+		}
+	}
+
+	obj, class, err := a.readTaggedObject()
+	if err != nil {
+		pe := a.error(err)
+		if pe.Class == "" && class != nil {
+			pe.Class = class.name
+		}
+		return nil, false, pe
+	}
+	return obj, obj != nil && class == nil, nil
+}
+
+// readTaggedObject reads an object tag and the object it introduces,
+// returning the class of the object when it is new.
+func (a *archive) readTaggedObject() (any, *archiveClass, error) {
+	tag, err := a.jr.ReadWORD()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var obTag uint32
+	if tag == archiveBigObjectTag {
+		if obTag, err = a.jr.ReadDWORD(); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		obTag = uint32(tag&archiveClassTag)<<16 | uint32(tag&^archiveClassTag)
+	}
+
+	var class *archiveClass
+	switch {
+	case tag == archiveNewClassTag:
+		if class, err = a.readClass(); err != nil {
+			return nil, nil, err
+		}
+	case obTag&archiveBigClassTag != 0:
+		if class, err = a.class(obTag &^ archiveBigClassTag); err != nil {
+			return nil, nil, err
+		}
+	case obTag == archiveNullTag:
+		return nil, nil, nil
+	default:
+		obj, err := a.object(obTag)
+		return obj, nil, err
+	}
+
+	// Like MFC, register the object before reading its contents so that the
+	// objects it contains get the following indices
+	index := len(a.loaded)
+	a.loaded = append(a.loaded, nil)
+
+	obj, err := a.decode(class)
+	if err != nil {
+		return nil, class, err
+	}
+	a.loaded[index] = obj
+	return obj, class, nil
+}
+
+// readClass reads a class definition following archiveNewClassTag and adds
+// it to the table.
+func (a *archive) readClass() (*archiveClass, error) {
+	schema, err := a.jr.ReadWORD()
+	if err != nil {
+		return nil, err
+	}
+
+	nameLen, err := a.jr.ReadWORD()
+	if err != nil {
+		return nil, err
+	}
+
+	nameBuf := make([]byte, nameLen)
+	if err := a.jr.ReadBytes(nameBuf); err != nil {
+		return nil, err
+	}
+
+	class := &archiveClass{name: string(nameBuf), schema: schema}
+	a.loaded = append(a.loaded, class)
+	return class, nil
+}
+
+// class returns the class read earlier at the given index.
+func (a *archive) class(index uint32) (*archiveClass, error) {
+	if index >= uint32(len(a.loaded)) {
+		return nil, fmt.Errorf("unknown class index: %d (%d items loaded)", index, len(a.loaded)-1)
+	}
+	class, ok := a.loaded[index].(*archiveClass)
+	if !ok {
+		return nil, fmt.Errorf("index %d refers to an object, not a class", index)
+	}
+	return class, nil
+}
+
+// object returns the object read earlier at the given index.
+func (a *archive) object(index uint32) (any, error) {
+	if index >= uint32(len(a.loaded)) {
+		return nil, fmt.Errorf("unknown object index: %d (%d items loaded)", index, len(a.loaded)-1)
+	}
+	switch obj := a.loaded[index].(type) {
+	case *archiveClass:
+		return nil, fmt.Errorf("index %d refers to class %s, not an object", index, obj.name)
+	case nil:
+		return nil, fmt.Errorf("index %d refers to an object still being read", index)
+	case releasedEntity:
+		return nil, fmt.Errorf("index %d refers to an entity no longer held by the decoder", index)
+	default:
+		return obj, nil
+	}
+}
+
+// decode reads the contents of a new object of the given class.
+func (a *archive) decode(class *archiveClass) (any, error) {
+	var entity Entity
+	var err error
+	switch class.name {
+	case "CDataSen":
+		entity, err = parseLine(a.jr, a.version)
+	case "CDataEnko":
+		entity, err = parseArc(a.jr, a.version)
+	case "CDataTen":
+		entity, err = parsePoint(a.jr, a.version)
+	case "CDataMoji":
+		entity, err = parseTextEntity(a.jr, a.version)
+	case "CDataSolid":
+		entity, err = parseSolid(a.jr, a.version)
+	case "CDataBlock":
+		entity, err = parseBlock(a.jr, a.version)
+	case "CDataSunpou":
+		entity, err = parseDimension(a.jr, a.version)
+	case "CDataList":
+		if a.depth >= a.opts.MaxBlockDepth {
+			return nil, fmt.Errorf("%w: limit %d", ErrBlockTooDeep, a.opts.MaxBlockDepth)
+		}
+		a.depth++
+		defer func() { a.depth-- }()
+		return parseBlockDef(a)
+	default:
+		return nil, fmt.Errorf("unknown class: %s", class.name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if base := entity.Base(); base.Layer > 15 || base.LayerGroup > 15 {
+		if err := a.suspicious("layer %X-%X out of range", base.LayerGroup, base.Layer); err != nil {
+			return nil, err
+		}
+	}
+
+	setAttributes(entity)
+	return entity, nil
+}
+
+// readEntityList reads a list of entities (CTypedPtrList of CData) stored as
+// an object count followed by the objects. Errors are returned as a
+// *ParseError locating the failing entity.
+func (a *archive) readEntityList() ([]Entity, error) {
+	var entities []Entity
+	err := a.eachEntity(func(entity Entity) bool {
+		entities = append(entities, entity)
+		return true
+	})
+	return entities, err
+}
+
+// eachEntity reads a list of entities like readEntityList, calling fn with
+// each entity as soon as it is read. Reading stops when fn returns false.
+//
+// In lenient mode a damaged entity is skipped by resynchronizing on the next
+// object of a known class. A list nested in a block definition ends early
+// when that object is the next block definition.
+func (a *archive) eachEntity(fn func(Entity) bool) error {
+	count, err := a.jr.ReadCount()
+	if err != nil {
+		return a.error(err)
+	}
+	if err := a.checkCount(count); err != nil {
+		return a.error(err)
+	}
+
+	outer := a.index
+	defer func() { a.index = outer }()
+
+	recovered := false
+	for i := 0; ; i++ {
+		a.index = i
+
+		// The top-level list ends at the block definitions, which are
+		// located in advance in lenient mode; after a recovery the count
+		// is no longer reliable
+		at := a.jr.BytesRead()
+		if at == a.blockDefListAt {
+			if i < int(count) {
+				a.warn(fmt.Errorf("entity list ends after %d of %d entities", i, count))
+			}
+			break
+		}
+		if i >= int(count) && !(recovered && at < a.blockDefListAt) {
+			break
+		}
+
+		obj, ref, err := a.readObject()
+		if err == nil {
+			var entity Entity
+			if entity, err = a.listEntity(obj, ref); err == nil && entity != nil {
+				if err := a.count(); err != nil {
+					return a.error(err)
+				}
+				if a.release && a.blockDef < 0 && !ref && a.loaded[len(a.loaded)-1] == obj {
+					a.loaded[len(a.loaded)-1] = releasedEntity{}
+				}
+				if !fn(entity) {
+					return nil
+				}
+			}
+		}
+		if err == nil {
+			continue
+		}
+
+		if a.mode != Lenient || a.data == nil || exceedsLimit(err) {
+			return a.error(err)
+		}
+		a.warn(err)
+		recovered = true
+
+		limit := int64(len(a.data))
+		if a.blockDefListAt >= 0 && at < a.blockDefListAt {
+			limit = a.blockDefListAt
+		}
+		class := a.resync(at+1, limit, knownClasses)
+		if err := a.opts.Context.Err(); err != nil {
+			return a.error(err)
+		}
+		if class == "" || class == "CDataList" {
+			if a.blockDefListAt > at {
+				a.jr.seek(a.blockDefListAt)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// listEntity checks an object read from an entity list, which must be an
+// entity. It returns nil for null objects.
+func (a *archive) listEntity(obj any, ref bool) (Entity, error) {
+	if obj == nil {
+		return nil, a.suspicious("null object in entity list")
+	}
+	entity, ok := obj.(Entity)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %T in entity list", obj)
+	}
+	if ref {
+		if err := a.suspicious("entity list refers back to an entity read earlier"); err != nil {
+			return nil, err
+		}
+	}
+	return entity, nil
+}
+
+// readBlockDefList reads the list of block definitions (CTypedPtrList of
+// CDataList). Errors are returned as a *ParseError locating the failing
+// block definition. In lenient mode a damaged block definition is skipped by
+// resynchronizing on the next one.
+func (a *archive) readBlockDefList() ([]BlockDef, error) {
+	count, err := a.jr.ReadCount()
+	if err != nil {
+		return nil, a.error(err)
+	}
+	if err := a.checkCount(count); err != nil {
+		return nil, a.error(err)
+	}
+
+	defer func() { a.blockDef = -1 }()
+
+	blockDefs := make([]BlockDef, 0, min(count, 4096))
+	for i := 0; i < int(count); i++ {
+		a.blockDef = i
+		at := a.jr.BytesRead()
+
+		obj, ref, err := a.readObject()
+		if err == nil {
+			var bd *BlockDef
+			if bd, err = a.listBlockDef(obj, ref); err == nil && bd != nil {
+				if err := a.count(); err != nil {
+					return blockDefs, a.error(err)
+				}
+				blockDefs = append(blockDefs, *bd)
+			}
+		}
+		if err == nil {
+			continue
+		}
+
+		if a.mode != Lenient || a.data == nil || exceedsLimit(err) {
+			return blockDefs, a.error(err)
+		}
+		a.warn(err)
+		class := a.resync(at+1, int64(len(a.data)), map[string]bool{"CDataList": true})
+		if err := a.opts.Context.Err(); err != nil {
+			return blockDefs, a.error(err)
+		}
+		if class == "" {
+			break
+		}
+	}
+	return blockDefs, nil
+}
+
+// checkCount rejects a list of count entities or block definitions that
+// would exceed ParseOptions.MaxEntities, before it is read.
+func (a *archive) checkCount(count uint32) error {
+	if int64(a.entities)+int64(count) > int64(a.opts.MaxEntities) {
+		return fmt.Errorf("%w: list of %d after %d read, limit %d", ErrTooManyEntities, count, a.entities, a.opts.MaxEntities)
+	}
+	return nil
+}
+
+// count counts an entity or block definition read against
+// ParseOptions.MaxEntities. Lists may hold more items than their count after
+// a recovery in lenient mode.
+func (a *archive) count() error {
+	if a.entities >= a.opts.MaxEntities {
+		return fmt.Errorf("%w: limit %d", ErrTooManyEntities, a.opts.MaxEntities)
+	}
+	a.entities++
+	if a.entities%checkInterval == 0 {
+		a.progress()
+	}
+	return nil
+}
+
+// sectionStages are the progress stages of the sections of the file.
+var sectionStages = [...]ProgressStage{
+	SectionHeader:    StageHeader,
+	SectionEntities:  StageEntities,
+	SectionBlockDefs: StageBlockDefs,
+	SectionImages:    StageImages,
+}
+
+// progress reports the position reached in the section being read to
+// ParseOptions.Progress.
+func (a *archive) progress() {
+	a.opts.report(Progress{
+		Stage:      sectionStages[a.section],
+		Bytes:      a.jr.BytesRead(),
+		TotalBytes: a.size,
+		Entities:   a.entities,
+	})
+}
+
+// listBlockDef checks an object read from the block definition list, which
+// must be a block definition. It returns nil for null objects.
+func (a *archive) listBlockDef(obj any, ref bool) (*BlockDef, error) {
+	if obj == nil {
+		return nil, a.suspicious("null object in block definition list")
+	}
+	bd, ok := obj.(*BlockDef)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %T in block definition list", obj)
+	}
+	if ref {
+		if err := a.suspicious("block definition list refers back to a definition read earlier"); err != nil {
+			return nil, err
+		}
+	}
+	return bd, nil
+}
+
+// resync looks for the next object of one of the given classes between the
+// offsets from and limit that decodes in strict mode, and moves there. It
+// returns the class of the object, or "" when none was found.
+func (a *archive) resync(from, limit int64, classes map[string]bool) string {
+	for at := from; at+2 <= limit; at++ {
+		if (at-from)%checkInterval == 0 && a.opts.Context.Err() != nil {
+			return ""
+		}
+		class := a.tagClass(at)
+		if classes[class] && a.decodes(at) {
+			a.jr.seek(at)
+			return class
+		}
+	}
+	return ""
+}
+
+// tagClass returns the name of the known class introduced by the object tag
+// at the given offset, or "".
+func (a *archive) tagClass(at int64) string {
+	d := a.data[at:]
+	tag := binary.LittleEndian.Uint16(d)
+	switch {
+	case tag == archiveNewClassTag:
+		if len(d) < 6 {
+			return ""
+		}
+		n := int(binary.LittleEndian.Uint16(d[4:]))
+		if n > len(d)-6 || !knownClasses[string(d[6:6+n])] {
+			return ""
+		}
+		return string(d[6 : 6+n])
+	case tag&archiveClassTag != 0:
+		if class, err := a.class(uint32(tag &^ archiveClassTag)); err == nil {
+			return class.name
+		}
+	}
+	return ""
+}
+
+// decodes reports whether the object at the given offset decodes without
+// errors in strict mode. The archive is left as it was.
+func (a *archive) decodes(at int64) bool {
+	loaded, warnings := len(a.loaded), len(a.warnings)
+	mode, checkValues := a.mode, a.jr.checkValues
+	index, blockDef := a.index, a.blockDef
+	entities, depth := a.entities, a.depth
+	defer func() {
+		a.loaded, a.warnings = a.loaded[:loaded], a.warnings[:warnings]
+		a.mode, a.jr.checkValues = mode, checkValues
+		a.index, a.blockDef = index, blockDef
+		a.entities, a.depth = entities, depth
+	}()
+
+	a.mode, a.jr.checkValues = Strict, true
+	if a.jr.seek(at) != nil {
+		return false
+	}
+	_, _, err := a.readObject()
+	return err == nil
+}
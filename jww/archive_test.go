@@ -0,0 +1,403 @@
+package jww
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// archiveWriter builds MFC CArchive object streams for tests.
+type archiveWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *archiveWriter) word(v uint16)  { _ = binary.Write(&w.buf, binary.LittleEndian, v) }
+func (w *archiveWriter) dword(v uint32) { _ = binary.Write(&w.buf, binary.LittleEndian, v) }
+
+// newClass writes a class definition tag.
+func (w *archiveWriter) newClass(name string) {
+	w.word(0xFFFF)
+	w.word(600)
+	w.word(uint16(len(name)))
+	w.buf.WriteString(name)
+}
+
+// base writes the common entity fields with the given layer.
+func (w *archiveWriter) base(layer uint16) {
+	w.dword(0)
+	w.buf.WriteByte(1)
+	w.word(1)
+	w.word(1)
+	w.word(layer)
+	w.word(0)
+	w.word(0)
+}
+
+// line writes the body of a line on the given layer.
+func (w *archiveWriter) line(layer uint16) {
+	w.base(layer)
+	w.buf.Write(make([]byte, 4*8))
+}
+
+// blockDef writes the body of a block definition up to its entity list.
+func (w *archiveWriter) blockDef(number uint32, name string) {
+	w.base(0)
+	w.dword(number)
+	w.dword(0)
+	w.dword(0)
+	w.buf.WriteByte(byte(len(name)))
+	w.buf.WriteString(name)
+}
+
+func (w *archiveWriter) archive() *archive {
+	return newArchive(NewReader(bytes.NewReader(w.buf.Bytes())), 600)
+}
+
+func TestArchive_SharedTableAcrossLists(t *testing.T) {
+	w := &archiveWriter{}
+
+	// Entity list: class CDataSen is item 1, the lines items 2 and 3
+	w.word(2)
+	w.newClass("CDataSen")
+	w.line(1)
+	w.word(0x8001)
+	w.line(2)
+
+	// Block definitions: class CDataList is item 4, the definition item 5
+	w.word(1)
+	w.newClass("CDataList")
+	w.blockDef(1, "BLK")
+
+	// Nested entities reuse the class of the entity list, refer back to
+	// the first line (item 2) and contain a null object
+	w.word(3)
+	w.word(0x8001)
+	w.line(3)
+	w.word(0x0002)
+	w.word(0x0000)
+
+	a := w.archive()
+	entities, err := a.readEntityList()
+	if err != nil {
+		t.Fatalf("readEntityList failed: %v", err)
+	}
+	blockDefs, err := a.readBlockDefList()
+	if err != nil {
+		t.Fatalf("readBlockDefList failed: %v", err)
+	}
+
+	if len(entities) != 2 || entities[1].Base().Layer != 2 {
+		t.Fatalf("unexpected entities: %+v", entities)
+	}
+	if len(blockDefs) != 1 {
+		t.Fatalf("expected 1 block definition, got %d", len(blockDefs))
+	}
+
+	nested := blockDefs[0].Entities
+	if len(nested) != 2 {
+		t.Fatalf("expected 2 nested entities, got %d", len(nested))
+	}
+	if nested[0].Base().Layer != 3 {
+		t.Errorf("nested line layer: got %d, want 3", nested[0].Base().Layer)
+	}
+	if nested[1] != entities[0] {
+		t.Error("back-reference should return the first line of the entity list")
+	}
+}
+
+func TestArchive_BigTags(t *testing.T) {
+	w := &archiveWriter{}
+	w.word(3)
+	w.newClass("CDataSen")
+	w.line(1)
+
+	// 32-bit class reference to item 1
+	w.word(0x7FFF)
+	w.dword(0x80000001)
+	w.line(2)
+
+	// 32-bit back-reference to item 3
+	w.word(0x7FFF)
+	w.dword(3)
+
+	entities, err := w.archive().readEntityList()
+	if err != nil {
+		t.Fatalf("readEntityList failed: %v", err)
+	}
+	if len(entities) != 3 {
+		t.Fatalf("expected 3 entities, got %d", len(entities))
+	}
+	if entities[1].Base().Layer != 2 || entities[2] != entities[1] {
+		t.Errorf("unexpected entities: %+v", entities)
+	}
+}
+
+func TestArchive_LargeCount(t *testing.T) {
+	w := &archiveWriter{}
+	w.word(0xFFFF)
+	w.dword(2)
+	w.newClass("CDataSen")
+	w.line(0)
+	w.word(0x8001)
+	w.line(0)
+
+	entities, err := w.archive().readEntityList()
+	if err != nil {
+		t.Fatalf("readEntityList failed: %v", err)
+	}
+	if len(entities) != 2 {
+		t.Errorf("expected 2 entities, got %d", len(entities))
+	}
+}
+
+func TestArchive_InvalidTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *archiveWriter)
+		want  string
+	}{
+		{"unknown class", func(w *archiveWriter) { w.word(0x8005) }, "unknown class index"},
+		{"unknown object", func(w *archiveWriter) { w.word(0x0005) }, "unknown object index"},
+		{"class used as object", func(w *archiveWriter) {
+			w.newClass("CDataSen")
+			w.line(0)
+			w.word(0x0001)
+		}, "refers to class CDataSen"},
+		{"object used as class", func(w *archiveWriter) {
+			w.newClass("CDataSen")
+			w.line(0)
+			w.word(0x8002)
+		}, "refers to an object"},
+		{"unknown class name", func(w *archiveWriter) { w.newClass("CDataFoo") }, "unknown class: CDataFoo"},
+		{"block definition in entity list", func(w *archiveWriter) {
+			w.newClass("CDataList")
+			w.blockDef(1, "BLK")
+			w.word(0)
+		}, "unexpected *jww.BlockDef"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &archiveWriter{}
+			w.word(3)
+			tt.write(w)
+
+			_, err := w.archive().readEntityList()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// jwwFile returns a Ver.6.00 file with the archive written by w following the
+// header.
+func jwwFile(w *archiveWriter) []byte {
+	data := []byte("JwwData.")
+	data = binary.LittleEndian.AppendUint32(data, 600)
+	data = append(data, buildTestHeader(600)...)
+	return append(data, w.buf.Bytes()...)
+}
+
+// emptyBlockDefList writes a block definition list with one definition.
+func (w *archiveWriter) emptyBlockDefList() {
+	w.word(1)
+	w.newClass("CDataList")
+	w.blockDef(1, "BLK")
+	w.word(0)
+}
+
+func TestParseWithOptions_LenientUnknownClass(t *testing.T) {
+	w := &archiveWriter{}
+	w.word(3)
+	w.newClass("CDataSen")
+	w.line(1)
+	w.newClass("CDataFoo")
+	w.buf.Write(make([]byte, 20))
+	w.word(0x8001)
+	w.line(3)
+	w.emptyBlockDefList()
+	data := jwwFile(w)
+
+	if _, err := Parse(bytes.NewReader(data)); err == nil {
+		t.Fatal("expected Parse to fail on an unknown class")
+	}
+
+	doc, err := ParseWithOptions(bytes.NewReader(data), ParseOptions{Mode: Lenient})
+	if err != nil {
+		t.Fatalf("ParseWithOptions failed: %v", err)
+	}
+	if len(doc.Entities) != 2 || doc.Entities[1].Base().Layer != 3 {
+		t.Fatalf("expected the lines before and after the damaged object, got %+v", doc.Entities)
+	}
+	if len(doc.BlockDefs) != 1 {
+		t.Errorf("expected 1 block definition, got %d", len(doc.BlockDefs))
+	}
+	if len(doc.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", doc.Warnings)
+	}
+	warn := doc.Warnings[0]
+	if warn.Section != SectionEntities || warn.Index != 1 || warn.Class != "CDataFoo" {
+		t.Errorf("unexpected warning: %s", warn)
+	}
+	if want := int64(bytes.Index(data, []byte("CDataFoo"))); warn.Offset != want {
+		t.Errorf("warning offset: got %d, want %d", warn.Offset, want)
+	}
+}
+
+func TestParseWithOptions_LenientBlockDef(t *testing.T) {
+	w := &archiveWriter{}
+	w.word(1)
+	w.newClass("CDataSen")
+	w.line(1)
+
+	// The entity list of the first definition is damaged; the second
+	// definition refers to the CDataList class (item 3)
+	w.word(2)
+	w.newClass("CDataList")
+	w.blockDef(1, "A")
+	w.word(2)
+	w.word(0x8009)
+	w.buf.Write(make([]byte, 10))
+	w.word(0x8003)
+	w.blockDef(2, "B")
+	w.word(1)
+	w.word(0x8001)
+	w.line(2)
+	data := jwwFile(w)
+
+	doc, err := ParseWithOptions(bytes.NewReader(data), ParseOptions{Mode: Lenient})
+	if err != nil {
+		t.Fatalf("ParseWithOptions failed: %v", err)
+	}
+	if len(doc.BlockDefs) != 2 {
+		t.Fatalf("expected 2 block definitions, got %d", len(doc.BlockDefs))
+	}
+	if b := doc.BlockDefs[1]; b.Name != "B" || len(b.Entities) != 1 {
+		t.Errorf("second block definition: got %q with %d entities", b.Name, len(b.Entities))
+	}
+	if len(doc.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", doc.Warnings)
+	}
+	if warn := doc.Warnings[0]; warn.Section != SectionBlockDefs || warn.BlockDef != 0 || warn.Index != 0 {
+		t.Errorf("unexpected warning: %s", warn)
+	}
+}
+
+func TestParseWithOptions_LenientEntityCount(t *testing.T) {
+	// The entity count claims more entities than the list holds
+	w := &archiveWriter{}
+	w.word(5)
+	w.newClass("CDataSen")
+	w.line(1)
+	w.word(0x8001)
+	w.line(2)
+	w.emptyBlockDefList()
+	data := jwwFile(w)
+
+	if _, err := Parse(bytes.NewReader(data)); err == nil {
+		t.Fatal("expected Parse to fail")
+	}
+
+	doc, err := ParseWithOptions(bytes.NewReader(data), ParseOptions{Mode: Lenient})
+	if err != nil {
+		t.Fatalf("ParseWithOptions failed: %v", err)
+	}
+	if len(doc.Entities) != 2 || len(doc.BlockDefs) != 1 {
+		t.Errorf("got %d entities and %d block definitions, want 2 and 1", len(doc.Entities), len(doc.BlockDefs))
+	}
+	if len(doc.Warnings) != 1 || !strings.Contains(doc.Warnings[0].Reason, "after 2 of 5 entities") {
+		t.Errorf("unexpected warnings: %v", doc.Warnings)
+	}
+}
+
+func TestParseWithOptions_Suspicious(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *archiveWriter)
+		want  string
+	}{
+		{"null object", func(w *archiveWriter) {
+			w.word(1)
+			w.word(0)
+			w.emptyBlockDefList()
+		}, "null object"},
+		{"back-reference", func(w *archiveWriter) {
+			w.word(2)
+			w.newClass("CDataSen")
+			w.line(0)
+			w.word(0x0002)
+			w.emptyBlockDefList()
+		}, "refers back"},
+		{"layer out of range", func(w *archiveWriter) {
+			w.word(1)
+			w.newClass("CDataSen")
+			w.line(16)
+			w.emptyBlockDefList()
+		}, "out of range"},
+		{"missing block definitions", func(w *archiveWriter) {
+			w.word(0)
+		}, "ends before the block definition list"},
+		{"trailing data", func(w *archiveWriter) {
+			w.word(0)
+			w.word(0)
+			w.dword(0)
+		}, "4 bytes of trailing data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &archiveWriter{}
+			tt.write(w)
+			data := jwwFile(w)
+
+			doc, err := Parse(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if len(doc.Warnings) != 1 || !strings.Contains(doc.Warnings[0].Reason, tt.want) {
+				t.Errorf("expected a warning %q, got %v", tt.want, doc.Warnings)
+			}
+
+			_, err = ParseWithOptions(bytes.NewReader(data), ParseOptions{Mode: Strict})
+			var pe *ParseError
+			if !errors.As(err, &pe) || !strings.Contains(pe.Err.Error(), tt.want) {
+				t.Errorf("expected strict mode to fail with %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestParseWithOptions_StrictValues(t *testing.T) {
+	w := &archiveWriter{}
+	w.word(1)
+	w.newClass("CDataSen")
+	w.base(0)
+	_ = binary.Write(&w.buf, binary.LittleEndian, math.NaN())
+	w.buf.Write(make([]byte, 3*8))
+	w.emptyBlockDefList()
+	data := jwwFile(w)
+
+	if _, err := Parse(bytes.NewReader(data)); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	_, err := ParseWithOptions(bytes.NewReader(data), ParseOptions{Mode: Strict})
+	if err == nil || !strings.Contains(err.Error(), "implausible value NaN") {
+		t.Errorf("expected strict mode to reject NaN, got %v", err)
+	}
+}
+
+func TestWarning_JSON(t *testing.T) {
+	out, err := json.Marshal(Warning{Offset: 10, Section: SectionImages, BlockDef: -1, Index: 2, Reason: "bad"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Offset":10,"Section":"images","BlockDef":-1,"Index":2,"Reason":"bad"}`
+	if string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
@@ -0,0 +1,223 @@
+package jww
+
+import (
+	"bufio"
+	"io"
+	"iter"
+)
+
+// decoderBufferSize is the size of the read buffer of each Decoder pass.
+const decoderBufferSize = 64 << 10
+
+// Decoder reads a JWW file piece by piece from an io.ReaderAt, for files too
+// large to parse into a Document at once. Only the header, the block
+// definitions and the bundled images are kept in memory: entities are
+// decoded one at a time as Entities is iterated, and released afterwards.
+//
+// The entity list comes before the block definitions in the file, and the
+// MFC classes read in the entity list are shared with them, so BlockDefs
+// and Images read the entity list through first unless Entities has already
+// been iterated to the end. Each iteration of Entities reads the list again
+// from the file.
+//
+// Entities referred to by later objects of the file (back-references, which
+// Jw_cad does not write) are not held, so such files are rejected; use Parse
+// for them.
+//
+// Example:
+//
+//	f, _ := os.Open("survey.jww")
+//	defer f.Close()
+//	st, _ := f.Stat()
+//
+//	dec := jww.NewDecoder(f, st.Size())
+//	for entity, err := range dec.Entities() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(entity.Type())
+//	}
+type Decoder struct {
+	r    io.ReaderAt
+	size int64
+	opts ParseOptions
+
+	// The header, read once, and the offset of the entity list
+	header     *Document
+	headerErr  error
+	entitiesAt int64
+
+	// ar is the archive of the first complete read of the entity list, which
+	// holds the classes and continues with the block definitions
+	ar *archive
+
+	blockDefs     []BlockDef
+	blockDefsErr  error
+	blockDefsRead bool
+
+	images     []Image
+	imagesErr  error
+	imagesRead bool
+}
+
+// NewDecoder creates a Decoder reading the JWW file of the given size from r.
+func NewDecoder(r io.ReaderAt, size int64) *Decoder {
+	return NewDecoderWithOptions(r, size, ParseOptions{})
+}
+
+// NewDecoderWithOptions is like NewDecoder, with the limits, context, progress
+// function and mode of opts applied as by ParseWithOptions. Lenient mode needs
+// the whole file to recover from damaged data, so apart from accepting newer
+// versions the Decoder handles it like Standard mode. MaxEntities, and the
+// entities counted in progress reports, apply to each read of the entity list.
+func NewDecoderWithOptions(r io.ReaderAt, size int64, opts ParseOptions) *Decoder {
+	return &Decoder{r: r, size: size, opts: opts.withDefaults()}
+}
+
+// Header reads the signature, version and header of the file, and returns
+// them as a Document without entities, block definitions or images. Errors
+// are returned as by Parse.
+func (d *Decoder) Header() (*Document, error) {
+	if d.header == nil && d.headerErr == nil {
+		if d.size > d.opts.MaxFileBytes {
+			d.headerErr = fileTooLarge(d.opts.MaxFileBytes)
+			return nil, d.headerErr
+		}
+		d.opts.report(Progress{Stage: StageHeader, TotalBytes: d.size})
+		jr := d.reader(0)
+		d.header, d.headerErr = readFileHeader(jr, d.opts.Mode)
+		d.entitiesAt = jr.BytesRead()
+	}
+	return d.header, d.headerErr
+}
+
+// Entities returns an iterator over the entity list of the file, decoding
+// each entity as it is reached. Errors are returned as a *ParseError, after
+// which the iteration ends.
+//
+// The entities do not include those of the block definitions; see
+// BlockDefs.
+func (d *Decoder) Entities() iter.Seq2[Entity, error] {
+	return func(yield func(Entity, error) bool) {
+		doc, err := d.Header()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		ar := newArchive(d.reader(d.entitiesAt), doc.Version)
+		ar.setOptions(d.opts)
+		ar.release = true
+		ar.size = d.size
+		ar.section = SectionEntities
+		ar.progress()
+
+		more := true
+		err = ar.eachEntity(func(entity Entity) bool {
+			more = yield(entity, nil)
+			return more
+		})
+		switch {
+		case err != nil:
+			yield(nil, err)
+		case more:
+			ar.progress()
+			if d.ar == nil {
+				d.ar = ar
+			}
+		}
+	}
+}
+
+// BlockDefs returns the block definitions of the file, with their entities.
+// Errors are returned as a *ParseError.
+func (d *Decoder) BlockDefs() ([]BlockDef, error) {
+	if !d.blockDefsRead {
+		d.blockDefs, d.blockDefsErr = d.readBlockDefs()
+		d.blockDefsRead = true
+	}
+	return d.blockDefs, d.blockDefsErr
+}
+
+// readBlockDefs reads the block definition list following the entity list.
+func (d *Decoder) readBlockDefs() ([]BlockDef, error) {
+	if d.ar == nil {
+		for _, err := range d.Entities() {
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	ar := d.ar
+	ar.section = SectionBlockDefs
+	ar.progress()
+	if ar.jr.BytesRead() >= d.size {
+		return nil, ar.suspicious("file ends before the block definition list")
+	}
+	blockDefs, err := ar.readBlockDefList()
+	if err == nil {
+		ar.progress()
+	}
+	return blockDefs, err
+}
+
+// Images returns the image files bundled with the drawing (同梱画像,
+// Ver.7.00 and later), which follow the block definitions. Errors are
+// returned as a *ParseError.
+func (d *Decoder) Images() ([]Image, error) {
+	if !d.imagesRead {
+		d.images, d.imagesErr = d.readImages()
+		d.imagesRead = true
+	}
+	return d.images, d.imagesErr
+}
+
+// readImages reads the bundled image files following the block definitions.
+func (d *Decoder) readImages() ([]Image, error) {
+	if _, err := d.BlockDefs(); err != nil {
+		return nil, err
+	}
+
+	ar := d.ar
+	jr := ar.jr
+	var images []Image
+	if d.header.Version >= 700 && jr.BytesRead() < d.size {
+		ar.section = SectionImages
+		ar.progress()
+		var err error
+		if images, err = parseImages(jr, d.size-jr.BytesRead(), d.opts, ar.progress); err != nil {
+			return nil, sectionError(err, SectionImages)
+		}
+	}
+
+	if n := d.size - jr.BytesRead(); n > 0 {
+		if err := ar.suspicious("%d bytes of trailing data", n); err != nil {
+			return nil, err
+		}
+	}
+	ar.progress()
+	return images, nil
+}
+
+// Warnings returns the problems that did not stop decoding, as listed in
+// Document.Warnings by Parse, found in the parts of the file read so far.
+func (d *Decoder) Warnings() []Warning {
+	var warnings []Warning
+	if d.header != nil {
+		warnings = append(warnings, d.header.Warnings...)
+	}
+	if d.ar != nil {
+		warnings = append(warnings, d.ar.warnings...)
+	}
+	return warnings
+}
+
+// reader returns a Reader of the file starting at the given offset.
+func (d *Decoder) reader(offset int64) *Reader {
+	sr := io.NewSectionReader(d.r, offset, d.size-offset)
+	jr := NewReader(bufio.NewReaderSize(sr, decoderBufferSize))
+	jr.bytesRead = offset
+	jr.maxStringLen = d.opts.MaxStringLen
+	return jr
+}
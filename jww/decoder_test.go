@@ -0,0 +1,177 @@
+package jww
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// decoderTestFile returns a file with three lines, the second of a class
+// referred to by its index, and a block definition holding a fourth line.
+func decoderTestFile() []byte {
+	w := &archiveWriter{}
+	w.word(3)
+	w.newClass("CDataSen")
+	w.line(1)
+	w.word(0x8001)
+	w.line(2)
+	w.word(0x8001)
+	w.line(3)
+
+	w.word(1)
+	w.newClass("CDataList")
+	w.blockDef(1, "BLK")
+	w.word(1)
+	w.word(0x8001)
+	w.line(4)
+	return jwwFile(w)
+}
+
+func TestDecoder_MatchesParse(t *testing.T) {
+	data := decoderTestFile()
+	want, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data), int64(len(data)))
+	doc, err := dec.Header()
+	if err != nil {
+		t.Fatalf("Header failed: %v", err)
+	}
+	if doc.Version != want.Version || doc.LayerGroups != want.LayerGroups || !reflect.DeepEqual(doc.Header, want.Header) {
+		t.Error("header differs from Parse")
+	}
+
+	var entities []Entity
+	for entity, err := range dec.Entities() {
+		if err != nil {
+			t.Fatalf("Entities failed: %v", err)
+		}
+		entities = append(entities, entity)
+	}
+	if !reflect.DeepEqual(entities, want.Entities) {
+		t.Errorf("entities differ from Parse: got %+v", entities)
+	}
+
+	blockDefs, err := dec.BlockDefs()
+	if err != nil {
+		t.Fatalf("BlockDefs failed: %v", err)
+	}
+	if !reflect.DeepEqual(blockDefs, want.BlockDefs) {
+		t.Errorf("block definitions differ from Parse: got %+v", blockDefs)
+	}
+
+	images, err := dec.Images()
+	if err != nil || images != nil {
+		t.Errorf("Images: got %v, %v", images, err)
+	}
+	if len(dec.Warnings()) != 0 {
+		t.Errorf("unexpected warnings: %v", dec.Warnings())
+	}
+}
+
+func TestDecoder_BlockDefsFirst(t *testing.T) {
+	data := decoderTestFile()
+	dec := NewDecoder(bytes.NewReader(data), int64(len(data)))
+
+	// The entity list is read through to reach the block definitions
+	blockDefs, err := dec.BlockDefs()
+	if err != nil {
+		t.Fatalf("BlockDefs failed: %v", err)
+	}
+	if len(blockDefs) != 1 || blockDefs[0].Entities[0].Base().Layer != 4 {
+		t.Fatalf("unexpected block definitions: %+v", blockDefs)
+	}
+
+	// Entities can still be iterated, and stopped early
+	var layers []uint16
+	for entity, err := range dec.Entities() {
+		if err != nil {
+			t.Fatalf("Entities failed: %v", err)
+		}
+		layers = append(layers, entity.Base().Layer)
+		if len(layers) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(layers, []uint16{1, 2}) {
+		t.Errorf("got layers %v, want [1 2]", layers)
+	}
+}
+
+func TestDecoder_StopEarly(t *testing.T) {
+	data := decoderTestFile()
+	dec := NewDecoder(bytes.NewReader(data), int64(len(data)))
+
+	for range dec.Entities() {
+		break
+	}
+
+	// An incomplete iteration does not leave the decoder past the entities
+	blockDefs, err := dec.BlockDefs()
+	if err != nil || len(blockDefs) != 1 {
+		t.Errorf("BlockDefs: got %d, %v", len(blockDefs), err)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	data := decoderTestFile()
+	truncated := data[:bytes.Index(data, []byte("CDataList"))-20]
+
+	dec := NewDecoder(bytes.NewReader(truncated), int64(len(truncated)))
+	var n int
+	var last error
+	for _, err := range dec.Entities() {
+		if err != nil {
+			last = err
+			continue
+		}
+		n++
+	}
+	var pe *ParseError
+	if !errors.As(last, &pe) || pe.Section != SectionEntities || pe.Index != 2 {
+		t.Fatalf("expected a ParseError for entity 2, got %v", last)
+	}
+	if !errors.Is(last, io.ErrUnexpectedEOF) || n != 2 {
+		t.Errorf("got %d entities and %v", n, last)
+	}
+	if _, err := dec.BlockDefs(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("BlockDefs: expected the entity list error, got %v", err)
+	}
+
+	dec = NewDecoder(strings.NewReader("NotValid"), 8)
+	for _, err := range dec.Entities() {
+		if !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("expected ErrInvalidSignature, got %v", err)
+		}
+	}
+}
+
+func TestDecoder_ReleasedEntity(t *testing.T) {
+	// The block definition refers back to the first line of the entity
+	// list, which Parse keeps and the Decoder does not
+	w := &archiveWriter{}
+	w.word(1)
+	w.newClass("CDataSen")
+	w.line(1)
+	w.word(1)
+	w.newClass("CDataList")
+	w.blockDef(1, "BLK")
+	w.word(1)
+	w.word(0x0002)
+	data := jwwFile(w)
+
+	if _, err := Parse(bytes.NewReader(data)); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data), int64(len(data)))
+	_, err := dec.BlockDefs()
+	if err == nil || !strings.Contains(err.Error(), "no longer held by the decoder") {
+		t.Errorf("got error %v", err)
+	}
+}
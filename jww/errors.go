@@ -0,0 +1,188 @@
+package jww
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Section is a part of a JWW file, as reported by ParseError.
+type Section int
+
+const (
+	// SectionHeader is the signature, version and header (memo, layers and
+	// drawing settings).
+	SectionHeader Section = iota + 1
+
+	// SectionEntities is the entity list (データリスト).
+	SectionEntities
+
+	// SectionBlockDefs is the list of block definitions (ブロック図形定義).
+	SectionBlockDefs
+
+	// SectionImages is the bundled image files (同梱画像) of Ver.7.00 and later.
+	SectionImages
+)
+
+// sectionNames are the names returned by Section.String.
+var sectionNames = [...]string{
+	SectionHeader:    "header",
+	SectionEntities:  "entity list",
+	SectionBlockDefs: "block definitions",
+	SectionImages:    "images",
+}
+
+// String returns the name of the section, e.g. "entity list".
+func (s Section) String() string {
+	if s <= 0 || int(s) >= len(sectionNames) {
+		return ""
+	}
+	return sectionNames[s]
+}
+
+// MarshalText encodes the section by name, so that it appears as a string in
+// JSON.
+func (s Section) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseError describes where a JWW file could not be parsed. It wraps the
+// cause, so errors.Is(err, io.ErrUnexpectedEOF) or errors.Is(err,
+// ErrUnsupportedVersion) still work on errors returned by Parse.
+//
+// Example:
+//
+//	doc, err := jww.Parse(f)
+//	var pe *jww.ParseError
+//	if errors.As(err, &pe) {
+//		log.Printf("%s: offset %d, entity %d (%s)", pe.Section, pe.Offset, pe.Index, pe.Class)
+//	}
+type ParseError struct {
+	// Offset is the byte offset from the start of the file of the value
+	// that could not be read or was found invalid.
+	Offset int64
+
+	// Section is the part of the file being parsed.
+	Section Section
+
+	// BlockDef is the index of the block definition being parsed within the
+	// block definition list, or -1.
+	BlockDef int
+
+	// Index is the index of the entity (or, in SectionImages, the image)
+	// being parsed within its list, or -1. Within a block definition the
+	// index is in the block definition's own entity list.
+	Index int
+
+	// Class is the MFC class name of the object being parsed, e.g.
+	// "CDataMoji", or "" when unknown.
+	Class string
+
+	// Err is the cause.
+	Err error
+}
+
+// Error returns a description such as "jww: entity list: entity 11
+// (CDataMoji) at offset 5023: unexpected EOF".
+func (e *ParseError) Error() string {
+	return "jww: " + describe(e.Section, e.BlockDef, e.Index, e.Class, e.Offset, e.Err.Error())
+}
+
+// Unwrap returns the cause.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// warning returns the error as a warning.
+func (e *ParseError) warning() Warning {
+	return Warning{
+		Offset:   e.Offset,
+		Section:  e.Section,
+		BlockDef: e.BlockDef,
+		Index:    e.Index,
+		Class:    e.Class,
+		Reason:   e.Err.Error(),
+	}
+}
+
+// Warning is a problem that did not stop parsing, reported in
+// Document.Warnings: damaged data skipped in lenient mode, or suspicious data.
+// The fields locate the problem like those of ParseError.
+type Warning struct {
+	// Offset is the byte offset from the start of the file.
+	Offset int64
+
+	// Section is the part of the file.
+	Section Section
+
+	// BlockDef is the index of the block definition, or -1.
+	BlockDef int
+
+	// Index is the index of the entity or image within its list, or -1.
+	Index int
+
+	// Class is the MFC class name of the object, or "" when unknown.
+	Class string `json:",omitempty"`
+
+	// Reason describes the problem.
+	Reason string
+}
+
+// String returns a description such as "entity list: entity 3 at offset
+// 1234: null object in entity list".
+func (w Warning) String() string {
+	return describe(w.Section, w.BlockDef, w.Index, w.Class, w.Offset, w.Reason)
+}
+
+// describe formats the location of a parse error or warning followed by the
+// reason.
+func describe(section Section, blockDef, index int, class string, offset int64, reason string) string {
+	var sb strings.Builder
+	if section != 0 {
+		sb.WriteString(section.String())
+		sb.WriteString(": ")
+	}
+
+	var where []string
+	if blockDef >= 0 {
+		where = append(where, fmt.Sprintf("block definition %d", blockDef))
+	}
+	if index >= 0 {
+		item := "entity"
+		if section == SectionImages {
+			item = "image"
+		}
+		where = append(where, fmt.Sprintf("%s %d", item, index))
+	}
+	if len(where) > 0 {
+		sb.WriteString(strings.Join(where, ", "))
+		sb.WriteString(" ")
+	}
+	if class != "" {
+		fmt.Fprintf(&sb, "(%s) ", class)
+	}
+	fmt.Fprintf(&sb, "at offset %d: %s", offset, reason)
+	return sb.String()
+}
+
+// asParseError returns err as a *ParseError. Errors that are not one yet are
+// wrapped in a new ParseError at the given offset; an io.EOF cause becomes
+// io.ErrUnexpectedEOF, as the data ended in the middle of the file structure.
+func asParseError(err error, offset int64) *ParseError {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return pe
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return &ParseError{Offset: offset, BlockDef: -1, Index: -1, Err: err}
+}
+
+// sectionError sets the section of the *ParseError err.
+func sectionError(err error, section Section) error {
+	pe := asParseError(err, 0)
+	pe.Section = section
+	return pe
+}
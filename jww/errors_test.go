@@ -0,0 +1,124 @@
+package jww
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParse_TruncatedEntity(t *testing.T) {
+	data := createMinimalJWWData()
+
+	_, err := Parse(bytes.NewReader(data[:len(data)-4]))
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if pe.Section != SectionEntities || pe.Index != 0 || pe.BlockDef != -1 || pe.Class != "CDataSen" {
+		t.Errorf("got section %v, index %d, block def %d, class %q", pe.Section, pe.Index, pe.BlockDef, pe.Class)
+	}
+	if want := int64(len(data) - 8); pe.Offset != want {
+		t.Errorf("offset: got %d, want %d (last coordinate of the line)", pe.Offset, want)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF cause, got %v", pe.Err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "entity list: entity 0 (CDataSen) at offset") {
+		t.Errorf("unexpected message: %s", msg)
+	}
+}
+
+func TestParse_TruncatedBlockDefEntity(t *testing.T) {
+	data := createMinimalJWWDataWithBlockDef()
+
+	// Replace the empty nested entity list by one line, of the class read in
+	// the entity list, that ends after its tag
+	data = data[:len(data)-2]
+	data = binary.LittleEndian.AppendUint16(data, 1)
+	data = binary.LittleEndian.AppendUint16(data, 0x8001)
+
+	_, err := Parse(bytes.NewReader(data))
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if pe.Section != SectionBlockDefs || pe.BlockDef != 0 || pe.Index != 0 || pe.Class != "CDataSen" {
+		t.Errorf("got section %v, block def %d, index %d, class %q", pe.Section, pe.BlockDef, pe.Index, pe.Class)
+	}
+	if pe.Offset != int64(len(data)) {
+		t.Errorf("offset: got %d, want %d", pe.Offset, len(data))
+	}
+}
+
+func TestParse_UnknownClass(t *testing.T) {
+	data := createMinimalJWWData()
+	i := bytes.Index(data, []byte("CDataSen"))
+	copy(data[i:], "CDataXyz")
+
+	_, err := Parse(bytes.NewReader(data))
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if pe.Class != "CDataXyz" || pe.Offset != int64(i) {
+		t.Errorf("got class %q at offset %d, want CDataXyz at %d", pe.Class, pe.Offset, i)
+	}
+}
+
+func TestParse_UnsupportedVersion(t *testing.T) {
+	for _, version := range []uint32{0, 199, 701, 900, 0xFFFFFFFF} {
+		data := createMinimalJWWData()
+		binary.LittleEndian.PutUint32(data[8:], version)
+
+		_, err := Parse(bytes.NewReader(data))
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("version %d: expected ErrUnsupportedVersion, got %v", version, err)
+			continue
+		}
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Section != SectionHeader || pe.Offset != 8 {
+			t.Errorf("version %d: unexpected error %#v", version, err)
+		}
+	}
+
+	// Lenient mode reads newer versions with a warning, but not older ones
+	for _, version := range []uint32{0, 199, 701, 900} {
+		data := createMinimalJWWData()
+		binary.LittleEndian.PutUint32(data[8:], version)
+
+		doc, err := ParseWithOptions(bytes.NewReader(data), ParseOptions{Mode: Lenient})
+		if version < 200 {
+			if !errors.Is(err, ErrUnsupportedVersion) {
+				t.Errorf("version %d: expected ErrUnsupportedVersion, got %v", version, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("version %d: %v", version, err)
+			continue
+		}
+		if len(doc.Warnings) == 0 || doc.Warnings[0].Section != SectionHeader || doc.Warnings[0].Offset != 8 {
+			t.Errorf("version %d: expected a header warning, got %v", version, doc.Warnings)
+		}
+	}
+}
+
+func TestParse_TruncatedHeader(t *testing.T) {
+	data := createMinimalJWWData()
+
+	_, err := Parse(bytes.NewReader(data[:100]))
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Section != SectionHeader || pe.Index != -1 {
+		t.Fatalf("expected a header *ParseError, got %v", err)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF cause, got %v", pe.Err)
+	}
+}
@@ -0,0 +1,281 @@
+package jww
+
+// TextRole is the role of a text entity given by its attribute flags.
+type TextRole int
+
+const (
+	// TextRoleNone is an ordinary text.
+	TextRoleNone TextRole = iota
+
+	// TextRoleDimensionValue is a dimension value (寸法値, flag 0x0010).
+	TextRoleDimensionValue
+
+	// TextRoleRadiusValue is a radius dimension value (半径寸法値, flag 0x0100).
+	TextRoleRadiusValue
+
+	// TextRoleDiameterValue is a diameter dimension value (直径寸法値, flag 0x0200).
+	TextRoleDiameterValue
+
+	// TextRoleAngleValue is an angle dimension value (角度寸法値, flag 0x0400).
+	TextRoleAngleValue
+
+	// TextRoleCumulativeValue is a cumulative dimension value (累計寸法値, flag 0x1000).
+	TextRoleCumulativeValue
+
+	// TextRoleTrueNorth is the true north text (真北, flag 0x0040).
+	TextRoleTrueNorth
+
+	// TextRoleShadow is a shadow (sun shading) text (日影, flag 0x0080).
+	TextRoleShadow
+)
+
+// textRoleNames are the names returned by TextRole.String.
+var textRoleNames = [...]string{
+	TextRoleNone:            "",
+	TextRoleDimensionValue:  "dimension-value",
+	TextRoleRadiusValue:     "radius-value",
+	TextRoleDiameterValue:   "diameter-value",
+	TextRoleAngleValue:      "angle-value",
+	TextRoleCumulativeValue: "cumulative-value",
+	TextRoleTrueNorth:       "true-north",
+	TextRoleShadow:          "shadow",
+}
+
+// String returns the name of the role, e.g. "radius-value", or "" for
+// ordinary texts.
+func (r TextRole) String() string {
+	if r < 0 || int(r) >= len(textRoleNames) {
+		return ""
+	}
+	return textRoleNames[r]
+}
+
+// MarshalText encodes the role by name, so that it appears as a string in
+// JSON.
+func (r TextRole) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Attributes is the meaning of the attribute flags (属性フラグ) of an entity,
+// decoded according to its type by DecodeAttributes.
+type Attributes struct {
+	// Figure marks entities belonging to a figure (図形).
+	Figure bool `json:",omitempty"`
+
+	// Hatch marks lines, arcs, points and solids drawn by hatching (ハッチ).
+	Hatch bool `json:",omitempty"`
+
+	// DimensionPart marks entities belonging to a dimension (寸法).
+	DimensionPart bool `json:",omitempty"`
+
+	// Fitting marks entities belonging to a fitting such as a door or window
+	// (建具).
+	Fitting bool `json:",omitempty"`
+
+	// EnvelopeExcluded marks fitting lines excluded from envelope processing
+	// (包絡処理対象外の建具).
+	EnvelopeExcluded bool `json:",omitempty"`
+
+	// Vertical marks vertically written texts (縦字).
+	Vertical bool `json:",omitempty"`
+
+	// FigureAttribute marks texts selected as figure attributes (図形属性選択).
+	FigureAttribute bool `json:",omitempty"`
+
+	// Is25D marks 2.5D texts.
+	Is25D bool `json:",omitempty"`
+
+	// TextRole is the role of a text entity.
+	TextRole TextRole `json:",omitempty"`
+}
+
+// DecodeAttributes decodes the attribute flags of an entity according to its
+// type. The parser stores the result in EntityBase.Attributes; entities built
+// or modified in code can be decoded again with this function.
+//
+// Example:
+//
+//	for _, e := range doc.Entities {
+//		if jww.DecodeAttributes(e).Hatch {
+//			hatchLines++
+//		}
+//	}
+func DecodeAttributes(e Entity) Attributes {
+	switch v := e.(type) {
+	case *Line:
+		return Attributes{
+			Figure:           v.IsFigure(),
+			Hatch:            v.IsHatch(),
+			DimensionPart:    v.IsDimensionPart(),
+			Fitting:          v.IsFitting(),
+			EnvelopeExcluded: v.IsEnvelopeExcluded(),
+		}
+	case *Arc:
+		return Attributes{
+			Figure:        v.IsFigure(),
+			Hatch:         v.IsHatch(),
+			DimensionPart: v.IsDimensionPart(),
+			Fitting:       v.IsFitting(),
+		}
+	case *Point:
+		return Attributes{
+			Figure:        v.IsFigure(),
+			Hatch:         v.IsHatch(),
+			DimensionPart: v.IsDimensionPart(),
+			Fitting:       v.IsFitting(),
+		}
+	case *Text:
+		return v.attributes()
+	case *ImageRef:
+		// Images are stored as texts
+		return (&Text{EntityBase: v.EntityBase}).attributes()
+	case *Solid:
+		return Attributes{Hatch: v.IsHatch()}
+	case *CircleSolid:
+		return Attributes{Hatch: v.IsHatch()}
+	case *Block:
+		return Attributes{
+			Figure:        v.IsFigure(),
+			DimensionPart: v.IsDimensionPart(),
+			Fitting:       v.IsFitting(),
+		}
+	case *Dimension:
+		return Attributes{DimensionPart: v.IsDimensionPart()}
+	}
+	return Attributes{}
+}
+
+// attributes decodes the attribute flags of a text.
+func (t *Text) attributes() Attributes {
+	return Attributes{
+		DimensionPart:   t.IsDimensionPart(),
+		Fitting:         t.IsFitting(),
+		Vertical:        t.IsVertical(),
+		FigureAttribute: t.IsFigureAttribute(),
+		Is25D:           t.Is25D(),
+		TextRole:        t.TextRole(),
+	}
+}
+
+// setAttributes stores the decoded attribute flags of an entity, and of the
+// lines, text and points making up a dimension, in their EntityBase.
+func setAttributes(e Entity) {
+	e.Base().Attributes = DecodeAttributes(e)
+
+	if dim, ok := e.(*Dimension); ok {
+		dim.Line.Attributes = DecodeAttributes(&dim.Line)
+		dim.Text.Attributes = DecodeAttributes(&dim.Text)
+		for i := range dim.AuxLines {
+			dim.AuxLines[i].Attributes = DecodeAttributes(&dim.AuxLines[i])
+		}
+		for i := range dim.ArrowPoints {
+			dim.ArrowPoints[i].Attributes = DecodeAttributes(&dim.ArrowPoints[i])
+		}
+		for i := range dim.RefPoints {
+			dim.RefPoints[i].Attributes = DecodeAttributes(&dim.RefPoints[i])
+		}
+	}
+}
+
+// IsFigure reports whether the line belongs to a figure (図形, flag 0x0800).
+func (l *Line) IsFigure() bool { return l.Flag&0x0800 != 0 }
+
+// IsHatch reports whether the line was drawn by hatching (ハッチ, flag 0x0020).
+func (l *Line) IsHatch() bool { return l.Flag&0x0020 != 0 }
+
+// IsDimensionPart reports whether the line belongs to a dimension (寸法,
+// flag 0x2000).
+func (l *Line) IsDimensionPart() bool { return l.Flag&0x2000 != 0 }
+
+// IsFitting reports whether the line belongs to a fitting (建具, flag
+// 0x1000), including fittings excluded from envelope processing.
+func (l *Line) IsFitting() bool { return l.Flag&(0x1000|0x8000) != 0 }
+
+// IsEnvelopeExcluded reports whether the line is a fitting line excluded from
+// envelope processing (包絡処理対象外の建具, flag 0x8000).
+func (l *Line) IsEnvelopeExcluded() bool { return l.Flag&0x8000 != 0 }
+
+// IsFigure reports whether the arc belongs to a figure (図形, flag 0x0010).
+func (a *Arc) IsFigure() bool { return a.Flag&0x0010 != 0 }
+
+// IsHatch reports whether the arc was drawn by hatching (ハッチ, flag 0x0020).
+func (a *Arc) IsHatch() bool { return a.Flag&0x0020 != 0 }
+
+// IsDimensionPart reports whether the arc belongs to a dimension (寸法, flag
+// 0x0040).
+func (a *Arc) IsDimensionPart() bool { return a.Flag&0x0040 != 0 }
+
+// IsFitting reports whether the arc belongs to a fitting (建具, flag 0x0080).
+func (a *Arc) IsFitting() bool { return a.Flag&0x0080 != 0 }
+
+// IsFigure reports whether the point belongs to a figure (図形, flag 0x0010).
+func (p *Point) IsFigure() bool { return p.Flag&0x0010 != 0 }
+
+// IsHatch reports whether the point was drawn by hatching (ハッチ, flag 0x0020).
+func (p *Point) IsHatch() bool { return p.Flag&0x0020 != 0 }
+
+// IsDimensionPart reports whether the point belongs to a dimension (寸法,
+// flag 0x0040).
+func (p *Point) IsDimensionPart() bool { return p.Flag&0x0040 != 0 }
+
+// IsFitting reports whether the point belongs to a fitting (建具, flag 0x0080).
+func (p *Point) IsFitting() bool { return p.Flag&0x0080 != 0 }
+
+// TextRole returns the role of the text given by its attribute flags. The
+// specific dimension value roles take precedence over the plain dimension
+// value flag.
+func (t *Text) TextRole() TextRole {
+	switch {
+	case t.Flag&0x0100 != 0:
+		return TextRoleRadiusValue
+	case t.Flag&0x0200 != 0:
+		return TextRoleDiameterValue
+	case t.Flag&0x0400 != 0:
+		return TextRoleAngleValue
+	case t.Flag&0x1000 != 0:
+		return TextRoleCumulativeValue
+	case t.Flag&0x0010 != 0:
+		return TextRoleDimensionValue
+	case t.Flag&0x0040 != 0:
+		return TextRoleTrueNorth
+	case t.Flag&0x0080 != 0:
+		return TextRoleShadow
+	}
+	return TextRoleNone
+}
+
+// IsDimensionPart reports whether the text belongs to a dimension (寸法, flag
+// 0x4000).
+func (t *Text) IsDimensionPart() bool { return t.Flag&0x4000 != 0 }
+
+// IsFitting reports whether the text belongs to a fitting (建具, flag 0x2000).
+func (t *Text) IsFitting() bool { return t.Flag&0x2000 != 0 }
+
+// IsFigureAttribute reports whether the text is selected as a figure
+// attribute (図形属性選択, flag 0x0800).
+func (t *Text) IsFigureAttribute() bool { return t.Flag&0x0800 != 0 }
+
+// Is25D reports whether the text is a 2.5D text (flag 0x8000).
+func (t *Text) Is25D() bool { return t.Flag&0x8000 != 0 }
+
+// IsHatch reports whether the solid was drawn by hatching (ハッチ, flag 0x0020).
+func (s *Solid) IsHatch() bool { return s.Flag&0x0020 != 0 }
+
+// IsHatch reports whether the circle solid was drawn by hatching (ハッチ,
+// flag 0x0020).
+func (c *CircleSolid) IsHatch() bool { return c.Flag&0x0020 != 0 }
+
+// IsFigure reports whether the block reference is a figure (図形, flag 0x0800).
+func (b *Block) IsFigure() bool { return b.Flag&0x0800 != 0 }
+
+// IsFitting reports whether the block reference is a fitting (建具, flag
+// 0x1000).
+func (b *Block) IsFitting() bool { return b.Flag&0x1000 != 0 }
+
+// IsDimensionPart reports whether the block reference belongs to a dimension
+// (寸法, flag 0x2000).
+func (b *Block) IsDimensionPart() bool { return b.Flag&0x2000 != 0 }
+
+// IsDimensionPart reports whether the dimension carries the dimension flag
+// (寸法, flag 0x2000).
+func (d *Dimension) IsDimensionPart() bool { return d.Flag&0x2000 != 0 }
@@ -0,0 +1,72 @@
+package jww
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeAttributes(t *testing.T) {
+	base := func(flag uint16) EntityBase { return EntityBase{Flag: flag} }
+	tests := []struct {
+		name   string
+		entity Entity
+		want   Attributes
+	}{
+		{"hatch line", &Line{EntityBase: base(0x0020)}, Attributes{Hatch: true}},
+		{"figure line", &Line{EntityBase: base(0x0800)}, Attributes{Figure: true}},
+		{"dimension line", &Line{EntityBase: base(0x2000)}, Attributes{DimensionPart: true}},
+		{"envelope excluded line", &Line{EntityBase: base(0x8000)}, Attributes{Fitting: true, EnvelopeExcluded: true}},
+		{"figure arc", &Arc{EntityBase: base(0x0010)}, Attributes{Figure: true}},
+		{"dimension arc", &Arc{EntityBase: base(0x0040)}, Attributes{DimensionPart: true}},
+		{"fitting point", &Point{EntityBase: base(0x0080)}, Attributes{Fitting: true}},
+		{"vertical text", &Text{EntityBase: base(0x0020)}, Attributes{Vertical: true}},
+		{"radius value", &Text{EntityBase: base(0x0110)}, Attributes{TextRole: TextRoleRadiusValue}},
+		{"dimension value", &Text{EntityBase: base(0x4010)}, Attributes{DimensionPart: true, TextRole: TextRoleDimensionValue}},
+		{"true north", &Text{EntityBase: base(0x0040)}, Attributes{TextRole: TextRoleTrueNorth}},
+		{"2.5D text", &Text{EntityBase: base(0x8000)}, Attributes{Is25D: true}},
+		{"fitting text", &Text{EntityBase: base(0x2000)}, Attributes{Fitting: true}},
+		{"hatch solid", &Solid{EntityBase: base(0x0020)}, Attributes{Hatch: true}},
+		{"fitting block", &Block{EntityBase: base(0x1000)}, Attributes{Fitting: true}},
+		{"block bits of lines", &Block{EntityBase: base(0x0020)}, Attributes{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeAttributes(tt.entity); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Attributes(t *testing.T) {
+	data := createMinimalJWWData()
+	// The line's flag precedes its four coordinates
+	data[len(data)-34] = 0x20
+
+	doc, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !doc.Entities[0].Base().Attributes.Hatch {
+		t.Fatalf("expected the hatch attribute, got %+v", doc.Entities[0].Base().Attributes)
+	}
+
+	out, err := json.Marshal(doc.Entities[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"Attributes":{"Hatch":true}`) {
+		t.Errorf("JSON: got %s", out)
+	}
+
+	out, _ = json.Marshal(&Text{EntityBase: EntityBase{Attributes: Attributes{TextRole: TextRoleAngleValue}}})
+	if !strings.Contains(string(out), `"Attributes":{"TextRole":"angle-value"}`) {
+		t.Errorf("JSON: got %s", out)
+	}
+	if out, _ := json.Marshal(&Line{}); strings.Contains(string(out), "Attributes") {
+		t.Errorf("expected no attributes without flags, got %s", out)
+	}
+}
@@ -0,0 +1,408 @@
+package jww
+
+// headerReader wraps a Reader and remembers the first read error, so the long
+// run of header fields can be decoded without checking every single read.
+// Once an error occurs all further reads return zero values.
+type headerReader struct {
+	jr     *Reader
+	err    error
+	offset int64
+}
+
+func (h *headerReader) fail(err error, offset int64) {
+	if h.err == nil && err != nil {
+		h.err = err
+		h.offset = offset
+	}
+}
+
+func (h *headerReader) dword() uint32 {
+	if h.err != nil {
+		return 0
+	}
+	offset := h.jr.BytesRead()
+	v, err := h.jr.ReadDWORD()
+	h.fail(err, offset)
+	return v
+}
+
+func (h *headerReader) int32() int32 {
+	return int32(h.dword())
+}
+
+func (h *headerReader) double() float64 {
+	if h.err != nil {
+		return 0
+	}
+	offset := h.jr.BytesRead()
+	v, err := h.jr.ReadDouble()
+	h.fail(err, offset)
+	return v
+}
+
+func (h *headerReader) cstring() string {
+	if h.err != nil {
+		return ""
+	}
+	offset := h.jr.BytesRead()
+	v, err := h.jr.ReadCString()
+	h.fail(err, offset)
+	return v
+}
+
+func (h *headerReader) skipDWORDs(n int) {
+	for i := 0; i < n; i++ {
+		h.dword()
+	}
+}
+
+// parseHeader reads the JWW header from the file memo up to the start of the
+// entity list. The reader must be positioned right after the version DWORD.
+//
+// Fields are read strictly in file order as documented in refs/jwdatafmt.md.
+// Version-dependent fields are only read when doc.Version includes them.
+func parseHeader(jr *Reader, doc *Document) error {
+	h := &headerReader{jr: jr}
+	hd := &doc.Header
+	version := doc.Version
+
+	doc.Memo = h.cstring()
+	doc.PaperSize = h.dword()
+
+	// Layer group and layer states
+	doc.WriteLayerGroup = h.dword()
+	for gLay := 0; gLay < 16; gLay++ {
+		lg := &doc.LayerGroups[gLay]
+		lg.State = h.dword()
+		lg.WriteLayer = h.dword()
+		lg.Scale = h.double()
+		lg.Protect = h.dword()
+		for lay := 0; lay < 16; lay++ {
+			lg.Layers[lay].State = h.dword()
+			lg.Layers[lay].Protect = h.dword()
+		}
+	}
+
+	h.skipDWORDs(14) // dummy
+
+	for i := range hd.DimensionSettings {
+		hd.DimensionSettings[i] = h.dword()
+	}
+
+	h.skipDWORDs(1) // dummy
+
+	hd.MaxDrawWidth = h.int32()
+
+	// Printer settings
+	hd.PrinterOriginX = h.double()
+	hd.PrinterOriginY = h.double()
+	hd.PrinterScale = h.double()
+	hd.PrinterSettings = h.dword()
+
+	// Grid (目盛) settings
+	hd.GridMode = h.int32()
+	hd.GridMinSpacing = h.double()
+	hd.GridSpacingX = h.double()
+	hd.GridSpacingY = h.double()
+	hd.GridOriginX = h.double()
+	hd.GridOriginY = h.double()
+
+	// Layer names and layer group names
+	for gLay := 0; gLay < 16; gLay++ {
+		for lay := 0; lay < 16; lay++ {
+			doc.LayerGroups[gLay].Layers[lay].Name = h.cstring()
+		}
+	}
+	for gLay := 0; gLay < 16; gLay++ {
+		doc.LayerGroups[gLay].Name = h.cstring()
+	}
+
+	// Sun shadow and sky map conditions
+	hd.Shadow.Level = h.double()
+	hd.Shadow.Latitude = h.double()
+	hd.Shadow.Hours9To15 = h.dword()
+	hd.Shadow.WallLevel = h.double()
+	if version >= 300 {
+		hd.SkyMapLevel = h.double()
+		hd.SkyMapDiameter = h.double()
+	}
+
+	hd.Units25D = h.dword()
+
+	// Screen magnification, range and mark jumps
+	hd.ViewScale = h.double()
+	hd.ViewOriginX = h.double()
+	hd.ViewOriginY = h.double()
+	hd.RangeScale = h.double()
+	hd.RangeOriginX = h.double()
+	hd.RangeOriginY = h.double()
+	if version >= 300 {
+		hd.MarkJumps = make([]MarkJump, 8)
+	} else {
+		hd.MarkJumps = make([]MarkJump, 4)
+	}
+	for i := range hd.MarkJumps {
+		mj := &hd.MarkJumps[i]
+		mj.Scale = h.double()
+		mj.OriginX = h.double()
+		mj.OriginY = h.double()
+		if version >= 300 {
+			mj.LayerGroup = h.dword()
+		}
+	}
+
+	// Text draw state, stored as dummies before Ver.4.05
+	if version >= 300 {
+		h.double() // dummy
+		h.double() // dummy
+		h.double() // dummy
+		h.dword()  // dummy
+		h.double() // dummy
+		h.double() // dummy
+		margin, mode := h.double(), h.dword()
+		if version >= 405 {
+			hd.TextBackgroundMargin = margin
+			hd.TextBackgroundMode = mode
+		}
+	}
+
+	// Parallel lines
+	for i := range hd.ParallelLineSpacings {
+		hd.ParallelLineSpacings[i] = h.double()
+	}
+	hd.ParallelLineEndExtension = h.double()
+
+	// Pen colors and widths per color number
+	for i := range hd.Pens {
+		hd.Pens[i].ScreenColor = h.dword()
+		hd.Pens[i].ScreenWidth = h.dword()
+	}
+	for i := range hd.Pens {
+		hd.Pens[i].PrinterColor = h.dword()
+		hd.Pens[i].PrinterWidth = h.dword()
+		hd.Pens[i].PointRadius = h.double()
+	}
+
+	// Line types 2-9, random lines 11-15, double-length line types 16-19
+	for n := 2; n <= 9; n++ {
+		hd.LineTypes[n] = readLineTypeDef(h)
+	}
+	for i := range hd.RandomLines {
+		rl := &hd.RandomLines[i]
+		rl.Pattern = h.dword()
+		rl.Amplitude = h.dword()
+		rl.Pitch = h.dword()
+		rl.PrinterAmplitude = h.dword()
+		rl.PrinterPitch = h.dword()
+	}
+	for i := range hd.DoubleLineTypes {
+		hd.DoubleLineTypes[i] = readLineTypeDef(h)
+	}
+
+	// Drawing and printing flags
+	hd.DrawScreenPoints = h.dword()
+	hd.DrawPrinterPoints = h.dword()
+	hd.BitmapFirstDraw = h.dword()
+	hd.ReverseDraw = h.dword()
+	hd.ReverseSearch = h.dword()
+	hd.ColorPrint = h.dword()
+	hd.LayerOrderPrint = h.dword()
+	hd.ColorOrderPrint = h.dword()
+	hd.PrintContinuous = h.dword()
+	hd.PrintCommonGray = h.dword()
+	hd.PrintSkipDisplayOnly = h.dword()
+
+	if version >= 223 {
+		hd.DrawTime = h.dword()
+
+		hd.Eye.InitFlags = h.dword()
+		for i := range hd.Eye.HorizontalAngles {
+			hd.Eye.HorizontalAngles[i] = h.dword()
+		}
+		hd.Eye.PerspectiveHeight = h.double()
+		hd.Eye.PerspectiveDistance = h.double()
+		hd.Eye.BirdsEyeHeight = h.double()
+		hd.Eye.BirdsEyeDistance = h.double()
+		hd.Eye.IsometricAngle = h.double()
+	}
+
+	if version >= 225 {
+		hd.LastLineLength = h.double()
+		hd.LastBoxWidth = h.double()
+		hd.LastBoxHeight = h.double()
+		hd.LastCircleRadius = h.double()
+	}
+
+	if version >= 230 {
+		hd.SolidColorMode = h.dword()
+		hd.SolidColor = h.dword()
+	}
+
+	// SXF extended colors and line types
+	if version >= 420 {
+		hd.SXFColors = make([]SXFColor, 257)
+		for i := range hd.SXFColors {
+			hd.SXFColors[i].ScreenColor = h.dword()
+			hd.SXFColors[i].ScreenWidth = h.dword()
+		}
+		for i := range hd.SXFColors {
+			c := &hd.SXFColors[i]
+			c.Name = h.cstring()
+			c.PrinterColor = h.dword()
+			c.PrinterWidth = h.dword()
+			c.PointRadius = h.double()
+		}
+
+		hd.SXFLineTypes = make([]SXFLineType, 33)
+		for i := range hd.SXFLineTypes {
+			hd.SXFLineTypes[i].LineTypeDef = readLineTypeDef(h)
+		}
+		for i := range hd.SXFLineTypes {
+			lt := &hd.SXFLineTypes[i]
+			lt.Name = h.cstring()
+			lt.Segments = h.dword()
+			for j := range lt.Pitches {
+				lt.Pitches[j] = h.double()
+			}
+		}
+	}
+
+	// Text type presets and the current write style
+	for i := range hd.TextStyles {
+		ts := &hd.TextStyles[i]
+		ts.Width = h.double()
+		ts.Height = h.double()
+		ts.Spacing = h.double()
+		ts.Color = h.dword()
+	}
+	hd.WriteTextStyle.Width = h.double()
+	hd.WriteTextStyle.Height = h.double()
+	hd.WriteTextStyle.Spacing = h.double()
+	hd.WriteTextStyle.Color = h.dword()
+	hd.WriteTextType = h.dword()
+
+	hd.TextArrangeLineSpacing = h.double()
+	hd.TextArrangeCount = h.double()
+
+	hd.TextOffsetEnabled = h.dword()
+	for i := range hd.TextOffsetX {
+		hd.TextOffsetX[i] = h.double()
+	}
+	for i := range hd.TextOffsetY {
+		hd.TextOffsetY[i] = h.double()
+	}
+
+	if h.err != nil {
+		pe := asParseError(h.err, h.offset)
+		pe.Section = SectionHeader
+		return pe
+	}
+	return nil
+}
+
+// readLineTypeDef reads a line type pattern, dot count, pitch and printer pitch.
+func readLineTypeDef(h *headerReader) LineTypeDef {
+	return LineTypeDef{
+		Pattern:      h.dword(),
+		UnitDots:     h.dword(),
+		Pitch:        h.dword(),
+		PrinterPitch: h.dword(),
+	}
+}
+
+// LineWidthIn100thMM reports whether the drawing stores line widths in
+// 1/100 mm (線幅を1/100mm単位とする), which is encoded as a negative
+// MaxDrawWidth. In this mode EntityBase.PenWidth and the printer widths of the
+// pen tables are in 1/100 mm; otherwise the printer widths are printer dots.
+func (hd *Header) LineWidthIn100thMM() bool {
+	return hd.MaxDrawWidth <= -101
+}
+
+// MaxLineDrawWidth returns the maximum line draw width (線描画の最大幅).
+// In 1/100 mm mode files from Ver.6.00 keep the width that was set before the
+// mode was enabled (-201 to -300 for positive, -400 to -500 for zero or
+// negative widths); older files store -101 and 0 is returned.
+func (hd *Header) MaxLineDrawWidth() int32 {
+	switch n := hd.MaxDrawWidth; {
+	case n > -101:
+		return n
+	case n <= -201 && n >= -300:
+		return -n - 200
+	case n <= -400 && n >= -500:
+		return n + 400
+	default:
+		return 0
+	}
+}
+
+// LineWidthDPI returns the printer resolution (300 or 600 dpi) that printer
+// line widths in dots refer to. It is stored in PrintSkipDisplayOnly from
+// Ver.6.00; 0 is returned for older files.
+func (hd *Header) LineWidthDPI() int {
+	switch hd.PrintSkipDisplayOnly / 10 {
+	case 1:
+		return 300
+	case 2:
+		return 600
+	default:
+		return 0
+	}
+}
+
+// DimensionStyle decodes the dimension settings m_lnSunpou1-5 (寸法関係の設定).
+// It reports false when the drawing was saved without them, in which case all
+// settings are zero.
+func (hd *Header) DimensionStyle() (DimensionStyle, bool) {
+	if hd.DimensionSettings == [5]uint32{} {
+		return DimensionStyle{}, false
+	}
+	s1, s2, s3, s4, s5 := hd.DimensionSettings[0], hd.DimensionSettings[1],
+		hd.DimensionSettings[2], hd.DimensionSettings[3], hd.DimensionSettings[4]
+
+	digit := func(v uint32, place uint32) int { return int(v / place % 10) }
+	flag := func(v uint32, place uint32) bool { return digit(v, place) != 0 }
+
+	return DimensionStyle{
+		LineColor:    digit(s1, 1),
+		ExtLineColor: digit(s1, 10),
+		PointColor:   digit(s1, 100),
+		Decimals:     digit(s1, 1000),
+		Meters:       flag(s1, 10000),
+		EndMark:      digit(s1, 100000),
+		TextType:     int(s1 / 1000000 % 100), // 1-10 spans two digits
+
+		TextOffset:       signedTenths(s2 % 10000),
+		ExtLineOvershoot: signedTenths(s2 / 10000 % 10000),
+
+		ArrowLength:        float64(s3%1000) / 10,
+		ArrowAngle:         float64(s3/1000%1000) / 10,
+		ReverseArrowLength: float64(s3/1000000%1000) / 10,
+
+		KeepTextDirection:   flag(s4, 1),
+		FullWidth:           flag(s4, 10),
+		SpaceSeparator:      flag(s4, 100),
+		FullWidthComma:      flag(s4, 1000),
+		FullWidthPoint:      flag(s4, 10000),
+		ShowUnit:            flag(s4, 100000),
+		RadiusMark:          digit(s4, 1000000),
+		RadiusComma:         flag(s4, 10000000),
+		RadiusTrailingZeros: flag(s4, 100000000),
+
+		Italic:             flag(s5, 1),
+		Bold:               flag(s5, 10),
+		AngleUnit:          digit(s5, 100),
+		AngleDecimals:      digit(s5, 1000),
+		Associative:        flag(s5, 10000),
+		SelectByAttributes: flag(s5, 100000),
+		Rounding:           digit(s5, 1000000),
+	}, true
+}
+
+// signedTenths decodes a length stored as tenths of a millimetre, with 1000
+// added for negative values.
+func signedTenths(v uint32) float64 {
+	if v >= 1000 {
+		return -float64(v-1000) / 10
+	}
+	return float64(v) / 10
+}
@@ -0,0 +1,354 @@
+package jww
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// testHeaderWriter builds JWW header bytes in file order for tests.
+type testHeaderWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *testHeaderWriter) dword(v uint32)     { _ = binary.Write(&w.buf, binary.LittleEndian, v) }
+func (w *testHeaderWriter) int32(v int32)      { _ = binary.Write(&w.buf, binary.LittleEndian, v) }
+func (w *testHeaderWriter) double(v float64)   { _ = binary.Write(&w.buf, binary.LittleEndian, v) }
+func (w *testHeaderWriter) dwords(n int)       { w.buf.Write(make([]byte, 4*n)) }
+func (w *testHeaderWriter) doubles(n int)      { w.buf.Write(make([]byte, 8*n)) }
+func (w *testHeaderWriter) cstring(s string)   { w.buf.WriteByte(byte(len(s))); w.buf.WriteString(s) }
+func (w *testHeaderWriter) cstrings(n int)     { w.buf.Write(make([]byte, n)) }
+func (w *testHeaderWriter) bytes() []byte      { return w.buf.Bytes() }
+func (w *testHeaderWriter) lineTypeDefs(n int) { w.dwords(4 * n) }
+
+// buildTestHeader returns the header bytes that follow the version DWORD for
+// the given file version. Recognizable values are written at the start, the
+// middle and the end of the header so tests can detect misalignment.
+func buildTestHeader(version uint32) []byte {
+	w := &testHeaderWriter{}
+
+	w.cstring("memo")
+	w.dword(3) // paper size: A3
+	w.dword(0) // write layer group
+	for g := 0; g < 16; g++ {
+		w.dword(2)       // state
+		w.dword(0)       // write layer
+		w.double(100)    // scale
+		w.dword(0)       // protect
+		w.dwords(16 * 2) // layer state + protect
+	}
+
+	w.dwords(14) // dummy
+	for i := 0; i < 5; i++ {
+		w.dword(uint32(i + 1)) // m_lnSunpou1-5
+	}
+	w.dwords(1)   // dummy
+	w.int32(-101) // max draw width
+	w.doubles(2)  // printer origin
+	w.double(1.5) // printer scale
+	w.dword(0)    // printer settings
+	w.int32(-1)   // grid mode
+	w.double(4)   // grid min spacing
+	w.double(910) // grid spacing X
+	w.double(910) // grid spacing Y
+	w.doubles(2)  // grid origin
+	for g := 0; g < 16; g++ {
+		for l := 0; l < 16; l++ {
+			switch {
+			case g == 0 && l == 1:
+				w.cstring("\x95\xc7") // 壁 in Shift-JIS
+			case g == 0 && l == 2:
+				w.cstring("\x90\xa1\x96\x40") // 寸法 in Shift-JIS
+			default:
+				w.cstring("")
+			}
+		}
+	}
+	w.cstring("\x88\xea\x8a\x4b") // layer group 0: 一階
+	w.cstrings(15)
+
+	w.double(1500) // shadow level
+	w.double(35.5) // latitude
+	w.dword(0)     // 9-15 flag
+	w.double(0)    // wall shadow level
+	if version >= 300 {
+		w.double(0)   // sky map level
+		w.double(200) // sky map diameter
+	}
+	w.dword(1)   // 2.5D unit
+	w.doubles(6) // view + range
+	if version >= 300 {
+		for i := 0; i < 8; i++ {
+			w.doubles(3)
+			w.dword(uint32(i))
+		}
+		w.doubles(3) // dummy
+		w.dwords(1)  // dummy
+		w.doubles(2) // dummy
+		w.double(0.5)
+		w.dword(3)
+	} else {
+		w.doubles(4 * 3)
+	}
+
+	w.doubles(10) // parallel line spacings
+	w.double(0)   // end extension
+	for i := 0; i < 10; i++ {
+		w.dword(0x000000FF) // screen color
+		w.dword(uint32(i))  // screen width
+	}
+	for i := 0; i < 10; i++ {
+		w.dword(0)                 // printer color
+		w.dword(uint32(i * 10))    // printer width
+		w.double(float64(i) / 10.) // point radius
+	}
+	w.lineTypeDefs(8) // 2-9
+	w.dwords(5 * 5)   // random lines
+	w.lineTypeDefs(4) // double-length
+	w.dwords(11)      // drawing/printing flags
+
+	if version >= 223 {
+		w.dword(123456) // draw time
+		w.dwords(1 + 3)
+		w.doubles(5)
+	}
+	if version >= 225 {
+		w.double(1000) // last line length
+		w.doubles(3)
+	}
+	if version >= 230 {
+		w.dword(1)
+		w.dword(0x00FF00)
+	}
+	if version >= 420 {
+		w.dwords(257 * 2)
+		for i := 0; i < 257; i++ {
+			w.cstring("")
+			w.dwords(2)
+			w.doubles(1)
+		}
+		w.lineTypeDefs(33)
+		for i := 0; i < 33; i++ {
+			w.cstring("")
+			w.dword(0)
+			w.doubles(10)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		w.double(float64(i + 1))     // width
+		w.double(float64(i+1) * 1.5) // height
+		w.double(0)                  // spacing
+		w.dword(uint32(i%9 + 1))     // color
+	}
+	w.double(3)   // write text width
+	w.double(3)   // write text height
+	w.double(0.5) // write text spacing
+	w.dword(1)    // write text color
+	w.dword(2)    // write text type
+	w.doubles(2)  // text arrangement
+	w.dword(1)    // text offset enabled
+	w.doubles(3)
+	w.double(0)
+	w.double(0)
+	w.double(7.25) // last field: text offset Y top
+
+	return w.bytes()
+}
+
+func TestParseHeader_Sequential(t *testing.T) {
+	// Each version where the header layout changes, and the one before
+	versions := []uint32{200, 222, 223, 224, 225, 229, 230, 299, 300, 351, 404, 405, 419, 420, 600, 700, 800}
+	for _, version := range versions {
+		doc := &Document{Version: version}
+		jr := NewReader(bytes.NewReader(buildTestHeader(version)))
+		if err := parseHeader(jr, doc); err != nil {
+			t.Fatalf("version %d: parseHeader failed: %v", version, err)
+		}
+
+		hd := &doc.Header
+		if doc.Memo != "memo" || doc.PaperSize != 3 {
+			t.Errorf("version %d: memo/paper: got %q/%d", version, doc.Memo, doc.PaperSize)
+		}
+		if doc.LayerGroups[15].Scale != 100 {
+			t.Errorf("version %d: group F scale: got %v, want 100", version, doc.LayerGroups[15].Scale)
+		}
+		if got := doc.LayerGroups[0].Layers[1].Name; got != "壁" {
+			t.Errorf("version %d: layer 0-1 name: got %q, want %q", version, got, "壁")
+		}
+		if got := doc.LayerGroups[0].Layers[2].Name; got != "寸法" {
+			t.Errorf("version %d: layer 0-2 name: got %q, want %q", version, got, "寸法")
+		}
+		if got := doc.LayerGroups[0].Name; got != "一階" {
+			t.Errorf("version %d: layer group 0 name: got %q, want %q", version, got, "一階")
+		}
+		if hd.DimensionSettings != [5]uint32{1, 2, 3, 4, 5} {
+			t.Errorf("version %d: dimension settings: got %v", version, hd.DimensionSettings)
+		}
+		if hd.MaxDrawWidth != -101 {
+			t.Errorf("version %d: max draw width: got %d, want -101", version, hd.MaxDrawWidth)
+		}
+		if hd.GridMode != -1 || hd.GridSpacingX != 910 {
+			t.Errorf("version %d: grid: got mode %d spacing %v", version, hd.GridMode, hd.GridSpacingX)
+		}
+		if hd.Shadow.Latitude != 35.5 {
+			t.Errorf("version %d: latitude: got %v, want 35.5", version, hd.Shadow.Latitude)
+		}
+		if hd.Pens[9].PrinterWidth != 90 {
+			t.Errorf("version %d: pen 9 printer width: got %d, want 90", version, hd.Pens[9].PrinterWidth)
+		}
+		if hd.TextStyles[9].Height != 15 {
+			t.Errorf("version %d: text style 10 height: got %v, want 15", version, hd.TextStyles[9].Height)
+		}
+		if hd.WriteTextType != 2 {
+			t.Errorf("version %d: write text type: got %d, want 2", version, hd.WriteTextType)
+		}
+		if hd.TextOffsetY[2] != 7.25 {
+			t.Errorf("version %d: last header field: got %v, want 7.25", version, hd.TextOffsetY[2])
+		}
+		if got := jr.BytesRead(); got != int64(len(buildTestHeader(version))) {
+			t.Errorf("version %d: consumed %d bytes, header has %d", version, got, len(buildTestHeader(version)))
+		}
+
+		if version >= 420 && len(hd.SXFColors) != 257 {
+			t.Errorf("version %d: SXF colors: got %d, want 257", version, len(hd.SXFColors))
+		}
+		if version < 420 && hd.SXFColors != nil {
+			t.Errorf("version %d: SXF colors should be absent", version)
+		}
+
+		// Fields added by each version are zero before it
+		checks := []struct {
+			since uint32
+			name  string
+			got   any
+			want  any
+		}{
+			{223, "draw time", hd.DrawTime, uint32(123456)},
+			{225, "last line length", hd.LastLineLength, 1000.0},
+			{230, "solid color", hd.SolidColor, uint32(0x00FF00)},
+			{300, "sky map diameter", hd.SkyMapDiameter, 200.0},
+			{300, "mark jump 8 layer group", markJumpLayerGroup(hd, 7), uint32(7)},
+			{405, "text background margin", hd.TextBackgroundMargin, 0.5},
+			{405, "text background mode", hd.TextBackgroundMode, uint32(3)},
+		}
+		for _, c := range checks {
+			want := c.want
+			if version < c.since {
+				want = reflect.Zero(reflect.TypeOf(c.want)).Interface()
+			}
+			if c.got != want {
+				t.Errorf("version %d: %s: got %v, want %v", version, c.name, c.got, want)
+			}
+		}
+		if want := map[bool]int{true: 8, false: 4}[version >= 300]; len(hd.MarkJumps) != want {
+			t.Errorf("version %d: mark jumps: got %d, want %d", version, len(hd.MarkJumps), want)
+		}
+	}
+}
+
+// markJumpLayerGroup returns the layer group of mark jump i, or 0 when there
+// is none.
+func markJumpLayerGroup(hd *Header, i int) uint32 {
+	if i >= len(hd.MarkJumps) {
+		return 0
+	}
+	return hd.MarkJumps[i].LayerGroup
+}
+
+func TestParseHeader_Truncated(t *testing.T) {
+	data := buildTestHeader(600)
+	jr := NewReader(bytes.NewReader(data[:len(data)-4]))
+	if err := parseHeader(jr, &Document{Version: 600}); err == nil {
+		t.Fatal("expected error for truncated header")
+	}
+}
+
+func TestLineTypeDef_Dashes(t *testing.T) {
+	cases := []struct {
+		name string
+		def  LineTypeDef
+		want []int
+	}{
+		{"dashed", LineTypeDef{Pattern: 0xFFF0, UnitDots: 16}, []int{12, -4}},
+		{"dash dot", LineTypeDef{Pattern: 0b1111110010, UnitDots: 10}, []int{6, -2, 1, -1}},
+		{"starts with gap", LineTypeDef{Pattern: 0b00111100, UnitDots: 8}, []int{4, -4}},
+		{"wraps around", LineTypeDef{Pattern: 0b11000011, UnitDots: 8}, []int{4, -4}},
+		{"ignores high bits", LineTypeDef{Pattern: 0xFF0C, UnitDots: 4}, []int{2, -2}},
+		{"solid", LineTypeDef{Pattern: 0xFF, UnitDots: 8}, nil},
+		{"empty", LineTypeDef{Pattern: 0, UnitDots: 8}, nil},
+		{"undefined", LineTypeDef{Pattern: 0xF0}, nil},
+	}
+
+	for _, c := range cases {
+		got := c.def.Dashes()
+		if !slices.Equal(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestHeader_LineWidthMode(t *testing.T) {
+	cases := []struct {
+		maxDrawWidth int32
+		in100thMM    bool
+		maxWidth     int32
+	}{
+		{5, false, 5},
+		{0, false, 0},
+		{-101, true, 0},
+		{-203, true, 3},
+		{-400, true, 0},
+		{-402, true, -2},
+	}
+
+	for _, c := range cases {
+		hd := Header{MaxDrawWidth: c.maxDrawWidth}
+		if got := hd.LineWidthIn100thMM(); got != c.in100thMM {
+			t.Errorf("%d: LineWidthIn100thMM got %v, want %v", c.maxDrawWidth, got, c.in100thMM)
+		}
+		if got := hd.MaxLineDrawWidth(); got != c.maxWidth {
+			t.Errorf("%d: MaxLineDrawWidth got %d, want %d", c.maxDrawWidth, got, c.maxWidth)
+		}
+	}
+
+	for flag, want := range map[uint32]int{0: 0, 1: 0, 10: 300, 11: 300, 20: 600, 21: 600} {
+		hd := Header{PrintSkipDisplayOnly: flag}
+		if got := hd.LineWidthDPI(); got != want {
+			t.Errorf("PrintSkipDisplayOnly %d: LineWidthDPI got %d, want %d", flag, got, want)
+		}
+	}
+}
+
+func TestHeader_DimensionStyle(t *testing.T) {
+	var hd Header
+	if _, ok := hd.DimensionStyle(); ok {
+		t.Error("expected no dimension style without settings")
+	}
+
+	hd.DimensionSettings = [5]uint32{
+		1102128, // text type 1, arrows, mm, 2 decimals, colors 8/2/1
+		201015,  // overshoot 2.0, offset -1.5
+		150030,  // arrow angle 15.0, length 3.0
+		1000001, // "R" prefix, keep direction
+		2001010, // round up, 1 angle decimal, bold
+	}
+
+	ds, ok := hd.DimensionStyle()
+	if !ok {
+		t.Fatal("expected a dimension style")
+	}
+	want := DimensionStyle{
+		LineColor: 8, ExtLineColor: 2, PointColor: 1,
+		Decimals: 2, EndMark: DimEndArrow, TextType: 1,
+		TextOffset: -1.5, ExtLineOvershoot: 2,
+		ArrowLength: 3, ArrowAngle: 15,
+		KeepTextDirection: true, RadiusMark: DimRadiusMarkPrefix,
+		Bold: true, AngleDecimals: 1, Rounding: DimRoundUp,
+	}
+	if ds != want {
+		t.Errorf("got %+v\nwant %+v", ds, want)
+	}
+}
@@ -0,0 +1,221 @@
+package jww
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxImageSize is the decompressed size limit used by Image.Decompress
+// when no explicit limit is given.
+const DefaultMaxImageSize = 64 << 20
+
+// imageRefPrefix marks text entities that place an image (画像データ文字列).
+const imageRefPrefix = "^@BM"
+
+// parseTextEntity reads a text entity and returns an *ImageRef instead of a
+// *Text when the content is an image reference.
+func parseTextEntity(jr *Reader, version uint32) (Entity, error) {
+	txt, err := parseText(jr, version)
+	if err != nil {
+		return nil, err
+	}
+	if ref, ok := imageRefFromText(txt); ok {
+		return ref, nil
+	}
+	return txt, nil
+}
+
+// imageRefFromText decodes a "^@BM" image reference from a text entity.
+// It returns false when the text is not an image reference or the path
+// is missing.
+func imageRefFromText(txt *Text) (*ImageRef, bool) {
+	if !strings.HasPrefix(txt.Content, imageRefPrefix) {
+		return nil, false
+	}
+
+	fields := strings.Split(txt.Content[len(imageRefPrefix):], ",")
+	path := strings.TrimSpace(fields[0])
+	if path == "" {
+		return nil, false
+	}
+
+	ref := &ImageRef{
+		EntityBase: txt.EntityBase,
+		X:          txt.StartX,
+		Y:          txt.StartY,
+		Angle:      txt.Angle,
+		Path:       path,
+		TrimWidth:  1,
+		TrimHeight: 1,
+		Source:     txt.Content,
+	}
+
+	// Width, height, then the optional trimming start and size ratios
+	params := []*float64{&ref.Width, &ref.Height, &ref.TrimX, &ref.TrimY, &ref.TrimWidth, &ref.TrimHeight}
+	for i, f := range fields[1:] {
+		if i >= len(params) {
+			break
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			break
+		}
+		*params[i] = v
+	}
+
+	return ref, true
+}
+
+// parseImages reads the bundled image files that follow the block definition
+// list in Ver.7.00 and later: a DWORD count, then for each file its name,
+// DWORD size and content. remaining is the number of unread bytes in the
+// input, used to reject corrupt sizes before allocating. The images may hold
+// opts.MaxImageBytes in total, and opts.Context is checked before each one.
+// progress, if not nil, is called after each image. Errors are returned as a
+// *ParseError carrying the index of the failing image.
+func parseImages(jr *Reader, remaining int64, opts ParseOptions, progress func()) ([]Image, error) {
+	count, err := jr.ReadDWORD()
+	if err != nil {
+		return nil, asParseError(err, jr.lastOffset)
+	}
+
+	budget := opts.MaxImageBytes
+	var images []Image
+	for i := 0; i < int(count); i++ {
+		start := jr.BytesRead()
+		if err := opts.Context.Err(); err != nil {
+			pe := asParseError(err, start)
+			pe.Index = i
+			return images, pe
+		}
+		img, err := parseImage(jr, remaining, budget)
+		if err != nil {
+			pe := asParseError(err, jr.lastOffset)
+			pe.Index = i
+			return images, pe
+		}
+		remaining -= jr.BytesRead() - start
+		budget -= int64(len(img.Data))
+
+		images = append(images, *img)
+		if progress != nil {
+			progress()
+		}
+	}
+
+	return images, nil
+}
+
+// parseImage reads the name, size and content of one bundled image file of
+// at most budget bytes.
+func parseImage(jr *Reader, remaining, budget int64) (*Image, error) {
+	start := jr.BytesRead()
+
+	name, err := jr.ReadCString()
+	if err != nil {
+		return nil, err
+	}
+	size, err := jr.ReadDWORD()
+	if err != nil {
+		return nil, err
+	}
+
+	remaining -= jr.BytesRead() - start
+	if int64(size) > remaining {
+		return nil, fmt.Errorf("image %s: size %d exceeds remaining %d bytes", name, size, remaining)
+	}
+	if int64(size) > budget {
+		return nil, fmt.Errorf("%w: image %s of %d bytes, %d bytes left", ErrImageTooLarge, name, size, budget)
+	}
+
+	data := make([]byte, size)
+	if err := jr.ReadBytes(data); err != nil {
+		return nil, err
+	}
+
+	return &Image{Name: name, Data: data}, nil
+}
+
+// Open returns a reader for the original image file content. Compressed
+// (".gz") images are inflated on the fly; both gzip and raw zlib streams are
+// accepted. Uncompressed images are returned as stored.
+func (img *Image) Open() (io.ReadCloser, error) {
+	if !img.IsCompressed() {
+		return io.NopCloser(bytes.NewReader(img.Data)), nil
+	}
+
+	var r io.ReadCloser
+	var err error
+	if len(img.Data) >= 2 && img.Data[0] == 0x1f && img.Data[1] == 0x8b {
+		r, err = gzip.NewReader(bytes.NewReader(img.Data))
+	} else {
+		r, err = zlib.NewReader(bytes.NewReader(img.Data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decompressing image %s: %w", img.Name, err)
+	}
+	return r, nil
+}
+
+// Decompress returns the original image file content; see Open.
+//
+// maxSize limits the decompressed size; DefaultMaxImageSize is used when it
+// is zero or negative. ErrImageTooLarge is returned when the limit would be
+// exceeded.
+func (img *Image) Decompress(maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxImageSize
+	}
+	if !img.IsCompressed() {
+		if int64(len(img.Data)) > maxSize {
+			return nil, ErrImageTooLarge
+		}
+		return img.Data, nil
+	}
+
+	r, err := img.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing image %s: %w", img.Name, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrImageTooLarge
+	}
+	return data, nil
+}
+
+// FindImage returns the bundled image referenced by ref, or nil when ref
+// points to an external file or no bundled image matches its file name.
+func (d *Document) FindImage(ref *ImageRef) *Image {
+	if !ref.IsEmbedded() {
+		return nil
+	}
+	name := ref.FileName()
+	for i := range d.Images {
+		if strings.EqualFold(d.Images[i].FileName(), name) {
+			return &d.Images[i]
+		}
+	}
+	return nil
+}
+
+// imageBaseName strips any directory part and "%temp%" prefix from a path.
+func imageBaseName(path string) string {
+	if len(path) >= 6 && strings.EqualFold(path[:6], "%temp%") {
+		path = path[6:]
+	}
+	if i := strings.LastIndexAny(path, `\/`); i >= 0 {
+		path = path[i+1:]
+	}
+	return path
+}
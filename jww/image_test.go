@@ -0,0 +1,184 @@
+package jww
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"testing"
+)
+
+func TestImageRefFromText(t *testing.T) {
+	txt := &Text{
+		EntityBase: EntityBase{Layer: 3},
+		StartX:     100,
+		StartY:     200,
+		Angle:      30,
+		Content:    "^@BM%temp%photo.jpg,400,300,0.25,0,0.5,1",
+	}
+
+	ref, ok := imageRefFromText(txt)
+	if !ok {
+		t.Fatal("expected image reference")
+	}
+
+	if ref.X != 100 || ref.Y != 200 || ref.Angle != 30 || ref.Layer != 3 {
+		t.Errorf("placement: got (%v, %v) angle %v layer %d", ref.X, ref.Y, ref.Angle, ref.Layer)
+	}
+	if ref.Width != 400 || ref.Height != 300 {
+		t.Errorf("size: got %vx%v, want 400x300", ref.Width, ref.Height)
+	}
+	if ref.TrimX != 0.25 || ref.TrimWidth != 0.5 || ref.TrimHeight != 1 {
+		t.Errorf("trim: got x %v width %v height %v", ref.TrimX, ref.TrimWidth, ref.TrimHeight)
+	}
+	if !ref.IsEmbedded() || ref.FileName() != "photo.jpg" {
+		t.Errorf("path: embedded %v, file name %q", ref.IsEmbedded(), ref.FileName())
+	}
+}
+
+func TestImageRefFromText_Defaults(t *testing.T) {
+	ref, ok := imageRefFromText(&Text{Content: `^@BMC:\scan\plan.bmp,100,75`})
+	if !ok {
+		t.Fatal("expected image reference")
+	}
+	if ref.IsEmbedded() || ref.FileName() != "plan.bmp" {
+		t.Errorf("path: embedded %v, file name %q", ref.IsEmbedded(), ref.FileName())
+	}
+	if ref.TrimX != 0 || ref.TrimY != 0 || ref.TrimWidth != 1 || ref.TrimHeight != 1 {
+		t.Errorf("trim defaults: got %v %v %v %v", ref.TrimX, ref.TrimY, ref.TrimWidth, ref.TrimHeight)
+	}
+
+	for _, content := range []string{"plain text", "^@BM", "^@BM,100,75"} {
+		if _, ok := imageRefFromText(&Text{Content: content}); ok {
+			t.Errorf("%q: unexpected image reference", content)
+		}
+	}
+}
+
+func TestImage_Decompress(t *testing.T) {
+	content := bytes.Repeat([]byte("BM-image-data"), 100)
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write(content)
+	gw.Close()
+
+	var zl bytes.Buffer
+	zw := zlib.NewWriter(&zl)
+	zw.Write(content)
+	zw.Close()
+
+	tests := []struct {
+		name string
+		img  Image
+	}{
+		{"gzip", Image{Name: "a.bmp.gz", Data: gz.Bytes()}},
+		{"zlib", Image{Name: "a.bmp.GZ", Data: zl.Bytes()}},
+		{"stored", Image{Name: "a.bmp", Data: content}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.img.Decompress(0)
+			if err != nil {
+				t.Fatalf("Decompress failed: %v", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("got %d bytes, want %d", len(got), len(content))
+			}
+			if tt.img.FileName() != "a.bmp" {
+				t.Errorf("file name: got %q, want %q", tt.img.FileName(), "a.bmp")
+			}
+
+			if _, err := tt.img.Decompress(int64(len(content) - 1)); !errors.Is(err, ErrImageTooLarge) {
+				t.Errorf("expected ErrImageTooLarge, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParse_Images(t *testing.T) {
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte("BMdata"))
+	gw.Close()
+
+	w := &testHeaderWriter{}
+	w.buf.WriteString("JwwData.")
+	w.dword(700)
+	w.buf.Write(buildTestHeader(700))
+
+	// Entity list: one image reference text
+	w.buf.Write([]byte{1, 0, 0xFF, 0xFF, 0xBC, 0x02, 9, 0})
+	w.buf.WriteString("CDataMoji")
+	w.dword(0)                // group
+	w.buf.WriteByte(1)        // penStyle
+	w.buf.Write([]byte{1, 0}) // penColor
+	w.buf.Write([]byte{1, 0}) // penWidth
+	w.buf.Write([]byte{0, 0}) // layer
+	w.buf.Write([]byte{0, 0}) // layerGroup
+	w.buf.Write([]byte{0, 0}) // flag
+	w.double(10)              // startX
+	w.double(20)              // startY
+	w.doubles(2)              // end
+	w.dword(1)                // textType
+	w.doubles(4)              // size, spacing, angle
+	w.cstring("")
+	w.cstring("^@BM%temp%photo.bmp,100,75")
+
+	w.buf.Write([]byte{0, 0}) // block definitions
+
+	// Bundled images
+	w.dword(1)
+	w.cstring("photo.bmp.gz")
+	w.dword(uint32(gz.Len()))
+	w.buf.Write(gz.Bytes())
+
+	doc, err := Parse(bytes.NewReader(w.bytes()))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(doc.Entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(doc.Entities))
+	}
+	ref, ok := doc.Entities[0].(*ImageRef)
+	if !ok {
+		t.Fatalf("expected *ImageRef, got %T", doc.Entities[0])
+	}
+	if ref.X != 10 || ref.Y != 20 || ref.Width != 100 {
+		t.Errorf("image ref: got (%v, %v) width %v", ref.X, ref.Y, ref.Width)
+	}
+
+	if len(doc.Images) != 1 {
+		t.Fatalf("expected 1 image, got %d", len(doc.Images))
+	}
+	img := doc.FindImage(ref)
+	if img == nil {
+		t.Fatal("FindImage returned nil")
+	}
+	data, err := img.Decompress(0)
+	if err != nil {
+		t.Fatalf("Decompress failed: %v", err)
+	}
+	if string(data) != "BMdata" {
+		t.Errorf("image data: got %q, want %q", data, "BMdata")
+	}
+}
+
+func TestParseImages_CorruptSize(t *testing.T) {
+	w := &testHeaderWriter{}
+	w.dword(1)
+	w.cstring("big.bmp")
+	w.dword(0xFFFFFFF0)
+
+	jr := NewReader(bytes.NewReader(w.bytes()))
+	_, err := parseImages(jr, int64(len(w.bytes())), ParseOptions{}.withDefaults(), nil)
+	if err == nil {
+		t.Fatal("expected error for image size beyond end of data")
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Index != 0 || pe.Offset != int64(len(w.bytes())-4) {
+		t.Errorf("expected a *ParseError for image 0 at the size field, got %v", err)
+	}
+}
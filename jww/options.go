@@ -0,0 +1,235 @@
+package jww
+
+import (
+	"context"
+	"errors"
+)
+
+// ParseMode selects how ParseWithOptions handles damaged and suspicious data.
+type ParseMode int
+
+const (
+	// Standard fails on damaged data and reports suspicious data, such as
+	// null objects in lists or trailing bytes, in Document.Warnings. Parse
+	// uses this mode.
+	Standard ParseMode = iota
+
+	// Lenient recovers from damaged data: the damaged object is reported in
+	// Document.Warnings and parsing resumes at the next object of a known
+	// class, keeping everything parsed so far. Only a damaged header still
+	// fails.
+	Lenient
+
+	// Strict fails on damaged data and on anything suspicious, including
+	// layer numbers out of range and implausible coordinate values.
+	Strict
+)
+
+// Default limits used by ParseWithOptions for limits left at zero. They are
+// far above what Jw_cad writes while keeping crafted files from exhausting
+// memory.
+const (
+	// DefaultMaxEntities is the default of ParseOptions.MaxEntities.
+	DefaultMaxEntities = 1 << 24
+
+	// DefaultMaxStringLen is the default of ParseOptions.MaxStringLen.
+	DefaultMaxStringLen = 1 << 20
+
+	// DefaultMaxBlockDepth is the default of ParseOptions.MaxBlockDepth.
+	DefaultMaxBlockDepth = 16
+
+	// DefaultMaxImageBytes is the default of ParseOptions.MaxImageBytes.
+	DefaultMaxImageBytes = 1 << 30
+
+	// DefaultMaxFileBytes is the default of ParseOptions.MaxFileBytes.
+	DefaultMaxFileBytes = 2 << 30
+)
+
+// ParseOptions configures ParseWithOptions.
+//
+// The limits protect against crafted files, whose counts and lengths would
+// otherwise be trusted. Limits that are zero or negative use the defaults
+// above. Exceeding a limit fails with a *ParseError wrapping
+// ErrFileTooLarge, ErrTooManyEntities, ErrStringTooLong, ErrBlockTooDeep or
+// ErrImageTooLarge, in every mode.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+//	defer cancel()
+//	doc, err := jww.ParseWithOptions(upload, jww.ParseOptions{
+//		Context:     ctx,
+//		MaxEntities: 1_000_000,
+//	})
+//	if errors.Is(err, jww.ErrTooManyEntities) || errors.Is(err, context.DeadlineExceeded) {
+//		return errRejected
+//	}
+type ParseOptions struct {
+	// Mode selects the handling of damaged and suspicious data.
+	Mode ParseMode
+
+	// Context cancels parsing: it is checked every checkInterval objects
+	// and before each bundled image, and its error is returned wrapped in a
+	// *ParseError. nil means context.Background().
+	Context context.Context
+
+	// MaxEntities limits the number of entities and block definitions read,
+	// including the entities of the block definitions. Lists declaring more
+	// are rejected before they are read.
+	MaxEntities int
+
+	// MaxStringLen limits the length in bytes of each string (CString), as
+	// stored in the file.
+	MaxStringLen int
+
+	// MaxBlockDepth limits the nesting of block definitions (CDataList
+	// objects) within the entity lists of block definitions. 1 allows only
+	// the block definitions of the block definition list.
+	MaxBlockDepth int
+
+	// MaxImageBytes limits the total size of the bundled images as stored in
+	// the file (Ver.7.00 and later).
+	MaxImageBytes int64
+
+	// MaxFileBytes limits the size of the input. ParseWithOptions holds the
+	// whole input in memory and stops reading past the limit; a Decoder
+	// rejects files of a larger size.
+	MaxFileBytes int64
+
+	// Progress, when set, is called as parsing starts each section of the
+	// file, every checkInterval entities and once parsing is done.
+	Progress ProgressFunc
+}
+
+// ProgressStage is the stage of parsing or conversion reported in Progress.
+type ProgressStage int
+
+const (
+	// StageHeader is the reading of the signature, version and header.
+	StageHeader ProgressStage = iota + 1
+
+	// StageEntities is the decoding of the entity list (データリスト).
+	StageEntities
+
+	// StageBlockDefs is the decoding of the block definitions (ブロック図形定義).
+	StageBlockDefs
+
+	// StageImages is the reading of the bundled images (同梱画像).
+	StageImages
+
+	// StageConverting is the conversion of the entities to DXF, reported by
+	// the dxf package.
+	StageConverting
+
+	// StageWriting is the writing of the DXF file, reported by the dxf
+	// package.
+	StageWriting
+)
+
+// stageNames are the names returned by ProgressStage.String.
+var stageNames = [...]string{
+	StageHeader:     "header",
+	StageEntities:   "entities",
+	StageBlockDefs:  "blocks",
+	StageImages:     "images",
+	StageConverting: "converting",
+	StageWriting:    "writing",
+}
+
+// String returns the name of the stage, e.g. "entities".
+func (s ProgressStage) String() string {
+	if s <= 0 || int(s) >= len(stageNames) {
+		return ""
+	}
+	return stageNames[s]
+}
+
+// MarshalText encodes the stage by name, so that it appears as a string in
+// JSON.
+func (s ProgressStage) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Progress describes how far parsing or conversion has got.
+type Progress struct {
+	// Stage is the stage being worked on.
+	Stage ProgressStage
+
+	// Bytes is the number of bytes of the JWW file read so far, or in
+	// StageWriting of the DXF file written so far.
+	Bytes int64
+
+	// TotalBytes is the size of the JWW file, or 0 when unknown, as in
+	// StageWriting.
+	TotalBytes int64
+
+	// Entities is the number of entities decoded so far (block definitions
+	// included, as counted against MaxEntities), or in StageConverting and
+	// StageWriting the number converted or written.
+	Entities int
+
+	// TotalEntities is the number of entities of the stage, or 0 when not
+	// known in advance, as when decoding.
+	TotalEntities int
+}
+
+// ProgressFunc receives progress reports. It is called synchronously from
+// the goroutine parsing or converting, so it should return quickly.
+//
+// Example:
+//
+//	doc, err := jww.ParseWithOptions(f, jww.ParseOptions{
+//		Progress: func(p jww.Progress) {
+//			fmt.Printf("%s: %d/%d bytes\n", p.Stage, p.Bytes, p.TotalBytes)
+//		},
+//	})
+type ProgressFunc func(Progress)
+
+// report calls the Progress function of the options, if any.
+func (o ParseOptions) report(p Progress) {
+	if o.Progress != nil {
+		o.Progress(p)
+	}
+}
+
+// checkInterval is the number of objects read between checks of
+// ParseOptions.Context.
+const checkInterval = 1024
+
+// withDefaults returns the options with defaults for the context and the
+// limits left at zero.
+func (o ParseOptions) withDefaults() ParseOptions {
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
+	if o.MaxEntities <= 0 {
+		o.MaxEntities = DefaultMaxEntities
+	}
+	if o.MaxStringLen <= 0 {
+		o.MaxStringLen = DefaultMaxStringLen
+	}
+	if o.MaxBlockDepth <= 0 {
+		o.MaxBlockDepth = DefaultMaxBlockDepth
+	}
+	if o.MaxImageBytes <= 0 {
+		o.MaxImageBytes = DefaultMaxImageBytes
+	}
+	if o.MaxFileBytes <= 0 {
+		o.MaxFileBytes = DefaultMaxFileBytes
+	}
+	return o
+}
+
+// exceedsLimit reports whether err is a limit of ParseOptions being exceeded
+// or the context being done, which lenient parsing does not recover from.
+func exceedsLimit(err error) bool {
+	for _, target := range []error{
+		ErrFileTooLarge, ErrTooManyEntities, ErrStringTooLong, ErrBlockTooDeep, ErrImageTooLarge,
+		context.Canceled, context.DeadlineExceeded,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
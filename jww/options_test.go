@@ -0,0 +1,223 @@
+package jww
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// nestedBlockDefs returns a file whose block definition list holds a block
+// definition nested depth levels deep. Jw_cad does not nest them, so the file
+// is rejected, but only once the nested definitions have been decoded.
+func nestedBlockDefs(depth int) []byte {
+	w := &archiveWriter{}
+	w.word(0)
+	w.word(1)
+	w.newClass("CDataList")
+	w.blockDef(1, "A")
+	for i := 2; i <= depth; i++ {
+		w.word(1)
+		w.word(0x8001)
+		w.blockDef(uint32(i), "A")
+	}
+	w.word(0)
+	return jwwFile(w)
+}
+
+func TestParseWithOptions_Limits(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		opts ParseOptions
+		want error
+	}{
+		{"entity count", decoderTestFile(), ParseOptions{MaxEntities: 2}, ErrTooManyEntities},
+		{"block definition entities", decoderTestFile(), ParseOptions{MaxEntities: 4}, ErrTooManyEntities},
+		{"string length", func() []byte {
+			w := &archiveWriter{}
+			w.word(0)
+			w.word(1)
+			w.newClass("CDataList")
+			w.blockDef(1, "LONGER_NAME")
+			w.word(0)
+			return jwwFile(w)
+		}(), ParseOptions{MaxStringLen: 8}, ErrStringTooLong},
+		{"block depth", nestedBlockDefs(3), ParseOptions{MaxBlockDepth: 2}, ErrBlockTooDeep},
+		{"image bytes", imageFile(100, 100), ParseOptions{MaxImageBytes: 150}, ErrImageTooLarge},
+		{"file size", decoderTestFile(), ParseOptions{MaxFileBytes: 64}, ErrFileTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, mode := range []ParseMode{Standard, Lenient, Strict} {
+				opts := tt.opts
+				opts.Mode = mode
+				_, err := ParseWithOptions(bytes.NewReader(tt.data), opts)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("mode %d: expected %v, got %v", mode, tt.want, err)
+				}
+				var pe *ParseError
+				if !errors.As(err, &pe) {
+					t.Errorf("mode %d: expected a *ParseError, got %T", mode, err)
+				}
+			}
+
+			if _, err := ParseWithOptions(bytes.NewReader(tt.data), ParseOptions{}); errors.Is(err, tt.want) {
+				t.Errorf("default limits: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseWithOptions_WithinLimits(t *testing.T) {
+	opts := ParseOptions{MaxEntities: 5, MaxBlockDepth: 3, MaxImageBytes: 200}
+	for _, data := range [][]byte{decoderTestFile(), imageFile(100, 100)} {
+		opts.MaxFileBytes = int64(len(data))
+		if _, err := ParseWithOptions(bytes.NewReader(data), opts); err != nil {
+			t.Errorf("ParseWithOptions failed: %v", err)
+		}
+	}
+}
+
+// imageFile returns a Ver.7.00 file bundling images of the given sizes.
+func imageFile(sizes ...int) []byte {
+	w := &testHeaderWriter{}
+	w.buf.WriteString("JwwData.")
+	w.dword(700)
+	w.buf.Write(buildTestHeader(700))
+	w.buf.Write([]byte{0, 0, 0, 0}) // No entities or block definitions
+
+	w.dword(uint32(len(sizes)))
+	for _, size := range sizes {
+		w.cstring("a.bmp")
+		w.dword(uint32(size))
+		w.buf.Write(make([]byte, size))
+	}
+	return w.bytes()
+}
+
+func TestParseWithOptions_Context(t *testing.T) {
+	// Enough entities for the context to be checked
+	w := &archiveWriter{}
+	w.word(2 * checkInterval)
+	w.newClass("CDataSen")
+	w.line(0)
+	for i := 1; i < 2*checkInterval; i++ {
+		w.word(0x8001)
+		w.line(0)
+	}
+	w.word(0)
+	data := jwwFile(w)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := ParseWithOptions(bytes.NewReader(data), ParseOptions{Context: ctx}); err != nil {
+		t.Fatalf("ParseWithOptions failed: %v", err)
+	}
+
+	cancel()
+	for _, mode := range []ParseMode{Standard, Lenient} {
+		_, err := ParseWithOptions(bytes.NewReader(data), ParseOptions{Mode: mode, Context: ctx})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("mode %d: expected context.Canceled, got %v", mode, err)
+		}
+	}
+
+	// Canceled while reading the entity list
+	ar := newArchive(NewReader(bytes.NewReader(data[len(data)-len(w.buf.Bytes()):])), 600)
+	ar.setOptions(ParseOptions{Context: ctx}.withDefaults())
+	var pe *ParseError
+	if _, err := ar.readEntityList(); !errors.As(err, &pe) || pe.Index != checkInterval-1 {
+		t.Errorf("expected a *ParseError at entity %d, got %v", checkInterval-1, err)
+	}
+}
+
+func TestDecoder_Limits(t *testing.T) {
+	data := decoderTestFile()
+	dec := NewDecoderWithOptions(bytes.NewReader(data), int64(len(data)), ParseOptions{MaxEntities: 4})
+
+	// The entity list is within the limit, its block definition is not
+	for _, err := range dec.Entities() {
+		if err != nil {
+			t.Fatalf("Entities failed: %v", err)
+		}
+	}
+	if _, err := dec.BlockDefs(); !errors.Is(err, ErrTooManyEntities) {
+		t.Errorf("expected ErrTooManyEntities, got %v", err)
+	}
+
+	dec = NewDecoderWithOptions(bytes.NewReader(data), int64(len(data)), ParseOptions{MaxFileBytes: 64})
+	var pe *ParseError
+	if _, err := dec.Header(); !errors.Is(err, ErrFileTooLarge) || !errors.As(err, &pe) || pe.Offset != 64 {
+		t.Errorf("expected ErrFileTooLarge at offset 64, got %v", err)
+	}
+}
+
+func TestParseWithOptions_Progress(t *testing.T) {
+	w := &archiveWriter{}
+	w.word(2 * checkInterval)
+	w.newClass("CDataSen")
+	w.line(0)
+	for i := 1; i < 2*checkInterval; i++ {
+		w.word(0x8001)
+		w.line(0)
+	}
+	w.word(0)
+	data := jwwFile(w)
+	size := int64(len(data))
+
+	var reports []Progress
+	opts := ParseOptions{Progress: func(p Progress) { reports = append(reports, p) }}
+	if _, err := ParseWithOptions(bytes.NewReader(data), opts); err != nil {
+		t.Fatalf("ParseWithOptions failed: %v", err)
+	}
+
+	want := []Progress{
+		{Stage: StageHeader, TotalBytes: size},
+		{Stage: StageEntities, Bytes: size - int64(len(w.buf.Bytes())), TotalBytes: size},
+		{Stage: StageEntities, Entities: checkInterval},
+		{Stage: StageEntities, Entities: 2 * checkInterval},
+		{Stage: StageBlockDefs, Bytes: size - 2, TotalBytes: size, Entities: 2 * checkInterval},
+		{Stage: StageBlockDefs, Bytes: size, TotalBytes: size, Entities: 2 * checkInterval},
+	}
+	if len(reports) != len(want) {
+		t.Fatalf("expected %d reports, got %d: %+v", len(want), len(reports), reports)
+	}
+	for i, p := range reports {
+		if want[i].Bytes == 0 && i > 0 {
+			// Offsets within the entity list
+			want[i].Bytes, want[i].TotalBytes = p.Bytes, size
+		}
+		if p != want[i] {
+			t.Errorf("report %d: got %+v, want %+v", i, p, want[i])
+		}
+	}
+
+	// The Decoder reports each read of the entity list
+	reports = nil
+	dec := NewDecoderWithOptions(bytes.NewReader(data), size, opts)
+	if _, err := dec.Images(); err != nil {
+		t.Fatalf("Images failed: %v", err)
+	}
+	stages := make([]ProgressStage, len(reports))
+	for i, p := range reports {
+		stages[i] = p.Stage
+	}
+	if last := reports[len(reports)-1]; last.Bytes != size || last.Entities != 2*checkInterval {
+		t.Errorf("last report: got %+v", last)
+	}
+	wantStages := []ProgressStage{StageHeader, StageEntities, StageEntities, StageEntities, StageEntities, StageBlockDefs, StageBlockDefs, StageBlockDefs}
+	if !slices.Equal(stages, wantStages) {
+		t.Errorf("stages: got %v, want %v", stages, wantStages)
+	}
+}
+
+func TestProgressStage_String(t *testing.T) {
+	if got := StageBlockDefs.String(); got != "blocks" {
+		t.Errorf("got %q, want %q", got, "blocks")
+	}
+	if got := ProgressStage(0).String(); got != "" {
+		t.Errorf("got %q for an invalid stage", got)
+	}
+}
@@ -1,26 +1,35 @@
 package jww
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Parse reads a JWW (Jw_cad) file from the provided reader and returns a parsed Document.
 //
 // The function reads the entire file into memory, validates the JWW signature,
 // and parses the binary structure according to the MFC CArchive serialization format.
-// It extracts layer information, drawing entities, and block definitions.
+// The header is decoded field by field in file order, followed by the entity
+// list, the block definitions and, from Ver.7.00, the bundled image files.
+// It extracts layer information, drawing settings, drawing entities, block
+// definitions and images.
 //
 // The JWW file format uses:
 //   - Little-endian byte order
 //   - Shift-JIS text encoding (converted to UTF-8)
-//   - MFC CArchive serialization with PID tracking
+//   - MFC CArchive object serialization, with classes and objects shared
+//     across the entity list and the block definitions
 //
 // Returns an error if:
 //   - The file cannot be read
-//   - The file signature is invalid (not "JwwData.")
-//   - The file structure is corrupted or unsupported
+//   - The file signature is invalid (not "JwwData."), or the file is too
+//     short to hold one: a *ParseError wrapping ErrInvalidSignature
+//   - The file structure is corrupted or unsupported: a *ParseError
 //
 // Example:
 //
@@ -37,388 +46,359 @@ import (
 //
 //	fmt.Printf("Version: %d, Entities: %d\n", doc.Version, len(doc.Entities))
 func Parse(r io.Reader) (*Document, error) {
+	return ParseWithOptions(r, ParseOptions{})
+}
+
+// ParseWithOptions is like Parse, with options selecting how damaged and
+// suspicious data is handled. Problems that did not stop parsing are listed
+// in Document.Warnings.
+//
+// In Lenient mode damaged objects are skipped, and parsing resumes at the
+// next object of a known class: the document holds everything that could be
+// read. An error is only returned when the signature, version or header
+// cannot be read, a limit of opts is exceeded or its context is done.
+//
+// Example:
+//
+//	doc, err := jww.ParseWithOptions(f, jww.ParseOptions{Mode: jww.Lenient})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, w := range doc.Warnings {
+//	    log.Printf("%s: %s", name, w)
+//	}
+func ParseWithOptions(r io.Reader, opts ParseOptions) (*Document, error) {
+	opts = opts.withDefaults()
+
 	// Read entire file into memory for simpler parsing
-	data, err := io.ReadAll(r)
+	data, err := io.ReadAll(io.LimitReader(r, opts.MaxFileBytes+1))
 	if err != nil {
 		return nil, fmt.Errorf("reading file: %w", err)
 	}
-
-	// Validate signature
-	if len(data) < 8 || string(data[:8]) != "JwwData." {
-		return nil, ErrInvalidSignature
+	if int64(len(data)) > opts.MaxFileBytes {
+		return nil, fileTooLarge(opts.MaxFileBytes)
 	}
-
-	jr := NewReader(bytes.NewReader(data))
-
-	// Skip signature
-	jr.Skip(8)
-
-	doc := &Document{}
-
-	// Read version
-	version, err := jr.ReadDWORD()
-	if err != nil {
-		return nil, fmt.Errorf("reading version: %w", err)
-	}
-	doc.Version = version
-
-	// Read file memo
-	memo, err := jr.ReadCString()
-	if err != nil {
-		return nil, fmt.Errorf("reading memo: %w", err)
+	if err := opts.Context.Err(); err != nil {
+		return nil, err
 	}
-	doc.Memo = memo
 
-	// Read paper size
-	paperSize, err := jr.ReadDWORD()
-	if err != nil {
-		return nil, fmt.Errorf("reading paper size: %w", err)
-	}
-	doc.PaperSize = paperSize
+	size := int64(len(data))
+	opts.report(Progress{Stage: StageHeader, TotalBytes: size})
 
-	// Read write layer group
-	writeGLay, err := jr.ReadDWORD()
+	jr := NewReader(bytes.NewReader(data))
+	jr.maxStringLen = opts.MaxStringLen
+	doc, err := readFileHeader(jr, opts.Mode)
 	if err != nil {
-		return nil, fmt.Errorf("reading write layer group: %w", err)
-	}
-	doc.WriteLayerGroup = writeGLay
-
-	// Read layer groups (16 groups)
-	for gLay := 0; gLay < 16; gLay++ {
-		lg := &doc.LayerGroups[gLay]
-
-		state, _ := jr.ReadDWORD()
-		lg.State = state
-
-		writeLay, _ := jr.ReadDWORD()
-		lg.WriteLayer = writeLay
-
-		scale, _ := jr.ReadDouble()
-		lg.Scale = scale
-
-		protect, _ := jr.ReadDWORD()
-		lg.Protect = protect
-
-		for lay := 0; lay < 16; lay++ {
-			layState, _ := jr.ReadDWORD()
-			lg.Layers[lay].State = layState
-
-			layProtect, _ := jr.ReadDWORD()
-			lg.Layers[lay].Protect = layProtect
-		}
+		return nil, err
 	}
+	version := doc.Version
 
-	// Find entity list start by scanning for the first CData class pattern
-	// Pattern: [count DWORD] [0xFF 0xFF] [schema WORD] [name_len WORD] ["CData..."]
-	entityListOffset := findEntityListOffset(data, version)
-	if entityListOffset < 0 {
-		return nil, fmt.Errorf("could not find entity list in file")
+	// Entities and block definitions are objects of one MFC archive
+	ar := newArchive(jr, version)
+	ar.setOptions(opts)
+	ar.data = data
+	ar.size = size
+	if opts.Mode == Lenient {
+		ar.blockDefListAt = findBlockDefList(data, jr.BytesRead())
 	}
 
-	// Parse entities from found offset
-	jr2 := NewReader(bytes.NewReader(data[entityListOffset:]))
-	entities, bytesRead, err := parseEntityListWithOffset(jr2, version)
+	// Parse entities (immediately after the header)
+	ar.section = SectionEntities
+	ar.progress()
+	doc.Entities, err = ar.readEntityList()
 	if err != nil {
-		return nil, fmt.Errorf("parsing entity list: %w", err)
+		return nil, err
 	}
-	doc.Entities = entities
 
 	// Parse block definitions (immediately after entity list)
-	jr3 := NewReader(bytes.NewReader(data[entityListOffset+bytesRead:]))
-	blockDefs, err := parseBlockDefList(jr3, version)
-	if err != nil {
-		// Block definitions might not exist in all files, just continue
-		blockDefs = nil
+	ar.section = SectionBlockDefs
+	ar.progress()
+	if jr.BytesRead() < int64(len(data)) {
+		doc.BlockDefs, err = ar.readBlockDefList()
+		if err != nil {
+			return nil, err
+		}
+	} else if err := ar.suspicious("file ends before the block definition list"); err != nil {
+		return nil, err
 	}
-	doc.BlockDefs = blockDefs
-
-	// Parse layer names from earlier in the file
-	parseLayerNames(data, doc)
-
-	return doc, nil
-}
 
-// findEntityListOffset scans the file for the entity list start position.
-// The entity list is preceded by [count DWORD] and starts with a class definition.
-func findEntityListOffset(data []byte, version uint32) int {
-	// Look for the pattern: DWORD count followed by 0xFF 0xFF (new class marker)
-	// followed by version schema and "CData" class name
-
-	schemaBytes := []byte{byte(version & 0xFF), byte((version >> 8) & 0xFF)}
-
-	for i := 100; i < len(data)-20; i++ {
-		// Check for 0xFF 0xFF (new class marker)
-		if data[i] == 0xFF && data[i+1] == 0xFF {
-			// Check schema version matches
-			if data[i+2] == schemaBytes[0] && data[i+3] == schemaBytes[1] {
-				// Check if class name starts with "CData"
-				nameLen := int(data[i+4]) + int(data[i+5])*256
-				if nameLen >= 8 && nameLen <= 20 && i+6+nameLen <= len(data) {
-					className := string(data[i+6 : i+6+nameLen])
-					if len(className) >= 5 && className[:5] == "CData" {
-						// Found first entity class definition
-						// The count WORD is right before this (2 bytes)
-						return i - 2
-					}
-				}
+	// Bundled image files (Ver.7.00+) follow the block definitions
+	if version >= 700 && jr.BytesRead() < int64(len(data)) {
+		ar.section = SectionImages
+		ar.progress()
+		doc.Images, err = parseImages(jr, int64(len(data))-jr.BytesRead(), opts, ar.progress)
+		if err != nil {
+			err = sectionError(err, SectionImages)
+			if opts.Mode != Lenient || exceedsLimit(err) {
+				return nil, err
 			}
+			ar.warn(err)
 		}
 	}
 
-	return -1
-}
-
-// parseEntityListWithOffset parses the entity list and returns bytes consumed.
-func parseEntityListWithOffset(jr *Reader, version uint32) ([]Entity, int, error) {
-	startBytes := jr.BytesRead()
-
-	countWord, err := jr.ReadWORD()
-	if err != nil {
-		return nil, 0, fmt.Errorf("reading entity count: %w", err)
-	}
-	count := uint32(countWord)
-
-	entities := make([]Entity, 0, count)
-
-	// MFC CArchive PID tracking:
-	// - Each new class definition gets a PID
-	// - Each object also gets a PID
-	// - PIDs are assigned sequentially starting from 1
-	// - Class references use 0x8000 | class_PID
-	pidToClassName := make(map[uint32]string) // PID -> class name
-	nextPID := uint32(1)
-
-	for i := uint32(0); i < count; i++ {
-		entity, newPID, err := parseEntityWithPIDTracking(jr, version, pidToClassName, nextPID)
-		if err != nil {
-			return entities, 0, fmt.Errorf("parsing entity %d/%d: %w", i+1, count, err)
-		}
-		nextPID = newPID
-		if entity != nil {
-			entities = append(entities, entity)
+	if n := int64(len(data)) - jr.BytesRead(); n > 0 && err == nil {
+		if err := ar.suspicious("%d bytes of trailing data", n); err != nil {
+			return nil, err
 		}
 	}
+	doc.Warnings = append(doc.Warnings, ar.warnings...)
+	ar.progress()
 
-	bytesConsumed := jr.BytesRead() - startBytes
-	return entities, int(bytesConsumed), nil
+	return doc, nil
 }
 
-// parseEntityWithPIDTracking parses an entity using MFC CArchive PID tracking.
-// In MFC serialization:
-// - 0xFFFF = new class definition follows (schema + name), then assign PID to class
-// - 0x8000 = null object
-// - 0x8000 | n = reference to class with PID n
-// After parsing each object, assign a new PID to that object too.
-func parseEntityWithPIDTracking(jr *Reader, version uint32, pidToClassName map[uint32]string, nextPID uint32) (Entity, uint32, error) {
-	classID, err := jr.ReadWORD()
-	if err != nil {
-		return nil, nextPID, err
-	}
-
-	var className string
-
-	if classID == 0xFFFF {
-		// New class definition
-		schemaVer, err := jr.ReadWORD()
-		if err != nil {
-			return nil, nextPID, fmt.Errorf("reading schema version: %w", err)
-		}
-		_ = schemaVer
+// ParseHeader reads only the signature, version and header of a JWW file: the
+// memo, paper size, layer and layer group names, states and scales, drawing
+// time and the other drawing settings. It stops before the entity list,
+// decoding no entities, which makes it suitable for indexing many files.
+// The header is decoded by the same code as in Parse, and default names are
+// given to unnamed layers and layer groups in the same way.
+//
+// The returned Document has no entities, block definitions or images. The
+// bundled images are stored after the entity list; use Decoder.Images to read
+// their names without keeping the entities.
+//
+// r is read through a buffer, so it may be read past the end of the header.
+//
+// Example:
+//
+//	doc, err := jww.ParseHeader(f)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("Version: %d, Memo: %s\n", doc.Version, doc.Memo)
+func ParseHeader(r io.Reader) (*Document, error) {
+	return readFileHeader(NewReader(bufio.NewReader(r)), Standard)
+}
 
-		nameLen, err := jr.ReadWORD()
-		if err != nil {
-			return nil, nextPID, fmt.Errorf("reading class name length: %w", err)
+// readFileHeader reads the signature, the version and the header (memo,
+// layer states, settings) up to the entity list, and returns them as a
+// Document without entities. Unnamed layers and layer groups get their
+// default names. Versions newer than maxVersion are only read in Lenient
+// mode, with a warning.
+func readFileHeader(jr *Reader, mode ParseMode) (*Document, error) {
+	if err := jr.ReadSignature(); err != nil {
+		if err != ErrInvalidSignature {
+			// Too short to hold a signature
+			err = fmt.Errorf("%w: %w", ErrInvalidSignature, io.ErrUnexpectedEOF)
 		}
+		return nil, sectionError(asParseError(err, 0), SectionHeader)
+	}
 
-		nameBuf := make([]byte, nameLen)
-		if err := jr.ReadBytes(nameBuf); err != nil {
-			return nil, nextPID, fmt.Errorf("reading class name: %w", err)
-		}
-		className = string(nameBuf)
-
-		// Assign PID to this class definition
-		pidToClassName[nextPID] = className
-		nextPID++
-	} else if classID == 0x8000 {
-		// Null object
-		return nil, nextPID, nil
-	} else {
-		// Class reference: 0x8000 | class_PID
-		// The lower bits contain the PID of the class definition
-		classPID := uint32(classID & 0x7FFF)
-		var ok bool
-		className, ok = pidToClassName[classPID]
-		if !ok {
-			return nil, nextPID, fmt.Errorf("unknown class PID: %d (have PIDs: %v)", classPID, getKeys(pidToClassName))
+	version, err := jr.ReadDWORD()
+	if err != nil {
+		return nil, sectionError(asParseError(err, jr.lastOffset), SectionHeader)
+	}
+	doc := &Document{Version: version}
+	if version < minVersion || version > maxVersion {
+		pe := asParseError(fmt.Errorf("%w: %d", ErrUnsupportedVersion, version), jr.lastOffset)
+		pe.Section = SectionHeader
+		if version < minVersion || mode != Lenient {
+			return nil, pe
 		}
+		// Newer files are read with the latest known layout
+		doc.Warnings = append(doc.Warnings, pe.warning())
 	}
 
-	// Parse the object based on class name
-	var entity Entity
-	switch className {
-	case "CDataSen":
-		entity, err = parseLine(jr, version)
-	case "CDataEnko":
-		entity, err = parseArc(jr, version)
-	case "CDataTen":
-		entity, err = parsePoint(jr, version)
-	case "CDataMoji":
-		entity, err = parseText(jr, version)
-	case "CDataSolid":
-		entity, err = parseSolid(jr, version)
-	case "CDataBlock":
-		entity, err = parseBlock(jr, version)
-	case "CDataSunpou":
-		entity, err = parseDimension(jr, version)
-	default:
-		return nil, nextPID, fmt.Errorf("unknown entity class: %s", className)
+	if err := parseHeader(jr, doc); err != nil {
+		return nil, err
 	}
 
-	if err != nil {
-		return nil, nextPID, err
-	}
+	// Fill in default names for unnamed layers and layer groups
+	setDefaultLayerNames(doc)
 
-	// Assign PID to this object
-	nextPID++
+	return doc, nil
+}
 
-	return entity, nextPID, nil
+// fileTooLarge returns the error for an input larger than limit bytes,
+// located at the first byte past the limit.
+func fileTooLarge(limit int64) error {
+	return asParseError(fmt.Errorf("%w: more than %d bytes", ErrFileTooLarge, limit), limit)
 }
 
-// getKeys returns the keys of a map for debugging
-func getKeys(m map[uint32]string) []uint32 {
-	keys := make([]uint32, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
+// Range of file versions accepted by Parse, from Jw_cad Ver.2.00 up to
+// JW_DATA_VERSION 700 (Ver.7.00), the last layout documented in
+// refs/jwdatafmt.md. Other versions are rejected with ErrUnsupportedVersion,
+// except newer ones in Lenient mode.
+const (
+	minVersion = 200
+	maxVersion = 700
+)
+
+// findBlockDefList locates the block definition list following the entity
+// list that starts at offset from, by the definition of the CDataList class
+// that introduces its first block definition. It returns the offset of the
+// list, or -1 when there is none.
+func findBlockDefList(data []byte, from int64) int64 {
+	marker := []byte("\x09\x00CDataList")
+	for at := from; at < int64(len(data)); {
+		i := bytes.Index(data[at:], marker)
+		if i < 0 {
+			return -1
+		}
+		tag := at + int64(i) - 4
+		if tag-2 >= from && data[tag] == 0xFF && data[tag+1] == 0xFF {
+			return tag - 2
+		}
+		at += int64(i) + 1
 	}
-	return keys
+	return -1
 }
 
-// parseLayerNames extracts layer names from the file.
-func parseLayerNames(data []byte, doc *Document) {
-	// Layer names appear earlier in the file
-	// For now, use default names if we can't find them
+// setDefaultLayerNames assigns default names to layers and layer groups whose
+// stored name is empty: "Group0"-"GroupF" for groups and "G-L" in hexadecimal
+// (e.g. "0-0", "F-A") for layers. Names stored in the file are kept as-is.
+func setDefaultLayerNames(doc *Document) {
 	for gLay := 0; gLay < 16; gLay++ {
 		if doc.LayerGroups[gLay].Name == "" {
-			doc.LayerGroups[gLay].Name = fmt.Sprintf("Group%X", gLay)
+			doc.LayerGroups[gLay].Name = "Group" + hexDigits[gLay:gLay+1]
 		}
 		for lay := 0; lay < 16; lay++ {
 			if doc.LayerGroups[gLay].Layers[lay].Name == "" {
-				doc.LayerGroups[gLay].Layers[lay].Name = fmt.Sprintf("%X-%X", gLay, lay)
+				doc.LayerGroups[gLay].Layers[lay].Name = hexDigits[gLay:gLay+1] + "-" + hexDigits[lay:lay+1]
 			}
 		}
 	}
 }
 
-// parseBlockDefList parses the block definition list
-func parseBlockDefList(jr *Reader, version uint32) ([]BlockDef, error) {
-	count, err := jr.ReadDWORD()
+// hexDigits are the digits of the default layer names, cheaper to index than
+// formatting the numbers when every file is indexed.
+const hexDigits = "0123456789ABCDEF"
+
+// parseBlockDef reads the contents of a block definition (JWW class:
+// CDataList): the common entity fields, the definition number, whether it is
+// referenced, its creation time and name, followed by its own entity list.
+// The nested entities are read through the same archive, so their classes
+// and objects share the table of the enclosing lists.
+func parseBlockDef(a *archive) (*BlockDef, error) {
+	base, err := parseEntityBase(a.jr, a.version)
 	if err != nil {
-		return nil, fmt.Errorf("reading block def count: %w", err)
-	}
-
-	if count > 10000 {
-		// Probably not a valid block count, skip
-		return nil, nil
+		return nil, err
 	}
 
-	blockDefs := make([]BlockDef, 0, count)
-	classMap := make(map[uint16]string)
-	nextID := uint16(1)
+	bd := &BlockDef{EntityBase: *base}
 
-	for i := uint32(0); i < count; i++ {
-		bd, newID, err := parseBlockDefWithTracking(jr, version, classMap, nextID)
-		if err != nil {
-			return blockDefs, nil // Return what we have
-		}
-		nextID = newID
-		if bd != nil {
-			blockDefs = append(blockDefs, *bd)
-		}
+	if bd.Number, err = a.jr.ReadDWORD(); err != nil {
+		return nil, err
 	}
 
-	return blockDefs, nil
-}
-
-// parseBlockDefWithTracking parses a single block definition with class tracking.
-func parseBlockDefWithTracking(jr *Reader, version uint32, classMap map[uint16]string, nextID uint16) (*BlockDef, uint16, error) {
-	classID, err := jr.ReadWORD()
+	ref, err := a.jr.ReadDWORD()
 	if err != nil {
-		return nil, nextID, err
+		return nil, err
 	}
+	bd.IsReferenced = ref != 0
 
-	if classID == 0xFFFF {
-		_, _ = jr.ReadWORD() // schema
-		nameLen, _ := jr.ReadWORD()
-		nameBuf := make([]byte, nameLen)
-		jr.ReadBytes(nameBuf)
-		classMap[nextID] = string(nameBuf)
-		nextID++
-	} else if classID == 0x8000 {
-		return nil, nextID, nil
+	created, err := a.jr.ReadDWORD() // CTime
+	if err != nil {
+		return nil, err
 	}
+	bd.Created = blockDefTime(created)
 
-	base, err := parseEntityBase(jr, version)
+	name, err := a.jr.ReadCString()
 	if err != nil {
-		return nil, nextID, err
+		return nil, err
+	}
+	bd.Name = name
+	if a.version >= 410 {
+		bd.Name, bd.Kind = splitBlockDefName(name)
+	}
+	if bd.Kind > BlockKindDrawingPart {
+		if err := a.suspicious("unknown compound figure kind %d", bd.Kind); err != nil {
+			return nil, err
+		}
 	}
 
-	bd := &BlockDef{EntityBase: *base}
-
-	bd.Number, _ = jr.ReadDWORD()
-
-	ref, _ := jr.ReadDWORD()
-	bd.IsReferenced = ref != 0
+	if bd.Entities, err = a.readEntityList(); err != nil {
+		return nil, err
+	}
 
-	jr.Skip(4) // CTime
+	return bd, nil
+}
 
-	bd.Name, _ = jr.ReadCString()
+// blockDefKindMarker separates the block name from the compound figure type
+// appended by Jw_cad Ver.4.10 and later.
+const blockDefKindMarker = "@@SfigorgFlag@@"
 
-	// Parse nested entities
-	nestedEntities, _, err := parseEntityListWithOffset(jr, version)
+// splitBlockDefName splits a stored block definition name into the name and
+// the compound figure type that follows blockDefKindMarker.
+func splitBlockDefName(stored string) (string, BlockKind) {
+	name, kind, found := strings.Cut(stored, blockDefKindMarker)
+	if !found {
+		return stored, BlockKindBlock
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(kind))
 	if err != nil {
-		return bd, nextID, nil
+		return name, BlockKindBlock
 	}
-	bd.Entities = nestedEntities
+	return name, BlockKind(n)
+}
 
-	return bd, nextID, nil
+// blockDefTime converts an MFC CTime, stored as a 32-bit time_t in seconds
+// since the Unix epoch, to a time. Zero is the zero time.
+func blockDefTime(t uint32) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(int32(t)), 0)
 }
 
 // parseDimension parses a dimension entity from the JWW file (JWW class: CDataSunpou).
-// Dimensions are complex entities composed of lines and text to show measurements.
-// This function extracts the dimension data and returns the associated line entity.
-// Version 4.20 and later include additional SXF mode data.
-func parseDimension(jr *Reader, version uint32) (Entity, error) {
+// Dimensions are complex entities composed of a line and a text member that
+// show a measurement. Version 4.20 and later include the SXF mode followed by
+// two auxiliary lines and four points (two arrow points, two reference points).
+func parseDimension(jr *Reader, version uint32) (*Dimension, error) {
 	base, err := parseEntityBase(jr, version)
 	if err != nil {
 		return nil, err
 	}
-	_ = base
+
+	dim := &Dimension{EntityBase: *base}
 
 	// Parse the line member
 	line, err := parseLine(jr, version)
 	if err != nil {
 		return nil, err
 	}
+	dim.Line = *line
 
 	// Parse the text member
-	_, err = parseText(jr, version)
+	txt, err := parseText(jr, version)
 	if err != nil {
 		return nil, err
 	}
+	dim.Text = *txt
 
 	// Ver.4.20+ has additional SXF mode data
 	if version >= 420 {
-		_, _ = jr.ReadWORD() // SXF mode
+		dim.SXFMode, err = jr.ReadWORD()
+		if err != nil {
+			return nil, err
+		}
 
-		for i := 0; i < 2; i++ {
-			parseLine(jr, version)
+		for i := range dim.AuxLines {
+			aux, err := parseLine(jr, version)
+			if err != nil {
+				return nil, err
+			}
+			dim.AuxLines[i] = *aux
 		}
-		for i := 0; i < 4; i++ {
-			parsePoint(jr, version)
+		for i := range dim.ArrowPoints {
+			pt, err := parsePoint(jr, version)
+			if err != nil {
+				return nil, err
+			}
+			dim.ArrowPoints[i] = *pt
+		}
+		for i := range dim.RefPoints {
+			pt, err := parsePoint(jr, version)
+			if err != nil {
+				return nil, err
+			}
+			dim.RefPoints[i] = *pt
 		}
 	}
 
-	return line, nil
+	return dim, nil
 }
 
 // parseEntityBase reads the common entity base fields shared by all entity types.
@@ -488,10 +468,9 @@ func parseLine(jr *Reader, version uint32) (*Line, error) {
 
 	line := &Line{EntityBase: *base}
 
-	line.StartX, _ = jr.ReadDouble()
-	line.StartY, _ = jr.ReadDouble()
-	line.EndX, _ = jr.ReadDouble()
-	line.EndY, _ = jr.ReadDouble()
+	if err := readDoubles(jr, &line.StartX, &line.StartY, &line.EndX, &line.EndY); err != nil {
+		return nil, err
+	}
 
 	return line, nil
 }
@@ -507,21 +486,27 @@ func parseArc(jr *Reader, version uint32) (*Arc, error) {
 
 	arc := &Arc{EntityBase: *base}
 
-	arc.CenterX, _ = jr.ReadDouble()
-	arc.CenterY, _ = jr.ReadDouble()
-	arc.Radius, _ = jr.ReadDouble()
-	arc.StartAngle, _ = jr.ReadDouble()
-	arc.ArcAngle, _ = jr.ReadDouble()
-	arc.TiltAngle, _ = jr.ReadDouble()
-	arc.Flatness, _ = jr.ReadDouble()
-	fullCircle, _ := jr.ReadDWORD()
+	if err := readDoubles(jr,
+		&arc.CenterX, &arc.CenterY,
+		&arc.Radius,
+		&arc.StartAngle, &arc.ArcAngle,
+		&arc.TiltAngle, &arc.Flatness,
+	); err != nil {
+		return nil, err
+	}
+	fullCircle, err := jr.ReadDWORD()
+	if err != nil {
+		return nil, err
+	}
 	arc.IsFullCircle = fullCircle != 0
 
 	return arc, nil
 }
 
 // parsePoint reads a point entity from the JWW file (JWW class: CDataTen).
-// Points can be temporary construction points or permanent marker points with symbols.
+// Points can be temporary construction points or permanent marker points with
+// symbols. The marker code, angle and scale follow a PenStyle of 100 from
+// Ver.2.52; older files store no marker fields.
 func parsePoint(jr *Reader, version uint32) (*Point, error) {
 	base, err := parseEntityBase(jr, version)
 	if err != nil {
@@ -530,15 +515,22 @@ func parsePoint(jr *Reader, version uint32) (*Point, error) {
 
 	pt := &Point{EntityBase: *base}
 
-	pt.X, _ = jr.ReadDouble()
-	pt.Y, _ = jr.ReadDouble()
-	tmp, _ := jr.ReadDWORD()
+	if err := readDoubles(jr, &pt.X, &pt.Y); err != nil {
+		return nil, err
+	}
+	tmp, err := jr.ReadDWORD()
+	if err != nil {
+		return nil, err
+	}
 	pt.IsTemporary = tmp != 0
 
-	if base.PenStyle == 100 {
-		pt.Code, _ = jr.ReadDWORD()
-		pt.Angle, _ = jr.ReadDouble()
-		pt.Scale, _ = jr.ReadDouble()
+	if base.PenStyle == 100 && version >= 252 {
+		if pt.Code, err = jr.ReadDWORD(); err != nil {
+			return nil, err
+		}
+		if err := readDoubles(jr, &pt.Angle, &pt.Scale); err != nil {
+			return nil, err
+		}
 	}
 
 	return pt, nil
@@ -555,47 +547,84 @@ func parseText(jr *Reader, version uint32) (*Text, error) {
 
 	txt := &Text{EntityBase: *base}
 
-	txt.StartX, _ = jr.ReadDouble()
-	txt.StartY, _ = jr.ReadDouble()
-	txt.EndX, _ = jr.ReadDouble()
-	txt.EndY, _ = jr.ReadDouble()
-	txt.TextType, _ = jr.ReadDWORD()
-	txt.SizeX, _ = jr.ReadDouble()
-	txt.SizeY, _ = jr.ReadDouble()
-	txt.Spacing, _ = jr.ReadDouble()
-	txt.Angle, _ = jr.ReadDouble()
-	txt.FontName, _ = jr.ReadCString()
-	txt.Content, _ = jr.ReadCString()
+	if err := readDoubles(jr, &txt.StartX, &txt.StartY, &txt.EndX, &txt.EndY); err != nil {
+		return nil, err
+	}
+	if txt.TextType, err = jr.ReadDWORD(); err != nil {
+		return nil, err
+	}
+	if err := readDoubles(jr, &txt.SizeX, &txt.SizeY, &txt.Spacing, &txt.Angle); err != nil {
+		return nil, err
+	}
+	if txt.FontName, err = jr.ReadCString(); err != nil {
+		return nil, err
+	}
+	if txt.Content, err = jr.ReadCString(); err != nil {
+		return nil, err
+	}
 
 	return txt, nil
 }
 
 // parseSolid reads a solid fill entity from the JWW file (JWW class: CDataSolid).
 // Solids are quadrilaterals or triangles used for filled areas, hatching, and shading.
-func parseSolid(jr *Reader, version uint32) (*Solid, error) {
+// A PenStyle of 101 or above marks a circle solid; see parseCircleSolid.
+func parseSolid(jr *Reader, version uint32) (Entity, error) {
 	base, err := parseEntityBase(jr, version)
 	if err != nil {
 		return nil, err
 	}
 
+	if base.PenStyle >= 101 {
+		return parseCircleSolid(jr, base)
+	}
+
 	solid := &Solid{EntityBase: *base}
 
-	solid.Point1X, _ = jr.ReadDouble()
-	solid.Point1Y, _ = jr.ReadDouble()
-	solid.Point4X, _ = jr.ReadDouble()
-	solid.Point4Y, _ = jr.ReadDouble()
-	solid.Point2X, _ = jr.ReadDouble()
-	solid.Point2Y, _ = jr.ReadDouble()
-	solid.Point3X, _ = jr.ReadDouble()
-	solid.Point3Y, _ = jr.ReadDouble()
+	if err := readDoubles(jr,
+		&solid.Point1X, &solid.Point1Y,
+		&solid.Point4X, &solid.Point4Y,
+		&solid.Point2X, &solid.Point2Y,
+		&solid.Point3X, &solid.Point3Y,
+	); err != nil {
+		return nil, err
+	}
 
 	if base.PenColor == 10 {
-		solid.Color, _ = jr.ReadDWORD()
+		if solid.Color, err = jr.ReadDWORD(); err != nil {
+			return nil, err
+		}
 	}
 
 	return solid, nil
 }
 
+// parseCircleSolid reads the body of a circle solid (円ソリッド). The CDataSolid
+// point fields hold, in file order: center X/Y, radius, flatness, tilt angle,
+// start angle, arc angle and the shape parameter.
+func parseCircleSolid(jr *Reader, base *EntityBase) (*CircleSolid, error) {
+	cs := &CircleSolid{EntityBase: *base}
+
+	if err := readDoubles(jr,
+		&cs.CenterX, &cs.CenterY,
+		&cs.Radius, &cs.Flatness,
+		&cs.TiltAngle, &cs.StartAngle,
+		&cs.ArcAngle, &cs.Param,
+	); err != nil {
+		return nil, err
+	}
+
+	if base.PenColor == 10 {
+		color, err := jr.ReadDWORD()
+		if err != nil {
+			return nil, err
+		}
+		cs.Color = color
+	}
+
+	return cs, nil
+}
+
 // parseBlock reads a block insert entity from the JWW file (JWW class: CDataBlock).
 // Block inserts reference a block definition and can have independent scale and rotation.
 func parseBlock(jr *Reader, version uint32) (*Block, error) {
@@ -606,12 +635,25 @@ func parseBlock(jr *Reader, version uint32) (*Block, error) {
 
 	block := &Block{EntityBase: *base}
 
-	block.RefX, _ = jr.ReadDouble()
-	block.RefY, _ = jr.ReadDouble()
-	block.ScaleX, _ = jr.ReadDouble()
-	block.ScaleY, _ = jr.ReadDouble()
-	block.Rotation, _ = jr.ReadDouble()
-	block.DefNumber, _ = jr.ReadDWORD()
+	if err := readDoubles(jr, &block.RefX, &block.RefY, &block.ScaleX, &block.ScaleY, &block.Rotation); err != nil {
+		return nil, err
+	}
+	if block.DefNumber, err = jr.ReadDWORD(); err != nil {
+		return nil, err
+	}
 
 	return block, nil
 }
+
+// readDoubles reads consecutive doubles into the given fields, stopping at
+// the first error.
+func readDoubles(jr *Reader, fields ...*float64) error {
+	for _, f := range fields {
+		v, err := jr.ReadDouble()
+		if err != nil {
+			return err
+		}
+		*f = v
+	}
+	return nil
+}
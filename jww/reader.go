@@ -28,7 +28,9 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"unsafe"
 
 	"golang.org/x/text/encoding/japanese"
@@ -41,24 +43,53 @@ var (
 
 	// ErrUnsupportedVersion is returned when the JWW file version is not supported by this parser.
 	ErrUnsupportedVersion = errors.New("unsupported JWW version")
+
+	// ErrImageTooLarge is returned when a bundled image exceeds the decompressed size limit,
+	// or the bundled images exceed ParseOptions.MaxImageBytes.
+	ErrImageTooLarge = errors.New("bundled image exceeds size limit")
+
+	// ErrTooManyEntities is returned when a file holds more entities than
+	// ParseOptions.MaxEntities.
+	ErrTooManyEntities = errors.New("too many entities")
+
+	// ErrStringTooLong is returned when a string is longer than
+	// ParseOptions.MaxStringLen.
+	ErrStringTooLong = errors.New("string too long")
+
+	// ErrBlockTooDeep is returned when block definitions are nested deeper
+	// than ParseOptions.MaxBlockDepth.
+	ErrBlockTooDeep = errors.New("block definitions nested too deep")
+
+	// ErrFileTooLarge is returned when the input is larger than
+	// ParseOptions.MaxFileBytes.
+	ErrFileTooLarge = errors.New("file too large")
 )
 
 // Reader wraps an io.Reader to provide convenient methods for reading JWW binary data.
 // All multi-byte values are read in little-endian format, and text strings are
 // decoded from Shift-JIS to UTF-8.
 type Reader struct {
-	r         io.Reader
-	buf       []byte
-	bytesRead int64
+	r          io.Reader
+	buf        []byte
+	bytesRead  int64
+	lastOffset int64 // Offset of the last read, reported in parse errors
+
+	// checkValues rejects implausible doubles in strict parsing
+	checkValues bool
+
+	// maxStringLen limits the length of strings, see ParseOptions
+	maxStringLen int
 }
 
 // NewReader creates a new JWW binary reader that wraps the provided io.Reader.
 // The reader maintains an internal buffer for efficient binary data reading.
+// Strings longer than DefaultMaxStringLen are rejected with ErrStringTooLong.
 func NewReader(r io.Reader) *Reader {
 	return &Reader{
-		r:         r,
-		buf:       make([]byte, 8),
-		bytesRead: 0,
+		r:            r,
+		buf:          make([]byte, 8),
+		bytesRead:    0,
+		maxStringLen: DefaultMaxStringLen,
 	}
 }
 
@@ -67,9 +98,7 @@ func NewReader(r io.Reader) *Reader {
 // Returns ErrInvalidSignature if the signature is invalid.
 func (r *Reader) ReadSignature() error {
 	sig := make([]byte, 8)
-	n, err := io.ReadFull(r.r, sig)
-	r.bytesRead += int64(n)
-	if err != nil {
+	if err := r.read(sig); err != nil {
 		return err
 	}
 	if string(sig) != "JwwData." {
@@ -81,9 +110,7 @@ func (r *Reader) ReadSignature() error {
 // ReadDWORD reads a 32-bit unsigned integer in little-endian format.
 // This corresponds to the Windows DWORD type used in the JWW file format.
 func (r *Reader) ReadDWORD() (uint32, error) {
-	n, err := io.ReadFull(r.r, r.buf[:4])
-	r.bytesRead += int64(n)
-	if err != nil {
+	if err := r.read(r.buf[:4]); err != nil {
 		return 0, err
 	}
 	return binary.LittleEndian.Uint32(r.buf[:4]), nil
@@ -92,20 +119,30 @@ func (r *Reader) ReadDWORD() (uint32, error) {
 // ReadWORD reads a 16-bit unsigned integer in little-endian format.
 // This corresponds to the Windows WORD type used in the JWW file format.
 func (r *Reader) ReadWORD() (uint16, error) {
-	n, err := io.ReadFull(r.r, r.buf[:2])
-	r.bytesRead += int64(n)
-	if err != nil {
+	if err := r.read(r.buf[:2]); err != nil {
 		return 0, err
 	}
 	return binary.LittleEndian.Uint16(r.buf[:2]), nil
 }
 
+// ReadCount reads an element count in MFC CArchive format (CArchive::ReadCount),
+// as stored in front of serialized lists. Counts below 0xFFFF are stored as a
+// WORD; larger ones as the WORD 0xFFFF followed by a DWORD.
+func (r *Reader) ReadCount() (uint32, error) {
+	count, err := r.ReadWORD()
+	if err != nil {
+		return 0, err
+	}
+	if count != 0xFFFF {
+		return uint32(count), nil
+	}
+	return r.ReadDWORD()
+}
+
 // ReadBYTE reads a single unsigned byte.
 // This corresponds to the Windows BYTE type used in the JWW file format.
 func (r *Reader) ReadBYTE() (byte, error) {
-	n, err := io.ReadFull(r.r, r.buf[:1])
-	r.bytesRead += int64(n)
-	if err != nil {
+	if err := r.read(r.buf[:1]); err != nil {
 		return 0, err
 	}
 	return r.buf[0], nil
@@ -114,13 +151,15 @@ func (r *Reader) ReadBYTE() (byte, error) {
 // ReadDouble reads a 64-bit IEEE 754 floating point number in little-endian format.
 // This is used for coordinate values and other measurements in JWW files.
 func (r *Reader) ReadDouble() (float64, error) {
-	n, err := io.ReadFull(r.r, r.buf[:8])
-	r.bytesRead += int64(n)
-	if err != nil {
+	if err := r.read(r.buf[:8]); err != nil {
 		return 0, err
 	}
 	bits := binary.LittleEndian.Uint64(r.buf[:8])
-	return float64FromBits(bits), nil
+	v := float64FromBits(bits)
+	if r.checkValues && !plausible(v) {
+		return 0, fmt.Errorf("implausible value %g", v)
+	}
+	return v, nil
 }
 
 // ReadCString reads a length-prefixed string in MFC CString format.
@@ -131,6 +170,8 @@ func (r *Reader) ReadDouble() (float64, error) {
 //   - Otherwise: 1 byte 0xFF marker + 2 byte 0xFFFF marker + 4 byte length
 //
 // The string data is encoded in Shift-JIS and automatically converted to UTF-8.
+// Strings longer than the limit of the reader (DefaultMaxStringLen, or
+// ParseOptions.MaxStringLen when parsing) fail with ErrStringTooLong.
 func (r *Reader) ReadCString() (string, error) {
 	// Read length prefix
 	lenByte, err := r.ReadBYTE()
@@ -161,12 +202,13 @@ func (r *Reader) ReadCString() (string, error) {
 	if length == 0 {
 		return "", nil
 	}
+	if uint64(length) > uint64(r.maxStringLen) {
+		return "", fmt.Errorf("%w: %d bytes", ErrStringTooLong, length)
+	}
 
 	// Read string bytes
 	strBuf := make([]byte, length)
-	n, err := io.ReadFull(r.r, strBuf)
-	r.bytesRead += int64(n)
-	if err != nil {
+	if err := r.read(strBuf); err != nil {
 		return "", err
 	}
 
@@ -177,18 +219,13 @@ func (r *Reader) ReadCString() (string, error) {
 // ReadBytes reads exactly len(buf) bytes into the provided buffer.
 // Returns an error if fewer bytes are available.
 func (r *Reader) ReadBytes(buf []byte) error {
-	n, err := io.ReadFull(r.r, buf)
-	r.bytesRead += int64(n)
-	return err
+	return r.read(buf)
 }
 
 // Skip skips n bytes in the input stream.
 // This is useful for skipping over unknown or unneeded data structures.
 func (r *Reader) Skip(n int) error {
-	buf := make([]byte, n)
-	read, err := io.ReadFull(r.r, buf)
-	r.bytesRead += int64(read)
-	return err
+	return r.read(make([]byte, n))
 }
 
 // BytesRead returns the total number of bytes read from the underlying reader.
@@ -197,6 +234,41 @@ func (r *Reader) BytesRead() int64 {
 	return r.bytesRead
 }
 
+// read reads exactly len(buf) bytes, remembering the offset it started at.
+func (r *Reader) read(buf []byte) error {
+	r.lastOffset = r.bytesRead
+	n, err := io.ReadFull(r.r, buf)
+	r.bytesRead += int64(n)
+	return err
+}
+
+// seek moves to the given offset from the start of the input, which must be
+// an io.Seeker.
+func (r *Reader) seek(offset int64) error {
+	s, ok := r.r.(io.Seeker)
+	if !ok {
+		return errors.New("input is not seekable")
+	}
+	if _, err := s.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	r.bytesRead = offset
+	r.lastOffset = offset
+	return nil
+}
+
+// maxPlausibleValue bounds the doubles accepted in strict parsing. Drawings
+// in millimeters stay far below it, while garbage bytes read as doubles
+// usually do not.
+const maxPlausibleValue = 1e15
+
+// plausible reports whether v is finite, below maxPlausibleValue and not a
+// subnormal number.
+func plausible(v float64) bool {
+	a := math.Abs(v)
+	return a == 0 || (a >= 0x1p-1022 && a < maxPlausibleValue)
+}
+
 // float64FromBits converts a uint64 bit pattern to a float64 value.
 // This uses unsafe pointer conversion to reinterpret the bits as a float64.
 func float64FromBits(bits uint64) float64 {
@@ -61,6 +61,31 @@ func TestReader_ReadWORD(t *testing.T) {
 	}
 }
 
+func TestReader_ReadCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected uint32
+	}{
+		{"zero", []byte{0, 0}, 0},
+		{"word", []byte{0xFE, 0xFF}, 0xFFFE},
+		{"escaped dword", []byte{0xFF, 0xFF, 0x00, 0x00, 0x01, 0x00}, 0x10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(bytes.NewReader(tt.data))
+			val, err := r.ReadCount()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if val != tt.expected {
+				t.Errorf("got %d, want %d", val, tt.expected)
+			}
+		})
+	}
+}
+
 func TestReader_ReadBYTE(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -232,3 +257,38 @@ func TestReader_ReadSignature_Invalid(t *testing.T) {
 		t.Errorf("expected ErrInvalidSignature, got: %v", err)
 	}
 }
+
+func FuzzReader(f *testing.F) {
+	f.Add([]byte{4, 't', 'e', 's', 't', 0xFF, 0xFF, 0x7F})
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F})
+	f.Add([]byte{0xFF, 0x2C, 0x01, 'a', 'b'})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		const maxStringLen = 64
+		r := NewReader(bytes.NewReader(data))
+		r.maxStringLen = maxStringLen
+
+		// Read strings, counts and numbers in turn until the data runs out
+		for i := 0; ; i++ {
+			var err error
+			switch i % 3 {
+			case 0:
+				var s string
+				s, err = r.ReadCString()
+				if err == nil && len(s) > 3*maxStringLen {
+					t.Fatalf("string of %d bytes read past the limit", len(s))
+				}
+			case 1:
+				_, err = r.ReadCount()
+			case 2:
+				_, err = r.ReadDouble()
+			}
+			if err != nil {
+				break
+			}
+		}
+		if r.BytesRead() > int64(len(data)) {
+			t.Fatalf("read %d bytes of %d", r.BytesRead(), len(data))
+		}
+	})
+}
@@ -1,5 +1,12 @@
 package jww
 
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
 // Document represents a complete JWW (Jw_cad) file structure.
 // JWW files are binary CAD files used by Jw_cad, a popular Japanese CAD software.
 // The document contains layer information, drawing entities, and optional block definitions.
@@ -20,11 +27,485 @@ type Document struct {
 	// This provides a total of 256 possible layers organized in a hierarchical structure.
 	LayerGroups [16]LayerGroup
 
+	// Header contains the drawing settings stored between the layer state
+	// table and the entity list (dimension settings, printer, grid, pens,
+	// line types, text presets, etc.).
+	Header Header
+
 	// Entities contains all drawing entities (lines, arcs, text, etc.) in the file.
 	Entities []Entity
 
 	// BlockDefs contains block definitions that can be referenced by block insert entities.
 	BlockDefs []BlockDef
+
+	// Images contains the image files bundled with the drawing (同梱画像,
+	// Ver.7.00 and later). They are referenced by ImageRef entities.
+	Images []Image
+
+	// Warnings lists the problems that did not stop parsing: damaged data
+	// skipped in lenient mode and suspicious values. See ParseWithOptions.
+	Warnings []Warning `json:",omitempty"`
+}
+
+// Header holds the drawing settings that follow the layer state table in a
+// JWW file. Fields are stored in file order; see refs/jwdatafmt.md for the
+// exact layout. Fields introduced in later file versions are left at their
+// zero value when reading older files.
+type Header struct {
+	// DimensionSettings are the packed dimension settings m_lnSunpou1-5 (寸法関係の設定).
+	// They are all zero unless the drawing was saved with dimension settings enabled.
+	// See DimensionStyle for the decoded values.
+	DimensionSettings [5]uint32
+
+	// MaxDrawWidth is the maximum line draw width (線描画の最大幅).
+	// Negative values (-101, -201..-300, -401..-500) indicate that line widths
+	// are stored in 1/100 mm units.
+	MaxDrawWidth int32
+
+	// PrinterOriginX, PrinterOriginY are the origin of the printer output range.
+	PrinterOriginX, PrinterOriginY float64
+
+	// PrinterScale is the printer output magnification (プリンタ出力倍率).
+	PrinterScale float64
+
+	// PrinterSettings packs the 90° rotation flag (ones digit) and the
+	// output reference point position (tens digit, Ver.3.00 and later).
+	PrinterSettings uint32
+
+	// GridMode is the grid (目盛) mode; negative values disable grid snapping.
+	GridMode int32
+
+	// GridMinSpacing is the minimum grid display spacing in dots.
+	GridMinSpacing float64
+
+	// GridSpacingX, GridSpacingY are the grid spacing.
+	GridSpacingX, GridSpacingY float64
+
+	// GridOriginX, GridOriginY are the grid reference point.
+	GridOriginX, GridOriginY float64
+
+	// Shadow holds the sun shadow (日影) calculation conditions.
+	Shadow ShadowSettings
+
+	// SkyMapLevel is the measurement plane height for sky maps (Ver.3.00 and later).
+	SkyMapLevel float64
+
+	// SkyMapDiameter is the sky map radius × 2 (Ver.3.00 and later).
+	SkyMapDiameter float64
+
+	// Units25D is the 2.5D calculation unit (non-zero means millimetres).
+	Units25D uint32
+
+	// ViewScale, ViewOriginX, ViewOriginY are the screen magnification and origin at save time.
+	ViewScale, ViewOriginX, ViewOriginY float64
+
+	// RangeScale, RangeOriginX, RangeOriginY are the stored range magnification and origin.
+	RangeScale, RangeOriginX, RangeOriginY float64
+
+	// MarkJumps are the mark jump (マークジャンプ) views: 8 entries from Ver.3.00, 4 before.
+	MarkJumps []MarkJump
+
+	// TextBackgroundMargin is the margin used when drawing text ranges with
+	// the background color (Ver.4.05 and later; Ver.3.00-4.04 store a dummy
+	// in its place, which is ignored).
+	TextBackgroundMargin float64
+
+	// TextBackgroundMode is the text draw state (文字の描画状態, Ver.4.05 and
+	// later; Ver.3.00-4.04 store a dummy in its place, which is ignored).
+	TextBackgroundMode uint32
+
+	// ParallelLineSpacings are the ten stored parallel line (複線) spacings.
+	ParallelLineSpacings [10]float64
+
+	// ParallelLineEndExtension is the end extension for double-sided parallel lines.
+	ParallelLineEndExtension float64
+
+	// Pens holds the screen and printer settings for color numbers 0-9.
+	Pens [10]PenSetting
+
+	// LineTypes holds the patterns of line types 2-9, indexed by line type
+	// number. Entries 0 and 1 are unused.
+	LineTypes [10]LineTypeDef
+
+	// RandomLines holds the random line (ランダム線) definitions 1-5.
+	RandomLines [5]RandomLineDef
+
+	// DoubleLineTypes holds the double-length (倍長) line types 6-9.
+	DoubleLineTypes [4]LineTypeDef
+
+	// DrawScreenPoints is set when points are drawn at the specified radius on screen.
+	DrawScreenPoints uint32
+
+	// DrawPrinterPoints is set when points are printed at the specified radius.
+	DrawPrinterPoints uint32
+
+	// BitmapFirstDraw is the bitmap/solid draw order setting.
+	BitmapFirstDraw uint32
+
+	// ReverseDraw is the reverse draw (逆描画) setting.
+	ReverseDraw uint32
+
+	// ReverseSearch is the reverse search (逆サーチ) setting.
+	ReverseSearch uint32
+
+	// ColorPrint is the color printing setting.
+	ColorPrint uint32
+
+	// LayerOrderPrint is the print-in-layer-order setting.
+	LayerOrderPrint uint32
+
+	// ColorOrderPrint is the print-in-color-order setting.
+	ColorOrderPrint uint32
+
+	// PrintContinuous is the continuous per layer/group printing setting.
+	PrintContinuous uint32
+
+	// PrintCommonGray is the gray output setting for display-only layers.
+	PrintCommonGray uint32
+
+	// PrintSkipDisplayOnly suppresses display-only layers when printing.
+	// From Ver.6.00 it also carries the default line width dpi (+10: 300dpi, +20: 600dpi).
+	PrintSkipDisplayOnly uint32
+
+	// DrawTime is the accumulated drawing time (作図時間, Ver.2.23 and later).
+	DrawTime uint32
+
+	// Eye holds the 2.5D view settings (Ver.2.23 and later).
+	Eye EyeSettings
+
+	// LastLineLength is the last line length entered (Ver.2.25 and later).
+	LastLineLength float64
+
+	// LastBoxWidth, LastBoxHeight are the last rectangle size entered (Ver.2.25 and later).
+	LastBoxWidth, LastBoxHeight float64
+
+	// LastCircleRadius is the last circle radius entered (Ver.2.25 and later).
+	LastCircleRadius float64
+
+	// SolidColorMode is set when solids are drawn with an arbitrary color (Ver.2.30 and later).
+	SolidColorMode uint32
+
+	// SolidColor is the default arbitrary solid color as RGB (Ver.2.30 and later).
+	SolidColor uint32
+
+	// SXFColors holds the 257 SXF extended colors (Ver.4.20 and later).
+	// Entry n corresponds to pen color number n+100.
+	SXFColors []SXFColor
+
+	// SXFLineTypes holds the 33 SXF extended line types (Ver.4.20 and later).
+	// Entry n corresponds to line type number n+30.
+	SXFLineTypes []SXFLineType
+
+	// TextStyles holds the text type presets 1-10 (文字種1～10), index 0 is 文字種1.
+	TextStyles [10]TextStyle
+
+	// WriteTextStyle is the current text style for writing.
+	WriteTextStyle TextStyle
+
+	// WriteTextType is the current text type number (文字番号).
+	WriteTextType uint32
+
+	// TextArrangeLineSpacing is the line spacing used by text arrangement.
+	TextArrangeLineSpacing float64
+
+	// TextArrangeCount is the character count used by text arrangement.
+	TextArrangeCount float64
+
+	// TextOffsetEnabled is set when the text reference point offsets are used.
+	TextOffsetEnabled uint32
+
+	// TextOffsetX holds the horizontal reference point offsets (left, center, right).
+	TextOffsetX [3]float64
+
+	// TextOffsetY holds the vertical reference point offsets (bottom, middle, top).
+	TextOffsetY [3]float64
+}
+
+// Dimension end marks (寸法線端部).
+const (
+	DimEndDot          = 0 // 点
+	DimEndArrow        = 1 // 矢印
+	DimEndReverseArrow = 2 // 逆矢印
+)
+
+// Placement of the "R" mark of radius values (半径値に「R」を表示する).
+const (
+	DimRadiusMarkNone   = 0
+	DimRadiusMarkPrefix = 1
+	DimRadiusMarkSuffix = 2
+)
+
+// Angle units of angular dimension values (角度単位).
+const (
+	DimAngleDegrees         = 0 // Decimal degrees with "°"
+	DimAngleDMS             = 1 // Degrees, minutes and seconds
+	DimAngleDegreesNoSymbol = 2 // Decimal degrees without "ﾟ"
+)
+
+// Rounding of dimension values beyond the displayed decimals (表示小数点以下の処理).
+const (
+	DimRoundHalfUp = 0 // 四捨五入
+	DimRoundDown   = 1 // 切捨
+	DimRoundUp     = 2 // 切上
+)
+
+// DimensionStyle holds the decoded dimension settings (寸法設定) stored in
+// Header.DimensionSettings. Lengths are paper millimetres.
+type DimensionStyle struct {
+	// LineColor, ExtLineColor and PointColor are the pen colors (1-9) of the
+	// dimension line (寸法線色), extension lines (引出線色) and points (寸法点色).
+	LineColor, ExtLineColor, PointColor int
+
+	// Decimals is the number of decimal places of dimension values (0-3).
+	Decimals int
+
+	// Meters reports whether values are shown in metres instead of millimetres (寸法単位).
+	Meters bool
+
+	// EndMark is the dimension line end mark: DimEndDot, DimEndArrow or DimEndReverseArrow.
+	EndMark int
+
+	// TextType is the text type (寸法文字種, 1-10) of dimension values.
+	TextType int
+
+	// TextOffset is the distance between the value and the dimension line (寸法値の寸法線との離れ).
+	TextOffset float64
+
+	// ExtLineOvershoot is the length extension lines project beyond the
+	// dimension line (寸法線の突出長さ).
+	ExtLineOvershoot float64
+
+	// ArrowLength and ArrowAngle are the arrow length and angle in degrees (矢印長さ・角度).
+	ArrowLength, ArrowAngle float64
+
+	// ReverseArrowLength is the length reverse arrows extend past the
+	// measured points (逆矢印の出寸法).
+	ReverseArrowLength float64
+
+	// KeepTextDirection disables correcting the value direction to be readable (寸法値方向を補正しない).
+	KeepTextDirection bool
+
+	// FullWidth, FullWidthComma and FullWidthPoint select full-width digits,
+	// separators and decimal points.
+	FullWidth, FullWidthComma, FullWidthPoint bool
+
+	// SpaceSeparator uses a space instead of a comma as thousands separator.
+	SpaceSeparator bool
+
+	// ShowUnit appends the unit to dimension values.
+	ShowUnit bool
+
+	// RadiusMark places the "R" of radius values: DimRadiusMarkNone,
+	// DimRadiusMarkPrefix or DimRadiusMarkSuffix.
+	RadiusMark int
+
+	// RadiusComma shows thousands separators in radius values.
+	RadiusComma bool
+
+	// RadiusTrailingZeros keeps trailing decimal zeros in radius values.
+	RadiusTrailingZeros bool
+
+	// Italic and Bold select italic and bold value text.
+	Italic, Bold bool
+
+	// AngleUnit is the angle unit: DimAngleDegrees, DimAngleDMS or DimAngleDegreesNoSymbol.
+	AngleUnit int
+
+	// AngleDecimals is the number of decimal places of angle values (0-6).
+	AngleDecimals int
+
+	// Associative reports whether dimensions are created as dimension
+	// objects (寸法図形) rather than separate lines and texts.
+	Associative bool
+
+	// SelectByAttributes selects dimension objects by their line color and
+	// type in range selections.
+	SelectByAttributes bool
+
+	// Rounding is the rounding mode: DimRoundHalfUp, DimRoundDown or DimRoundUp (Ver.4.02 and later).
+	Rounding int
+}
+
+// ShadowSettings holds the sun shadow (日影) calculation conditions.
+type ShadowSettings struct {
+	// Level is the measurement plane height.
+	Level float64
+
+	// Latitude is the site latitude.
+	Latitude float64
+
+	// Hours9To15 is the 9:00-15:00 measurement flag.
+	Hours9To15 uint32
+
+	// WallLevel is the measurement plane height for wall shadows.
+	WallLevel float64
+}
+
+// MarkJump is a stored mark jump view.
+type MarkJump struct {
+	// Scale is the view magnification.
+	Scale float64
+
+	// OriginX, OriginY are the view reference point.
+	OriginX, OriginY float64
+
+	// LayerGroup is the active layer group (Ver.3.00 and later).
+	LayerGroup uint32
+}
+
+// PenSetting holds the screen and printer settings of a color number.
+type PenSetting struct {
+	// ScreenColor is the screen display color (RGB).
+	ScreenColor uint32
+
+	// ScreenWidth is the screen line width.
+	ScreenWidth uint32
+
+	// PrinterColor is the printer output color (RGB).
+	PrinterColor uint32
+
+	// PrinterWidth is the printer line width.
+	PrinterWidth uint32
+
+	// PointRadius is the printed point radius.
+	PointRadius float64
+}
+
+// SXFColor is an SXF extended color definition.
+type SXFColor struct {
+	PenSetting
+
+	// Name is the user-defined color name.
+	Name string
+}
+
+// LineTypeDef holds a Jw_cad line type pattern.
+type LineTypeDef struct {
+	// Pattern is the on/off bit pattern of one unit.
+	Pattern uint32
+
+	// UnitDots is the number of dots in one unit.
+	UnitDots uint32
+
+	// Pitch is the screen pitch.
+	Pitch uint32
+
+	// PrinterPitch is the printer pitch.
+	PrinterPitch uint32
+}
+
+// Dashes returns the on/off runs of one pattern unit, measured in dots:
+// positive values are dashes and negative values are gaps. The lowest
+// UnitDots bits of Pattern are read from the most significant one down, and
+// the runs are rotated so that the unit starts with a dash. A solid, empty or
+// undefined pattern returns nil.
+func (lt LineTypeDef) Dashes() []int {
+	n := int(lt.UnitDots)
+	if n <= 0 || n > 32 {
+		return nil
+	}
+
+	bits := make([]bool, n)
+	for i := range bits {
+		bits[i] = lt.Pattern&(1<<(n-1-i)) != 0
+	}
+
+	// Start at the first dash that follows a gap so runs are not split
+	start := -1
+	for i := range bits {
+		if bits[i] && !bits[(i+n-1)%n] {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil // All dashes or all gaps
+	}
+
+	var runs []int
+	for i := 0; i < n; i++ {
+		on := bits[(start+i)%n]
+		if len(runs) > 0 && (runs[len(runs)-1] > 0) == on {
+			if on {
+				runs[len(runs)-1]++
+			} else {
+				runs[len(runs)-1]--
+			}
+			continue
+		}
+		if on {
+			runs = append(runs, 1)
+		} else {
+			runs = append(runs, -1)
+		}
+	}
+	return runs
+}
+
+// RandomLineDef holds a random line (ランダム線) definition.
+type RandomLineDef struct {
+	// Pattern is the line pattern.
+	Pattern uint32
+
+	// Amplitude is the screen amplitude.
+	Amplitude uint32
+
+	// Pitch is the screen pitch.
+	Pitch uint32
+
+	// PrinterAmplitude is the printer amplitude.
+	PrinterAmplitude uint32
+
+	// PrinterPitch is the printer pitch.
+	PrinterPitch uint32
+}
+
+// SXFLineType is an SXF extended line type definition.
+type SXFLineType struct {
+	LineTypeDef
+
+	// Name is the user-defined line type name.
+	Name string
+
+	// Segments is the number of used pitch values.
+	Segments uint32
+
+	// Pitches holds alternating segment and gap lengths.
+	Pitches [10]float64
+}
+
+// EyeSettings holds the 2.5D perspective, bird's-eye and isometric view settings.
+type EyeSettings struct {
+	// InitFlags records which views have an eye position set
+	// (ones: perspective, tens: bird's-eye, hundreds: isometric).
+	InitFlags uint32
+
+	// HorizontalAngles are the horizontal view angles × 100 for the three views.
+	HorizontalAngles [3]uint32
+
+	// PerspectiveHeight, PerspectiveDistance are the perspective eye height and distance.
+	PerspectiveHeight, PerspectiveDistance float64
+
+	// BirdsEyeHeight, BirdsEyeDistance are the bird's-eye eye height and distance.
+	BirdsEyeHeight, BirdsEyeDistance float64
+
+	// IsometricAngle is the isometric vertical view angle.
+	IsometricAngle float64
+}
+
+// TextStyle holds a text type preset (文字種).
+type TextStyle struct {
+	// Width is the character width.
+	Width float64
+
+	// Height is the character height.
+	Height float64
+
+	// Spacing is the character spacing.
+	Spacing float64
+
+	// Color is the pen color number.
+	Color uint32
 }
 
 // LayerGroup represents a layer group (レイヤグループ) in a JWW file.
@@ -47,7 +528,8 @@ type LayerGroup struct {
 	// Layers contains the 16 layers within this layer group.
 	Layers [16]Layer
 
-	// Name is the user-defined name of this layer group.
+	// Name is the user-defined name of this layer group (レイヤグループ名).
+	// Unnamed groups get a default name such as "Group0".
 	Name string
 }
 
@@ -61,7 +543,8 @@ type Layer struct {
 	// Protect is the protection flag to prevent accidental modifications.
 	Protect uint32
 
-	// Name is the user-defined name of this layer.
+	// Name is the user-defined name of this layer (レイヤ名).
+	// Unnamed layers get a default "G-L" name such as "0-0" or "F-A".
 	Name string
 }
 
@@ -86,8 +569,13 @@ type EntityBase struct {
 	// LayerGroup is the layer group number (0-15).
 	LayerGroup uint16
 
-	// Flag contains various attribute flags for the entity.
+	// Flag contains various attribute flags for the entity (属性フラグ). Their
+	// meaning depends on the entity type; see DecodeAttributes.
 	Flag uint16
+
+	// Attributes is the meaning of Flag for the entity type, decoded by the
+	// parser.
+	Attributes Attributes `json:",omitzero"`
 }
 
 // Entity is the interface implemented by all JWW drawing entities.
@@ -169,6 +657,7 @@ type Point struct {
 	IsTemporary bool
 
 	// Code specifies the point marker type (arrow, cross, circle, etc.).
+	// Marker points have a PenStyle of 100; they are stored from Ver.2.52.
 	Code uint32
 
 	// Angle is the rotation angle for directional point markers.
@@ -195,7 +684,8 @@ type Text struct {
 	// EndX is the X coordinate of the text's ending point (for text box).
 	EndX, EndY float64
 
-	// TextType contains text style flags: +10000 for italic, +20000 for bold.
+	// TextType is the text type number (文字種) plus style flags: +10000 for
+	// italic, +20000 for bold. See TextTypeNumber, IsItalic and IsBold.
 	TextType uint32
 
 	// SizeX is the character width.
@@ -220,6 +710,24 @@ func (t *Text) Base() *EntityBase { return &t.EntityBase }
 // Type returns "TEXT".
 func (t *Text) Type() string { return "TEXT" }
 
+// TextTypeNumber returns the text type (文字種) without the style flags:
+// 1-10 for the header presets (see Header.TextStyles), other values for
+// arbitrary sizes.
+func (t *Text) TextTypeNumber() uint32 { return t.TextType % 10000 }
+
+// IsItalic reports whether the text is drawn italic (斜体文字).
+// Jw_cad adds 10000 for italic and 20000 for bold when saving; the prose of
+// the format description has the two swapped, but its code does not.
+func (t *Text) IsItalic() bool { return (t.TextType/10000)&1 != 0 }
+
+// IsBold reports whether the text is drawn bold (ボールド体).
+func (t *Text) IsBold() bool { return (t.TextType/10000)&2 != 0 }
+
+// IsVertical reports whether the text is written vertically (縦字, flag
+// 0x0020): characters stay upright and run downwards, perpendicular to the
+// text angle.
+func (t *Text) IsVertical() bool { return t.Flag&0x0020 != 0 }
+
 // Solid represents a solid fill entity (JWW class: CDataSolid).
 // Solids are filled quadrilaterals or triangles used for hatching and shading.
 type Solid struct {
@@ -247,6 +755,222 @@ func (s *Solid) Base() *EntityBase { return &s.EntityBase }
 // Type returns "SOLID".
 func (s *Solid) Type() string { return "SOLID" }
 
+// CircleSolid represents a filled circular region (円ソリッド). It is stored as
+// a CDataSolid whose PenStyle is 101 or above, reusing the solid's point
+// fields for the circle or ellipse geometry.
+type CircleSolid struct {
+	EntityBase
+
+	// CenterX is the X coordinate of the center point.
+	CenterX, CenterY float64
+
+	// Radius is the outer radius (semi-major axis for ellipses).
+	Radius float64
+
+	// Flatness is the ratio of the minor to the major axis (1.0 for circles).
+	Flatness float64
+
+	// TiltAngle is the rotation of the major axis in radians.
+	TiltAngle float64
+
+	// StartAngle is the start angle in radians.
+	StartAngle float64
+
+	// ArcAngle is the angular extent in radians.
+	ArcAngle float64
+
+	// Param is the raw shape parameter. For circle solids (PenStyle 101) and
+	// circumference solids (PenStyle 111) it selects the shape; for ring
+	// solids (PenStyle 105/106) it is the inner radius.
+	Param float64
+
+	// Color is the RGB color value (used when PenColor == 10).
+	Color uint32
+}
+
+// CircleSolidKind identifies the shape of a CircleSolid.
+type CircleSolidKind int
+
+const (
+	// CircleSolidUnknown is an unrecognized PenStyle/Param combination.
+	CircleSolidUnknown CircleSolidKind = iota
+	// CircleSolidSector is a sector bounded by the arc and two radii (扇形ソリッド).
+	CircleSolidSector
+	// CircleSolidSegment is bounded by the arc and its chord (弓形ソリッド).
+	CircleSolidSegment
+	// CircleSolidDisc is a full circle or ellipse (全円ソリッド).
+	CircleSolidDisc
+	// CircleSolidOuterArc is the region between the arc and the intersection
+	// of its end tangents (外側円弧ソリッド).
+	CircleSolidOuterArc
+	// CircleSolidRing is a ring whose inner boundary is the outer one scaled
+	// down (円環ソリッド1).
+	CircleSolidRing
+	// CircleSolidRingOffset is a ring whose inner boundary is offset by a
+	// constant distance from the outer one (円環ソリッド2).
+	CircleSolidRingOffset
+	// CircleSolidCircumference is drawn along the circumference (円周ソリッド).
+	CircleSolidCircumference
+)
+
+// Kind decodes the shape from PenStyle and Param.
+func (c *CircleSolid) Kind() CircleSolidKind {
+	switch c.PenStyle {
+	case 101:
+		switch c.Param {
+		case -1:
+			return CircleSolidOuterArc
+		case 0:
+			return CircleSolidSector
+		case 5:
+			return CircleSolidSegment
+		case 100:
+			return CircleSolidDisc
+		}
+	case 105:
+		return CircleSolidRing
+	case 106:
+		return CircleSolidRingOffset
+	case 111:
+		return CircleSolidCircumference
+	}
+	return CircleSolidUnknown
+}
+
+// InnerRadius returns the inner radius of a ring solid, or 0 for other kinds.
+func (c *CircleSolid) InnerRadius() float64 {
+	if c.PenStyle == 105 || c.PenStyle == 106 {
+		return c.Param
+	}
+	return 0
+}
+
+// IsFullCircle reports whether the solid covers the full circumference.
+// Ring solids have no full-circle flag, so their arc angle is checked instead.
+func (c *CircleSolid) IsFullCircle() bool {
+	switch c.PenStyle {
+	case 101, 111:
+		return c.Param == 100
+	}
+	return math.Abs(c.ArcAngle) >= 2*math.Pi-1e-9
+}
+
+// Base returns the entity's base attributes.
+func (c *CircleSolid) Base() *EntityBase { return &c.EntityBase }
+
+// Type returns "CIRCLE_SOLID".
+func (c *CircleSolid) Type() string { return "CIRCLE_SOLID" }
+
+// Dimension represents a dimension entity (JWW class: CDataSunpou).
+// A dimension is composed of the dimension line and its value text. From
+// Ver.4.20 it also carries the SXF mode, two auxiliary (extension) lines and
+// the arrow and reference points.
+type Dimension struct {
+	EntityBase
+
+	// Line is the dimension line (寸法線).
+	Line Line
+
+	// Text is the dimension value text (寸法値), e.g. "3,640".
+	Text Text
+
+	// SXFMode is the SXF dimension mode (Ver.4.20 and later).
+	SXFMode uint16
+
+	// AuxLines are the auxiliary (extension) lines 1 and 2 (補助線, Ver.4.20 and later).
+	AuxLines [2]Line
+
+	// ArrowPoints are the arrow (end) points 1 and 2 (矢印, Ver.4.20 and later).
+	ArrowPoints [2]Point
+
+	// RefPoints are the reference points 1 and 2 (基準点, Ver.4.20 and later).
+	RefPoints [2]Point
+}
+
+// Base returns the entity's base attributes.
+func (d *Dimension) Base() *EntityBase { return &d.EntityBase }
+
+// Type returns "DIMENSION".
+func (d *Dimension) Type() string { return "DIMENSION" }
+
+// ImageRef represents a placed image (画像). Jw_cad stores images as text
+// entities (JWW class: CDataMoji) whose content starts with "^@BM", followed by
+// the image path, the drawing size and optional trimming parameters:
+//
+//	^@BM%temp%photo.jpg,100,75,0,0,1,1
+//
+// The image is placed with its lower-left corner at the text start point and
+// rotated by the text angle.
+type ImageRef struct {
+	EntityBase
+
+	// X is the X coordinate of the lower-left corner.
+	X, Y float64
+
+	// Width is the drawn width of the image in drawing units.
+	Width, Height float64
+
+	// Angle is the rotation angle in degrees.
+	Angle float64
+
+	// Path is the referenced file path as stored, including any "%temp%"
+	// prefix used for bundled images.
+	Path string
+
+	// TrimX and TrimY are the start of the visible region as ratios of the
+	// full image size (0 when not trimmed).
+	TrimX, TrimY float64
+
+	// TrimWidth and TrimHeight are the size of the visible region as ratios
+	// of the full image size (1 when not trimmed).
+	TrimWidth, TrimHeight float64
+
+	// Source is the original "^@BM" text content.
+	Source string
+}
+
+// Base returns the entity's base attributes.
+func (i *ImageRef) Base() *EntityBase { return &i.EntityBase }
+
+// Type returns "IMAGE".
+func (i *ImageRef) Type() string { return "IMAGE" }
+
+// IsEmbedded reports whether the path refers to an image bundled in the file.
+func (i *ImageRef) IsEmbedded() bool {
+	return strings.HasPrefix(strings.ToLower(i.Path), "%temp%")
+}
+
+// FileName returns the base name of the referenced file.
+func (i *ImageRef) FileName() string {
+	return imageBaseName(i.Path)
+}
+
+// Image is an image file bundled with a JWW file (同梱画像ファイル, Ver.7.00
+// and later). Files are usually zlib compressed, in which case the stored name
+// has a ".gz" suffix appended (e.g. "photo.jpg.gz").
+type Image struct {
+	// Name is the stored file name.
+	Name string
+
+	// Data is the stored file content, compressed when Name ends in ".gz".
+	Data []byte
+}
+
+// IsCompressed reports whether the stored data is compressed.
+func (img *Image) IsCompressed() bool {
+	return strings.HasSuffix(strings.ToLower(img.Name), ".gz")
+}
+
+// FileName returns the base name of the original image file, without any
+// directory and without the ".gz" compression suffix.
+func (img *Image) FileName() string {
+	name := imageBaseName(img.Name)
+	if img.IsCompressed() {
+		name = name[:len(name)-3]
+	}
+	return name
+}
+
 // Block represents a block insert entity (JWW class: CDataBlock).
 // Blocks allow reuse of geometry defined in a BlockDef.
 type Block struct {
@@ -286,9 +1010,61 @@ type BlockDef struct {
 	// IsReferenced indicates whether this block is used by any Block entity.
 	IsReferenced bool
 
-	// Name is the user-defined name of this block.
+	// Created is the time the block was defined (定義された時間), or the zero
+	// time when it is not recorded.
+	Created time.Time
+
+	// Name is the user-defined name of this block, without the compound
+	// figure suffix stored from Ver.4.10 (see Kind). Names of older files
+	// are kept as stored.
 	Name string
 
+	// Kind is the compound figure type (複合図形種別) stored after the name
+	// from Ver.4.10.
+	Kind BlockKind
+
 	// Entities contains the drawing entities that comprise this block.
 	Entities []Entity
 }
+
+// BlockKind is the compound figure type (複合図形種別) of a block definition.
+// Jw_cad Ver.4.10 and later append it to the block name after
+// "@@SfigorgFlag@@".
+type BlockKind int
+
+const (
+	// BlockKindBlock is a plain block (ブロック) without a compound figure type.
+	BlockKindBlock BlockKind = iota
+
+	// BlockKindPartialMath is a partial figure in math coordinates
+	// (部分図(数学座標系)).
+	BlockKindPartialMath
+
+	// BlockKindPartialSurvey is a partial figure in survey coordinates
+	// (部分図(測地座標系)), whose X axis points north and Y axis east.
+	BlockKindPartialSurvey
+
+	// BlockKindDrawingGroup is a drawing group (作図グループ).
+	BlockKindDrawingGroup
+
+	// BlockKindDrawingPart is a drawing part (作図部品).
+	BlockKindDrawingPart
+)
+
+// String returns a description of the kind, e.g. "partial figure (survey
+// coordinates)".
+func (k BlockKind) String() string {
+	switch k {
+	case BlockKindBlock:
+		return "block"
+	case BlockKindPartialMath:
+		return "partial figure (math coordinates)"
+	case BlockKindPartialSurvey:
+		return "partial figure (survey coordinates)"
+	case BlockKindDrawingGroup:
+		return "drawing group"
+	case BlockKindDrawingPart:
+		return "drawing part"
+	}
+	return fmt.Sprintf("compound figure %d", int(k))
+}
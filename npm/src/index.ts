@@ -183,6 +183,26 @@ export interface JwwDocument {
   Entities: JwwEntity[];
   /** Block definitions */
   Blocks: JwwBlock[];
+  /** Problems that did not stop parsing */
+  Warnings?: JwwWarning[];
+}
+
+/**
+ * Problem found while parsing: skipped damaged data or suspicious data
+ */
+export interface JwwWarning {
+  /** Byte offset from the start of the file */
+  Offset: number;
+  /** File section: "header", "entity list", "block definitions" or "images" */
+  Section: string;
+  /** Index of the block definition, or -1 */
+  BlockDef: number;
+  /** Index of the entity or image within its list, or -1 */
+  Index: number;
+  /** MFC class name of the object */
+  Class?: string;
+  /** Description of the problem */
+  Reason: string;
 }
 
 /**
@@ -225,6 +245,41 @@ export interface JwwEntityBase {
   Layer: number;
   /** Layer group index (0-15) */
   LayerGroup: number;
+  /** Raw attribute flags (meaning depends on the entity type) */
+  Flag: number;
+  /** Decoded attribute flags; omitted when none are set */
+  Attributes?: JwwAttributes;
+}
+
+/**
+ * Decoded JWW attribute flags. Only the flags that are set are present.
+ */
+export interface JwwAttributes {
+  /** Belongs to a figure (図形) */
+  Figure?: boolean;
+  /** Drawn by hatching (ハッチ) */
+  Hatch?: boolean;
+  /** Belongs to a dimension (寸法) */
+  DimensionPart?: boolean;
+  /** Belongs to a fitting (建具) */
+  Fitting?: boolean;
+  /** Fitting line excluded from envelope processing */
+  EnvelopeExcluded?: boolean;
+  /** Vertically written text (縦字) */
+  Vertical?: boolean;
+  /** Text selected as figure attribute (図形属性選択) */
+  FigureAttribute?: boolean;
+  /** 2.5D text */
+  Is25D?: boolean;
+  /** Role of a text */
+  TextRole?:
+    | "dimension-value"
+    | "radius-value"
+    | "diameter-value"
+    | "angle-value"
+    | "cumulative-value"
+    | "true-north"
+    | "shadow";
 }
 
 /**
@@ -338,8 +393,16 @@ export type JwwEntity =
  * JWW block definition
  */
 export interface JwwBlock {
-  /** Block name */
+  /** Definition number referenced by block entities */
+  Number: number;
+  /** Whether the definition is referenced */
+  IsReferenced: boolean;
+  /** Creation time (RFC 3339) */
+  Created: string;
+  /** Block name, without the compound figure marker */
   Name: string;
+  /** Compound figure kind (0: block, 1-2: partial figure, 3: drawing group, 4: drawing part) */
+  Kind: number;
   /** Entities within the block */
   Entities: JwwEntity[];
 }
@@ -354,6 +417,8 @@ export interface JwwBlock {
 export interface DxfDocument {
   /** DXF layers */
   Layers: DxfLayer[];
+  /** Dimension styles besides STANDARD */
+  DimStyles?: DxfDimStyle[];
   /** All entities in the document */
   Entities: DxfEntity[];
   /** Block definitions */
@@ -368,6 +433,8 @@ export interface DxfLayer {
   Name: string;
   /** ACI color code (1-255) */
   Color: number;
+  /** Line weight in 1/100 mm (0 = default) */
+  LineWeight?: number;
   /** Whether the layer is frozen */
   Frozen: boolean;
   /** Whether the layer is locked */
@@ -384,8 +451,12 @@ export interface DxfEntityBase {
   Layer: string;
   /** Optional ACI color code */
   Color?: number;
+  /** Optional 24-bit RGB true color (0xRRGGBB), null when unset */
+  TrueColor?: number | null;
   /** Optional line type name */
   LineType?: string;
+  /** Optional line weight in 1/100 mm (0 = by layer) */
+  LineWeight?: number;
 }
 
 /**
@@ -498,6 +569,10 @@ export interface DxfText extends DxfEntityBase {
   Rotation?: number;
   /** Text style name */
   Style?: string;
+  /** Relative character width (1 = default) */
+  WidthFactor?: number;
+  /** Character slant in degrees */
+  ObliqueAngle?: number;
 }
 
 /**
@@ -519,6 +594,76 @@ export interface DxfMText extends DxfEntityBase {
   Rotation?: number;
   /** Reference rectangle width */
   Width?: number;
+  /** Attachment point (1-9: top-left ... bottom-right) */
+  AttachmentPoint?: number;
+  /** Drawing direction (1: left to right, 3: top to bottom, 5: by style) */
+  DrawingDirection?: number;
+  /** Line spacing factor */
+  LineSpacing?: number;
+  /** Text style name */
+  Style?: string;
+}
+
+/**
+ * DXF DIMENSION entity. Its graphics are stored in an anonymous "*D" block.
+ */
+export interface DxfDimension extends DxfEntityBase {
+  Type: "DIMENSION";
+  /** 0: linear, 1: aligned, 2: angular, 3: diameter, 4: radius */
+  DimType: number;
+  /** Anonymous block name (assigned when writing if empty) */
+  BlockName?: string;
+  /** Entities drawn for the dimension */
+  Geometry?: DxfEntity[];
+  /** Dimension style name */
+  Style?: string;
+  /** Main definition point */
+  DefX: number;
+  DefY: number;
+  /** Middle point of the dimension text */
+  TextX: number;
+  TextY: number;
+  /** Definition points 13-16; meaning depends on DimType */
+  X1?: number;
+  Y1?: number;
+  X2?: number;
+  Y2?: number;
+  X3?: number;
+  Y3?: number;
+  X4?: number;
+  Y4?: number;
+  /** Dimension line angle of linear dimensions in degrees */
+  Rotation?: number;
+  /** Text rotation in degrees */
+  TextRotation?: number;
+  /** Measured length, or angle in degrees */
+  Measurement: number;
+  /** Text override ("" shows the measurement) */
+  Text?: string;
+}
+
+/**
+ * DXF dimension style (DIMSTYLE table entry)
+ */
+export interface DxfDimStyle {
+  Name: string;
+  Scale?: number;
+  ArrowSize?: number;
+  ArrowBlock?: string;
+  ExtLineOffset?: number;
+  ExtLineExtension?: number;
+  TextHeight?: number;
+  TextGap?: number;
+  TextAbove?: boolean;
+  LinearFactor?: number;
+  Suffix?: string;
+  Decimals?: number;
+  SuppressTrailingZeros?: boolean;
+  AngleUnit?: number;
+  AngleDecimals?: number;
+  LineColor?: number;
+  ExtLineColor?: number;
+  TextColor?: number;
 }
 
 /**
@@ -544,6 +689,85 @@ export interface DxfSolid extends DxfEntityBase {
   Y4: number;
 }
 
+/**
+ * DXF HATCH pattern definition line
+ */
+export interface DxfHatchPatternLine {
+  /** Line angle in degrees */
+  Angle: number;
+  /** Base point X */
+  BaseX: number;
+  /** Base point Y */
+  BaseY: number;
+  /** Offset to the next parallel line X */
+  OffsetX: number;
+  /** Offset to the next parallel line Y */
+  OffsetY: number;
+  /** Dash lengths (positive: dash, negative: gap); empty for continuous lines */
+  Dashes?: number[];
+}
+
+/**
+ * DXF HATCH boundary edge
+ */
+export interface DxfHatchEdge {
+  /** 1: line, 2: circular arc, 3: elliptical arc */
+  Type: number;
+  /** Line start X */
+  X1?: number;
+  /** Line start Y */
+  Y1?: number;
+  /** Line end X */
+  X2?: number;
+  /** Line end Y */
+  Y2?: number;
+  /** Arc or ellipse center X */
+  CenterX?: number;
+  /** Arc or ellipse center Y */
+  CenterY?: number;
+  /** Arc radius */
+  Radius?: number;
+  /** Ellipse major axis X component (relative to center) */
+  MajorAxisX?: number;
+  /** Ellipse major axis Y component (relative to center) */
+  MajorAxisY?: number;
+  /** Ellipse minor to major axis ratio */
+  MinorRatio?: number;
+  /** Start angle (arc) or parameter (ellipse) in degrees */
+  StartAngle?: number;
+  /** End angle (arc) or parameter (ellipse) in degrees */
+  EndAngle?: number;
+  /** Whether the curve runs clockwise */
+  Clockwise?: boolean;
+}
+
+/**
+ * DXF HATCH boundary loop: a polyline (Vertices) or a chain of edges (Edges)
+ */
+export interface DxfHatchBoundary {
+  /** Polyline vertices */
+  Vertices?: DxfVertex[] | null;
+  /** Boundary edges */
+  Edges?: DxfHatchEdge[] | null;
+}
+
+/**
+ * DXF HATCH entity (solid or pattern filled region)
+ */
+export interface DxfHatch extends DxfEntityBase {
+  Type: "HATCH";
+  /** Pattern name ("SOLID" for solid fill) */
+  Pattern?: string;
+  /** Pattern angle in degrees */
+  PatternAngle?: number;
+  /** Pattern scale */
+  PatternScale?: number;
+  /** Pattern definition lines */
+  PatternLines?: DxfHatchPatternLine[] | null;
+  /** Boundary loops; holes run opposite to the enclosing loop */
+  Boundaries: DxfHatchBoundary[];
+}
+
 /**
  * DXF INSERT entity (block reference)
  */
@@ -603,7 +827,9 @@ export type DxfEntity =
   | DxfPoint
   | DxfText
   | DxfMText
+  | DxfDimension
   | DxfSolid
+  | DxfHatch
   | DxfInsert
   | DxfLwPolyline
   | (DxfEntityBase & Record<string, unknown>); // Allow for unknown entity types
@@ -614,6 +840,8 @@ export type DxfEntity =
 export interface DxfBlock {
   /** Block name */
   Name: string;
+  /** Block description, e.g. the Jw_cad compound figure kind */
+  Description?: string;
   /** Base point X coordinate */
   BaseX?: number;
   /** Base point Y coordinate */
@@ -637,7 +865,9 @@ export type ProgressStage =
   | "parsing_layers"
   | "parsing_entities"
   | "parsing_blocks"
+  | "parsing_images"
   | "converting"
+  | "writing"
   | "complete";
 
 /**
@@ -934,6 +1164,22 @@ interface WasmResult {
   section?: string;
 }
 
+/**
+ * Progress reported by the WASM module during parsing, conversion and
+ * writing
+ * @internal
+ */
+interface WasmProgress {
+  stage: "header" | "entities" | "blocks" | "images" | "converting" | "writing";
+  /** Bytes of the JWW file read, or of the DXF file written */
+  bytes: number;
+  /** Size of the JWW file, or 0 when unknown */
+  totalBytes: number;
+  entities: number;
+  /** Entities of the stage, or 0 when unknown */
+  totalEntities: number;
+}
+
 /**
  * Validation result from WASM
  * @internal
@@ -958,14 +1204,77 @@ interface WasmValidationResult {
 
 declare global {
   var Go: new () => GoInstance;
-  var jwwParse: ((data: Uint8Array) => WasmResult) | undefined;
-  var jwwToDxf: ((data: Uint8Array) => WasmResult) | undefined;
-  var jwwToDxfString: ((data: Uint8Array) => WasmResult) | undefined;
+  var jwwParse:
+    | ((data: Uint8Array, onProgress?: (progress: WasmProgress) => void) => WasmResult)
+    | undefined;
+  var jwwToDxf:
+    | ((data: Uint8Array, onProgress?: (progress: WasmProgress) => void) => WasmResult)
+    | undefined;
+  var jwwToDxfString:
+    | ((data: Uint8Array, onProgress?: (progress: WasmProgress) => void) => WasmResult)
+    | undefined;
   var jwwValidate: ((data: Uint8Array) => WasmValidationResult) | undefined;
   var jwwGetVersion: (() => string) | undefined;
   var jwwSetDebug: ((enabled: boolean) => void) | undefined;
 }
 
+/**
+ * Stages reported by the WASM module
+ */
+const WASM_PROGRESS_STAGES: Record<WasmProgress["stage"], ProgressStage> = {
+  header: "parsing_header",
+  entities: "parsing_entities",
+  blocks: "parsing_blocks",
+  images: "parsing_images",
+  converting: "converting",
+  writing: "writing",
+};
+
+/**
+ * Shares of the overall progress (summing to 100) taken by parsing,
+ * conversion and writing
+ */
+interface ProgressShares {
+  parse: number;
+  converting?: number;
+  writing?: number;
+}
+
+/**
+ * Creates the callback passed to the WASM module, reporting its progress to
+ * onProgress. Parsing progresses with the bytes read, conversion and writing
+ * with the entities converted and written.
+ */
+function wasmProgressCallback(
+  onProgress: ProgressCallback | undefined,
+  startTime: number,
+  shares: ProgressShares
+): ((progress: WasmProgress) => void) | undefined {
+  if (!onProgress) {
+    return undefined;
+  }
+  return (p) => {
+    let progress: number;
+    let before = 0;
+    let share = shares.parse;
+    if (p.stage === "converting" || p.stage === "writing") {
+      progress = p.totalEntities > 0 ? (p.entities / p.totalEntities) * 100 : 0;
+      before = shares.parse + (p.stage === "writing" ? shares.converting ?? 0 : 0);
+      share = shares[p.stage] ?? 0;
+    } else {
+      progress = p.totalBytes > 0 ? (p.bytes / p.totalBytes) * 100 : 0;
+    }
+    onProgress({
+      stage: WASM_PROGRESS_STAGES[p.stage],
+      progress,
+      overallProgress: before + (share * progress) / 100,
+      entitiesProcessed: p.entities,
+      totalEntities: p.totalEntities || undefined,
+      elapsedMs: Date.now() - startTime,
+    });
+  };
+}
+
 interface GoInstance {
   importObject: WebAssembly.Imports;
   run(instance: WebAssembly.Instance): Promise<void>;
@@ -1452,7 +1761,10 @@ export class JwwParser {
     }
 
     try {
-      const result = globalThis.jwwParse!(data);
+      const result = globalThis.jwwParse!(
+        data,
+        wasmProgressCallback(options?.onProgress, startTime, { parse: 100 })
+      );
 
       if (!result.ok) {
         this.stats.errorCount++;
@@ -1576,7 +1888,10 @@ export class JwwParser {
     }
 
     try {
-      const result = globalThis.jwwToDxf!(data);
+      const result = globalThis.jwwToDxf!(
+        data,
+        wasmProgressCallback(options?.onProgress, startTime, { parse: 70, converting: 30 })
+      );
 
       if (!result.ok) {
         this.stats.errorCount++;
@@ -1637,13 +1952,30 @@ export class JwwParser {
     this.log("info", "Starting DXF string generation", { dataSize: data.length });
 
     try {
-      const result = globalThis.jwwToDxfString!(data);
+      const result = globalThis.jwwToDxfString!(
+        data,
+        wasmProgressCallback(options?.onProgress, startTime, {
+          parse: 60,
+          converting: 20,
+          writing: 20,
+        })
+      );
 
       if (!result.ok) {
         this.stats.errorCount++;
         throw createParseError(result);
       }
 
+      if (options?.onProgress) {
+        options.onProgress({
+          stage: "complete",
+          progress: 100,
+          overallProgress: 100,
+          message: "DXF generation complete",
+          elapsedMs: Date.now() - startTime,
+        });
+      }
+
       const parseTime = Date.now() - startTime;
       this.stats.parseCount++;
       this.stats.totalBytesProcessed += data.length;
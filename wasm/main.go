@@ -6,6 +6,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"syscall/js"
 
 	"github.com/f4ah6o/jww-parser/dxf"
@@ -71,8 +72,56 @@ func logDebug(format string, args ...interface{}) {
 	}
 }
 
+// progressFunc returns a progress function calling the JavaScript function
+// args[i] with { stage, bytes, totalBytes, entities, totalEntities }, or nil
+// when no function is given.
+//
+// Calls are throttled to the first report of each stage and one per percent
+// of the stage's entities or bytes. Exceptions thrown by the function are
+// caught so they do not abort the parse; a function that throws is not
+// called again.
+func progressFunc(args []js.Value, i int) jww.ProgressFunc {
+	if len(args) <= i || args[i].Type() != js.TypeFunction {
+		return nil
+	}
+	fn := args[i]
+	var last jww.ProgressStage
+	lastPercent := -1
+	failed := false
+	return func(p jww.Progress) {
+		percent := -1
+		switch {
+		case p.TotalEntities > 0:
+			percent = p.Entities * 100 / p.TotalEntities
+		case p.TotalBytes > 0:
+			percent = int(p.Bytes * 100 / p.TotalBytes)
+		}
+		if failed || (p.Stage == last && percent >= 0 && percent == lastPercent) {
+			return
+		}
+		last, lastPercent = p.Stage, percent
+
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(js.Error); !ok {
+					panic(r)
+				}
+				failed = true
+				logDebug("Progress callback failed: %v", r.(js.Error).Error())
+			}
+		}()
+		fn.Invoke(map[string]interface{}{
+			"stage":         p.Stage.String(),
+			"bytes":         p.Bytes,
+			"totalBytes":    p.TotalBytes,
+			"entities":      p.Entities,
+			"totalEntities": p.TotalEntities,
+		})
+	}
+}
+
 // jwwParse parses JWW binary data and returns JSON representation.
-// JS: jwwParse(Uint8Array) -> { ok: boolean, data?: string, error?: string }
+// JS: jwwParse(Uint8Array, onProgress?: (progress) => void) -> { ok: boolean, data?: string, error?: string }
 func jwwParse(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
 		return makeError("jwwParse requires 1 argument: Uint8Array")
@@ -85,7 +134,8 @@ func jwwParse(this js.Value, args []js.Value) interface{} {
 	logDebug("Received %d bytes", len(data))
 
 	// Parse JWW data
-	doc, err := jww.Parse(bytes.NewReader(data))
+	progress := progressFunc(args, 1)
+	doc, err := jww.ParseWithOptions(bytes.NewReader(data), jww.ParseOptions{Progress: progress})
 	if err != nil {
 		logDebug("Parse error: %v", err.Error())
 		return makeError("parse error: " + err.Error())
@@ -105,7 +155,7 @@ func jwwParse(this js.Value, args []js.Value) interface{} {
 }
 
 // jwwToDxf parses JWW binary data and returns DXF object as JSON.
-// JS: jwwToDxf(Uint8Array) -> { ok: boolean, data?: string, error?: string }
+// JS: jwwToDxf(Uint8Array, onProgress?: (progress) => void) -> { ok: boolean, data?: string, error?: string }
 func jwwToDxf(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
 		return makeError("jwwToDxf requires 1 argument: Uint8Array")
@@ -118,7 +168,8 @@ func jwwToDxf(this js.Value, args []js.Value) interface{} {
 	logDebug("Received %d bytes", len(data))
 
 	// Parse JWW data
-	jwwDoc, err := jww.Parse(bytes.NewReader(data))
+	progress := progressFunc(args, 1)
+	jwwDoc, err := jww.ParseWithOptions(bytes.NewReader(data), jww.ParseOptions{Progress: progress})
 	if err != nil {
 		logDebug("Parse error: %v", err.Error())
 		return makeError("parse error: " + err.Error())
@@ -127,7 +178,7 @@ func jwwToDxf(this js.Value, args []js.Value) interface{} {
 	logDebug("Parsed JWW document with %d entities", len(jwwDoc.Entities))
 
 	// Convert to DXF
-	dxfDoc := dxf.ConvertDocument(jwwDoc)
+	dxfDoc := dxf.ConvertDocument(jwwDoc, dxf.WithProgress(progress))
 	logDebug("Converted to DXF with %d entities", len(dxfDoc.Entities))
 
 	// Convert to JSON
@@ -142,7 +193,7 @@ func jwwToDxf(this js.Value, args []js.Value) interface{} {
 }
 
 // jwwToDxfString parses JWW binary data and returns DXF file content as string.
-// JS: jwwToDxfString(Uint8Array) -> { ok: boolean, data?: string, error?: string }
+// JS: jwwToDxfString(Uint8Array, onProgress?: (progress) => void) -> { ok: boolean, data?: string, error?: string }
 func jwwToDxfString(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
 		return makeError("jwwToDxfString requires 1 argument: Uint8Array")
@@ -155,7 +206,8 @@ func jwwToDxfString(this js.Value, args []js.Value) interface{} {
 	logDebug("Received %d bytes", len(data))
 
 	// Parse JWW data
-	jwwDoc, err := jww.Parse(bytes.NewReader(data))
+	progress := progressFunc(args, 1)
+	jwwDoc, err := jww.ParseWithOptions(bytes.NewReader(data), jww.ParseOptions{Progress: progress})
 	if err != nil {
 		logDebug("Parse error: %v", err.Error())
 		return makeError("parse error: " + err.Error())
@@ -164,11 +216,17 @@ func jwwToDxfString(this js.Value, args []js.Value) interface{} {
 	logDebug("Parsed JWW document with %d entities", len(jwwDoc.Entities))
 
 	// Convert to DXF
-	dxfDoc := dxf.ConvertDocument(jwwDoc)
+	dxfDoc := dxf.ConvertDocument(jwwDoc, dxf.WithProgress(progress))
 	logDebug("Converted to DXF with %d entities", len(dxfDoc.Entities))
 
 	// Convert to DXF string
-	dxfString := dxf.ToString(dxfDoc)
+	var sb strings.Builder
+	w := dxf.NewWriter(&sb)
+	w.SetProgress(progress)
+	if err := w.WriteDocument(dxfDoc); err != nil {
+		return makeError("DXF write error: " + err.Error())
+	}
+	dxfString := sb.String()
 	logDebug("Generated %d bytes of DXF string", len(dxfString))
 
 	return makeResult(dxfString)